@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/security"
+	"wipedisk_enterprise/internal/system"
+	"wipedisk_enterprise/internal/wipe"
+)
+
+// defaultConfigPath - файл, в который --save записывает профиль без --config
+const defaultConfigPath = "config.yaml"
+
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark [диски]",
+	Short: "Замерить скорость записи и подобрать параметры профиля",
+	Long: "Замер последовательной записи и задержки fsync на томах при разных размерах блока, периодах fsync, " +
+		"с кэшем ОС и в обход него (прямой ввод-вывод). Выводит рекомендуемый профиль; с --save записывает " +
+		"подобранные параметры в секцию wipe.tuned конфигурации - они применяются с --profile tuned. " +
+		"Каждое сочетание параметров записывает на том --size МБ, ограничение скорости на замер не действует.",
+	Example: `  wipedisk benchmark D:
+  wipedisk benchmark /data --size 512 --save`,
+	RunE: runBenchmark,
+}
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+
+	benchmarkCmd.Flags().Int("size", 256, "Объем записи одного замера, МБ")
+	benchmarkCmd.Flags().IntSlice("chunk-sizes", nil, "Размеры блока записи, МБ (по умолчанию 1,4,16,32,64)")
+	benchmarkCmd.Flags().IntSlice("sync-intervals", nil, "Периоды fsync, МБ (по умолчанию 16,64,256)")
+	benchmarkCmd.Flags().Bool("save", false, "Записать подобранные параметры в конфигурацию как профиль tuned")
+}
+
+func runBenchmark(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+	logger, err = logging.NewEnterpriseLogger(cfg, verbose)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации логгера: %w", err)
+	}
+	defer logger.Close()
+
+	size, _ := cmd.Flags().GetInt("size")
+	chunkMB, _ := cmd.Flags().GetIntSlice("chunk-sizes")
+	syncMB, _ := cmd.Flags().GetIntSlice("sync-intervals")
+	save, _ := cmd.Flags().GetBool("save")
+	if size <= 0 {
+		return fmt.Errorf("объем замера должен быть положительным")
+	}
+
+	opts := wipe.BenchmarkOptions{SampleSize: uint64(size) * 1024 * 1024}
+	// Файл замера подписывается ключом каталога состояния, чтобы его нашел recover
+	if opts.Marker, err = wipe.LoadArtifactMarker(cfg.Wipe.StateDir); err != nil {
+		logger.Log("WARN", "Ключ маркеров недоступен, файл замера не подписывается", "error", err.Error())
+	}
+	for _, mb := range chunkMB {
+		if mb <= 0 || mb > 100 {
+			return fmt.Errorf("размер блока должен быть от 1 до 100 МБ, получено %d", mb)
+		}
+		opts.ChunkSizes = append(opts.ChunkSizes, mb*1024*1024)
+	}
+	for _, mb := range syncMB {
+		if mb <= 0 {
+			return fmt.Errorf("период fsync должен быть положительным, получено %d", mb)
+		}
+		opts.SyncIntervals = append(opts.SyncIntervals, uint64(mb)*1024*1024)
+	}
+
+	disks, err := system.GetDiskInfo(verbose)
+	if err != nil {
+		return fmt.Errorf("ошибка получения информации о дисках: %w", err)
+	}
+	var targets []system.DiskInfo
+	for _, disk := range disks {
+		if len(args) == 0 {
+			if !security.ShouldSkipDisk(cfg, disk) {
+				targets = append(targets, disk)
+			}
+			continue
+		}
+		for _, arg := range args {
+			if strings.EqualFold(disk.Letter, arg) || strings.EqualFold(disk.Letter+":", arg) {
+				targets = append(targets, disk)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("нет дисков для замера")
+	}
+
+	if dryRun {
+		for _, disk := range targets {
+			fmt.Printf("DRY RUN: замер %s (%s, %.1f GB свободно)\n", disk.Letter, disk.Type, float64(disk.FreeSize)/(1024*1024*1024))
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		if sig, ok := <-sigChan; ok {
+			logger.Log("WARN", "Получен сигнал, замер прерван", "signal", sig.String())
+			cancel()
+		}
+	}()
+
+	var results []*wipe.BenchmarkResult
+	for _, disk := range targets {
+		fmt.Printf("\nЗамер %s (%s, %d МБ на сочетание):\n", disk.Letter, disk.Type, size)
+		fmt.Printf("  %-9s %8s %8s %10s %12s %12s\n", "I/O", "Блок", "fsync", "MB/s", "fsync ср.", "fsync макс.")
+
+		opts.OnSample = func(s wipe.BenchmarkSample) {
+			fmt.Printf("  %-9s %6dMB %6dMB %10.1f %10.1fms %10.1fms\n", ioModeName(s.DirectIO),
+				s.ChunkSize>>20, s.SyncInterval>>20, s.ThroughputMBps,
+				float64(s.SyncAvg.Microseconds())/1000, float64(s.SyncMax.Microseconds())/1000)
+		}
+		result, err := wipe.Benchmark(ctx, disk, opts, logger)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", disk.Letter, err)
+			if ctx.Err() != nil {
+				return fmt.Errorf("замер прерван")
+			}
+			continue
+		}
+		results = append(results, result)
+
+		if result.DirectIOUnsupported {
+			fmt.Println("  Прямой ввод-вывод не поддерживается файловой системой")
+		}
+		best := result.Best
+		fmt.Printf("✓ Рекомендуемый профиль: %s\n", result.Profile)
+		fmt.Printf("  Подобрано: блок %d МБ, fsync каждые %d МБ, %s, %.1f MB/s\n",
+			best.ChunkSize>>20, best.SyncInterval>>20, ioModeName(best.DirectIO), best.ThroughputMBps)
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("замер не выполнен ни на одном диске")
+	}
+	if !save {
+		fmt.Printf("\nДля сохранения параметров как профиля %s: wipedisk benchmark --save\n", config.TunedProfile)
+		return nil
+	}
+
+	// Профиль общий для всех томов: берется замер самого медленного тома
+	slowest := results[0]
+	for _, r := range results[1:] {
+		if r.Best.ThroughputMBps < slowest.Best.ThroughputMBps {
+			slowest = r
+		}
+	}
+	path := configPath
+	if path == "" {
+		path = defaultConfigPath
+	}
+	if err := config.SaveTuned(path, slowest.Tuned()); err != nil {
+		return fmt.Errorf("ошибка сохранения профиля: %w", err)
+	}
+	logger.Log("INFO", "Профиль tuned сохранен", "config", path, "volume", slowest.Volume,
+		"chunk", slowest.Best.ChunkSize, "sync_interval", slowest.Best.SyncInterval, "direct_io", slowest.Best.DirectIO)
+	fmt.Printf("\nПрофиль %s (по замеру %s) записан в %s; применение: --profile %s\n",
+		config.TunedProfile, slowest.Volume, path, config.TunedProfile)
+	return nil
+}
+
+// ioModeName описывает режим ввода-вывода замера
+func ioModeName(direct bool) string {
+	if direct {
+		return "direct"
+	}
+	return "buffered"
+}
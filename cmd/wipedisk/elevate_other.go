@@ -0,0 +1,10 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+// relaunchElevated не поддерживается вне Windows: права проверяются через system.IsAdmin()
+func relaunchElevated(verb, exePath, args, cwd string) error {
+	return fmt.Errorf("перезапуск с повышением прав поддерживается только в Windows")
+}
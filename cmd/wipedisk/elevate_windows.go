@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+// relaunchElevated перезапускает программу через ShellExecute (запрос UAC)
+func relaunchElevated(verb, exePath, args, cwd string) error {
+	return windows.ShellExecute(0,
+		windows.StringToUTF16Ptr(verb),
+		windows.StringToUTF16Ptr(exePath),
+		windows.StringToUTF16Ptr(args),
+		windows.StringToUTF16Ptr(cwd),
+		windows.SW_NORMAL)
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sys/windows"
 
 	"wipedisk_enterprise/internal/app"
 	"wipedisk_enterprise/internal/cli"
@@ -62,9 +61,15 @@ var rootCmd = &cobra.Command{
 }
 
 var wipeCmd = &cobra.Command{
-	Use:   "wipe [диски]",
+	Use:   "wipe [диски или каталоги]",
 	Short: "Затереть свободное место на дисках",
-	RunE:  runWipe,
+	Long: "Затирание свободного места на локальных дисках. Вместо диска можно указать точку монтирования, " +
+		"смонтированную папку или каталог внутри тома: файлы затирания создаются только в нем, объем " +
+		"ограничен местом, доступным пользователю (квоты, резерв root). С --device затирается целиком блочное устройство " +
+		"или файл образа (диапазон задается --offset/--length); смонтированные устройства, swap и члены " +
+		"активных массивов md/LVM не затираются. В Linux и macOS запись приостанавливается сигналом " +
+		"SIGUSR1 и возобновляется SIGUSR2; время паузы не входит в --max-duration.",
+	RunE: runWipe,
 }
 
 var cleanCmd = &cobra.Command{
@@ -109,12 +114,42 @@ var reportsCmd = &cobra.Command{
 	RunE:  runReports,
 }
 
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Удалить файлы, оставшиеся от прерванного затирания",
+	Long:  "Поиск на всех томах файлов затирания (wipe_NNN.tmp, wipe_bench_NNN.tmp, cipher_NNN_*.tmp, .wipedisk_tmp, .wipedisk_meta), оставшихся после аварийного завершения. Удаляются только файлы с подписанным заголовком-маркером WipeDisk.",
+	RunE:  runRecover,
+}
+
+var shredCmd = &cobra.Command{
+	Use:   "shred <пути...>",
+	Short: "Безвозвратно удалить файлы и каталоги",
+	Long: "Перезапись содержимого файлов на месте по схеме метода затирания (fsync после каждого прохода), " +
+		"обрезка, многократное переименование в случайные имена и удаление. Пути могут содержать маски (*.log). " +
+		"Пути внутри security.protected_paths не обрабатываются. " +
+		"На SSD и файловых системах с копированием при записи перезапись на месте не гарантирует затирание старых блоков - " +
+		"после shred выполните wipe.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: runShred,
+}
+
+var slackCmd = &cobra.Command{
+	Use:   "slack <пути...>",
+	Short: "Затереть хвосты последних кластеров файлов",
+	Long: "Затирание неиспользуемого хвоста последнего кластера (slack space) у файлов: файл дописывается " +
+		"случайными данными до границы кластера, сбрасывается на диск и обрезается до исходной длины. " +
+		"Каталоги обходятся рекурсивно, пути могут содержать маски. Время изменения и доступа сохраняется. " +
+		"Открытые другими процессами, разреженные, сжатые файлы и пути внутри security.protected_paths пропускаются.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSlack,
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "n", false, "Тестовый режим")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Подробный вывод")
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "Путь к конфигурации")
 	rootCmd.PersistentFlags().StringVar(&maxDurationStr, "max-duration", "", "Максимальное время работы (например: 30m, 2h)")
-	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Профиль производительности (safe/balanced/aggressive/fast/sdelete)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Профиль производительности (safe/balanced/aggressive/fast/sdelete/tuned или из секции profiles)")
 	rootCmd.PersistentFlags().StringVar(&engine, "engine", "internal", "Движок затирания (internal/sdelete-compatible/cipher)")
 	rootCmd.PersistentFlags().StringVar(&mode, "mode", "standard", "Режим затирания (standard/sdelete/cipher)")
 	rootCmd.PersistentFlags().BoolVar(&allowSystemDisk, "allow-system-disk", false, "Разрешить затирание системного диска (ОПАСНО)")
@@ -123,9 +158,22 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&elevated, "elevated", false, "Internal flag to prevent UAC recursion")
 	rootCmd.PersistentFlags().MarkHidden("elevated")
 
-	wipeCmd.Flags().StringP("method", "m", "", "Метод затирания")
-	wipeCmd.Flags().IntP("passes", "p", 0, "Количество проходов")
+	wipeCmd.Flags().StringP("method", "m", "", "Метод затирания ("+strings.Join(wipe.SchemeNames(), "/")+" или из секции methods)")
+	wipeCmd.Flags().IntP("passes", "p", 0, "Число проходов для --device (многопроходный метод - не меньше чем целиком)")
 	wipeCmd.Flags().BoolP("force", "f", false, "Пропустить подтверждение")
+	wipeCmd.Flags().String("resume", "", "Продолжить прерванный запуск по run-id из журнала")
+	wipeCmd.Flags().String("device", "", "Затереть блочное устройство или файл образа целиком (/dev/sdX, \\\\.\\PhysicalDriveN, disk.img)")
+	wipeCmd.Flags().Uint64("offset", 0, "Начало диапазона затирания устройства в байтах (кратно сектору)")
+	wipeCmd.Flags().Uint64("length", 0, "Длина диапазона затирания устройства в байтах (0 - до конца)")
+	wipeCmd.Flags().Bool("verify", false, "Проверить чтением весь последний проход (для --device)")
+
+	shredCmd.Flags().StringP("method", "m", "random", "Метод затирания ("+strings.Join(wipe.SchemeNames(), "/")+" или из секции methods)")
+	shredCmd.Flags().IntP("passes", "p", 1, "Число проходов (многопроходный метод - не меньше чем целиком)")
+	shredCmd.Flags().BoolP("recursive", "r", false, "Обрабатывать каталоги со всем содержимым")
+	shredCmd.Flags().Int("renames", 3, "Сколько раз переименовать файл перед удалением")
+	shredCmd.Flags().BoolP("force", "f", false, "Пропустить подтверждение")
+
+	slackCmd.Flags().BoolP("force", "f", false, "Пропустить подтверждение")
 
 	verifyCmd.Flags().Bool("last-session", false, "Проверить последнюю сессию")
 	verifyCmd.Flags().Bool("physical", false, "Физическая проверка (требует админ)")
@@ -161,7 +209,7 @@ func init() {
 	cleanupCmd.Flags().String("category", "", "Выполнить операции по категории")
 	cleanupCmd.Flags().Bool("dry-run", false, "Тестовый режим выполнения")
 
-	rootCmd.AddCommand(wipeCmd, cleanCmd, infoCmd, verifyCmd, maintenanceCmd, diagnoseCmd, reportsCmd, cleanupCmd, versionCmd)
+	rootCmd.AddCommand(wipeCmd, cleanCmd, infoCmd, verifyCmd, maintenanceCmd, diagnoseCmd, reportsCmd, cleanupCmd, recoverCmd, shredCmd, slackCmd, versionCmd)
 }
 
 func runWipe(cmd *cobra.Command, args []string) error {
@@ -171,25 +219,39 @@ func runWipe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// СНАЧАЛА загружаем конфигурацию
+	// Продолжение прерванного запуска: режим и профиль берутся из журнала,
+	// поэтому журнал читается до загрузки конфигурации с профилем. Каталог
+	// состояния - из конфигурации без профиля.
+	resumeID, _ := cmd.Flags().GetString("resume")
+	var journal *wipe.Journal
+	if resumeID != "" {
+		base, err := config.Load(configPath)
+		if err != nil {
+			return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+		}
+		journal, err = wipe.LoadJournal(base.Wipe.StateDir, resumeID)
+		if err != nil {
+			return fmt.Errorf("ошибка загрузки журнала: %w", err)
+		}
+		if profile != "" && profile != journal.Profile {
+			return fmt.Errorf("профиль %q не совпадает с профилем прерванного запуска %q: продолжение выполняется с профилем из журнала, уберите --profile", profile, journal.Profile)
+		}
+		mode = journal.Mode
+		profile = journal.Profile
+	}
+
+	// СНАЧАЛА загружаем конфигурацию с профилем, если указан
 	var err error
-	cfg, err = config.Load(configPath)
+	cfg, err = config.LoadWithProfile(configPath, profile)
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
 	}
 
-	// Валидация конфигурации
-	if err := config.Validate(cfg); err != nil {
+	// Валидация конфигурации после применения профиля, включая методы по реестру схем
+	if err := wipe.ValidateConfig(cfg); err != nil {
 		return fmt.Errorf("невалидная конфигурация: %w", err)
 	}
 
-	// Применяем профиль если указан
-	if profile != "" {
-		if err := config.ApplyProfile(cfg, profile); err != nil {
-			return fmt.Errorf("ошибка применения профиля %s: %w", profile, err)
-		}
-	}
-
 	// Создаем логгер после загрузки конфигурации
 	logger, err = logging.NewEnterpriseLogger(cfg, verbose)
 	if err != nil {
@@ -201,12 +263,27 @@ func runWipe(cmd *cobra.Command, args []string) error {
 		logger.Log("INFO", "Применён профиль", "profile", profile)
 	}
 
+	if journal != nil {
+		logger.Log("INFO", "Продолжение прерванного запуска", "run_id", resumeID, "journal", journal.Path(), "mode", mode, "profile", profile)
+	}
+
 	// Валидация режима
 	validMode, err := wipe.ValidateMode(mode)
 	if err != nil {
 		return fmt.Errorf("некорректный режим: %w", err)
 	}
 
+	// Метод из командной строки переопределяет методы конфигурации для SSD и HDD
+	if methodFlag, _ := cmd.Flags().GetString("method"); methodFlag != "" {
+		method, err := wipe.ValidateMethod(methodFlag)
+		if err != nil {
+			return fmt.Errorf("некорректный метод: %w", err)
+		}
+		cfg.Wipe.SSDMethod = string(method)
+		cfg.Wipe.HDDMethod = string(method)
+		logger.Log("INFO", "Метод затирания задан в командной строке", "method", method)
+	}
+
 	// Парсинг max-duration (после загрузки конфига)
 	if maxDurationStr != "" {
 		duration, err := time.ParseDuration(maxDurationStr)
@@ -231,23 +308,58 @@ func runWipe(cmd *cobra.Command, args []string) error {
 
 	logger.Log("INFO", "Запуск WipeDisk Enterprise", "version", Version, "dry_run", dryRun)
 
+	// Затирание устройства или образа целиком вместо свободного места
+	if device, _ := cmd.Flags().GetString("device"); device != "" {
+		if len(args) > 0 || journal != nil {
+			return fmt.Errorf("--device нельзя сочетать с дисками и --resume")
+		}
+		return runDeviceWipe(cmd, device)
+	}
+
+	// Освобождаем место, занятое файлами аварийно завершенных запусков
+	if report, err := wipe.RecoverAllVolumes(context.Background(), cfg.Wipe.StateDir, dryRun, logger); err != nil {
+		logger.Log("WARN", "Восстановление артефактов не выполнено", "error", err.Error())
+	} else if len(report.Removed) > 0 {
+		fmt.Printf("Удалено артефактов прерванного затирания: %d (%.1f GB)\n", len(report.Removed), float64(report.BytesReclaimed)/(1024*1024*1024))
+	}
+
 	disks, err := system.GetDiskInfo(verbose)
 	if err != nil {
 		return fmt.Errorf("ошибка получения дисков: %w", err)
 	}
 
 	var targetDisks []system.DiskInfo
-	if len(args) > 0 {
-		// Process only specified disks
+	if journal != nil {
+		// Только тома из журнала, работа над которыми не завершена
+		pending := journal.Pending()
+		if len(pending) == 0 {
+			fmt.Printf("Запуск %s уже завершен, продолжать нечего\n", resumeID)
+			return nil
+		}
+		for _, v := range pending {
+			disk, err := findWipeTarget(disks, v.Disk)
+			if err != nil {
+				return fmt.Errorf("диск %s из журнала %s не найден: %w", v.Disk, resumeID, err)
+			}
+			targetDisks = append(targetDisks, disk)
+		}
+	} else if len(args) > 0 {
+		// Только указанные диски и каталоги
 		for _, arg := range args {
-			for _, disk := range disks {
-				if strings.EqualFold(disk.Letter, arg) || strings.EqualFold(disk.Letter+":", arg) {
-					if !security.ShouldSkipDisk(cfg, disk) {
-						targetDisks = append(targetDisks, disk)
-					}
-					break
+			disk, err := findWipeTarget(disks, arg)
+			if err != nil {
+				return err
+			}
+			if disk.Path != "" {
+				if err := security.CheckProtectedPath(cfg, disk.Path); err != nil {
+					return err
 				}
 			}
+			if security.ShouldSkipDisk(cfg, disk) {
+				logger.Log("WARN", "Цель пропущена: диск исключен или системный", "target", disk.Target(), "disk", disk.Letter)
+				continue
+			}
+			targetDisks = append(targetDisks, disk)
 		}
 	} else {
 		// Process all non-excluded disks
@@ -265,8 +377,12 @@ func runWipe(cmd *cobra.Command, args []string) error {
 
 	force, _ := cmd.Flags().GetBool("force")
 	if !force && !dryRun && cfg.Security.RequireConfirmation {
-		fmt.Printf("ВНИМАНИЕ: Будет затерто свободное место на %d локальных дисках:\n", len(targetDisks))
+		fmt.Printf("ВНИМАНИЕ: Будет затерто свободное место на %d локальных дисках и каталогах:\n", len(targetDisks))
 		for _, disk := range targetDisks {
+			if disk.Path != "" {
+				fmt.Printf("  %s (том %s, %s, %.1f GB доступно)\n", disk.Path, disk.Letter, disk.Type, float64(disk.FreeSize)/(1024*1024*1024))
+				continue
+			}
 			fmt.Printf("  %s (%s, %.1f GB свободно)\n", disk.Letter, disk.Type, float64(disk.FreeSize)/(1024*1024*1024))
 		}
 		fmt.Print("Продолжить? (y/N): ")
@@ -278,13 +394,14 @@ func runWipe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Создаем контекст с учетом maxDuration
+	// Создаем контекст с учетом maxDuration; время пауз в лимит не входит
 	baseCtx := context.Background()
 	var ctx context.Context
 	var cancel context.CancelFunc
+	pause := wipe.NewPauseController()
 
 	if maxDuration > 0 {
-		ctx, cancel = context.WithTimeout(baseCtx, maxDuration)
+		ctx, cancel = pause.WithDuration(baseCtx, maxDuration)
 	} else {
 		ctx, cancel = context.WithCancel(baseCtx)
 	}
@@ -303,12 +420,39 @@ func runWipe(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n[INFO] Получен сигнал %s, завершаем работу...\n", sig.String())
 		cancel()
 	}()
+	defer notifyPause(pause)()
+	if err := startSchedule(ctx, pause); err != nil {
+		return err
+	}
 
-	var operations []*wipe.WipeOperation
 	var hasWarnings bool
 	var hasErrors bool
 
-	// Обработка дисков с поддержкой отмены
+	// Журнал контрольных точек нового запуска (в тестовом режиме не ведется)
+	if journal == nil && !dryRun {
+		runID := fmt.Sprintf("run_%d", startTime.UnixNano())
+		journal, err = wipe.NewJournal(cfg.Wipe.StateDir, runID, string(validMode), profile)
+		if err != nil {
+			logger.Log("WARN", "Журнал контрольных точек недоступен, продолжение будет невозможно", "error", err.Error())
+			journal = nil
+		}
+	}
+
+	// Прогресс всех дисков выводится одной строкой; движок не пишет в консоль
+	var progress chan wipe.ProgressInfo
+	progressDone := make(chan struct{})
+	if dryRun {
+		close(progressDone)
+	} else {
+		progress = make(chan wipe.ProgressInfo, 16)
+		go func() {
+			printWipeProgress(progress)
+			close(progressDone)
+		}()
+	}
+
+	// Формируем задания: проверка системного диска выполняется до запуска
+	var jobs []wipe.WipeJob
 	for _, disk := range targetDisks {
 		// Проверка системного диска
 		systemPolicy, err := wipe.PrepareSystemDiskWipe(disk.Letter, allowSystemDisk, logger)
@@ -323,37 +467,65 @@ func runWipe(cmd *cobra.Command, args []string) error {
 			logger.Log("INFO", "Применяется политика системного диска", "disk", disk.Letter, "policy", "system_disk")
 		}
 
-		// Проверка контекста перед каждым диском
-		select {
-		case <-ctx.Done():
-			if logger != nil {
-				logger.Log("INFO", "Операция отменена пользователем или по таймауту")
-			}
-			fmt.Println("\n[INFO] Операция отменена")
-			break
-		default:
+		disk := disk
+		var checkpoint *wipe.VolumeCheckpoint
+		if journal != nil {
+			checkpoint = journal.Volume(disk.Target())
 		}
+		// max_duration уже ограничивает ctx всего запуска, поэтому движку передается 0
+		jobs = append(jobs, wipe.WipeJob{
+			Disk:       disk,
+			Checkpoint: checkpoint,
+			Run: func(ctx context.Context) *wipe.WipeOperation {
+				return wipe.WipeWithStrategy(ctx, disk, cfg, logger, dryRun, 0, validMode, profile, checkpoint, progress, pause)
+			},
+		})
+	}
+
+	// Диски обрабатываются параллельно (не более max_concurrent, по одному заданию на физическое устройство)
+	operations := wipe.NewScheduler(cfg.Wipe.MaxConcurrent, logger).Run(ctx, jobs)
+	if progress != nil {
+		close(progress)
+	}
+	<-progressDone
+	if global := wipe.Limiters().Global(); global.BytesTotal() > 0 {
+		logger.Log("INFO", "Суммарная скорость записи", "limit_mbps", global.Rate(),
+			"effective_mbps", global.Throughput(), "bytes", global.BytesTotal())
+	}
+	if ctx.Err() != nil {
+		logger.Log("INFO", "Операция отменена пользователем или по таймауту")
+		fmt.Println("\n[INFO] Операция отменена")
+	}
 
-		op := wipe.WipeWithStrategy(ctx, disk, cfg, logger, dryRun, maxDuration, validMode, profile)
-		operations = append(operations, op)
+	if journal != nil {
+		if len(journal.Pending()) == 0 {
+			if err := journal.Remove(); err != nil {
+				logger.Log("WARN", "Ошибка удаления журнала", "journal", journal.Path(), "error", err.Error())
+			}
+		} else {
+			logger.Log("INFO", "Запуск можно продолжить", "run_id", journal.RunID, "journal", journal.Path())
+			fmt.Printf("\nДля продолжения: wipedisk wipe --resume %s\n", journal.RunID)
+		}
+	}
 
+	for _, op := range operations {
 		switch op.Status {
 		case "COMPLETED":
 			// Успешное завершение
 		case "PARTIAL":
 			hasWarnings = true
 			if logger != nil {
-				logger.Log("WARN", "Диск обработан частично", "disk", disk.Letter, "reason", op.Warning)
+				logger.Log("WARN", "Диск обработан частично", "disk", op.Target(), "reason", op.Warning)
 			}
 		case "CANCELLED":
 			hasWarnings = true
 			if logger != nil {
-				logger.Log("WARN", "Операция отменена", "disk", disk.Letter, "reason", op.Warning)
+				logger.Log("WARN", "Операция отменена", "disk", op.Target(), "reason", op.Warning)
 			}
 		case "FAILED":
 			hasErrors = true
 			if logger != nil {
-				logger.Log("ERROR", "Операция не удалась", "disk", disk.Letter, "error", op.Error)
+				logger.Log("ERROR", "Операция не удалась", "disk", op.Target(), "error", op.Error)
 			}
 		}
 	}
@@ -369,8 +541,11 @@ func runWipe(cmd *cobra.Command, args []string) error {
 			status = "✗"
 		}
 
-		fmt.Printf("%s %s - %s (%.1f GB, %.1f MB/s)\n", status, op.Disk, op.Status,
+		fmt.Printf("%s %s - %s (%.1f GB, %.1f MB/s)\n", status, op.Target(), op.Status,
 			float64(op.BytesWiped)/(1024*1024*1024), op.SpeedMBps)
+		if op.Path != "" {
+			fmt.Printf("  Том: %s\n", op.Disk)
+		}
 
 		if op.Warning != "" {
 			fmt.Printf("  Предупреждение: %s\n", op.Warning)
@@ -378,6 +553,10 @@ func runWipe(cmd *cobra.Command, args []string) error {
 		if op.Error != "" {
 			fmt.Printf("  Ошибка: %s\n", op.Error)
 		}
+		if len(op.Segments) > 1 {
+			fmt.Printf("  Выполнено за %d запуска(ов)\n", len(op.Segments))
+		}
+		printActiveIntervals(op.ActiveIntervals)
 	}
 
 	// Корректные exit codes
@@ -405,6 +584,181 @@ func runWipe(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// findWipeTarget находит цель затирания: том из списка disks по букве или
+// точке монтирования, иначе каталог, разрешенный до содержащего его тома
+func findWipeTarget(disks []system.DiskInfo, target string) (system.DiskInfo, error) {
+	for _, disk := range disks {
+		if strings.EqualFold(disk.Letter, target) || strings.EqualFold(disk.Letter+":", target) || system.SameTarget(disk.Letter, target) {
+			return disk, nil
+		}
+	}
+
+	disk, err := system.ResolveWipeTarget(target)
+	if err != nil {
+		return system.DiskInfo{}, fmt.Errorf("цель затирания %s: %w", target, err)
+	}
+	return disk, nil
+}
+
+// runDeviceWipe затирает блочное устройство или файл образа целиком
+func runDeviceWipe(cmd *cobra.Command, device string) error {
+	if err := security.CheckProtectedPath(cfg, device); err != nil {
+		return err
+	}
+
+	method, err := wipe.ValidateMethod(cfg.Wipe.HDDMethod)
+	if err != nil {
+		return fmt.Errorf("некорректный метод: %w", err)
+	}
+	passes, _ := cmd.Flags().GetInt("passes")
+	offset, _ := cmd.Flags().GetUint64("offset")
+	length, _ := cmd.Flags().GetUint64("length")
+	fullVerify, _ := cmd.Flags().GetBool("verify")
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !dryRun && cfg.Security.RequireConfirmation {
+		fmt.Printf("ВНИМАНИЕ: Будут уничтожены все данные на %s (метод %s", device, method)
+		if offset > 0 || length > 0 {
+			fmt.Printf(", смещение %d, длина %d", offset, length)
+		}
+		fmt.Print(")\nПродолжить? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			logger.Log("INFO", "Операция отменена пользователем")
+			return nil
+		}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	pause := wipe.NewPauseController()
+	if maxDuration > 0 {
+		ctx, cancel = pause.WithDuration(context.Background(), maxDuration)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	defer notifyPause(pause)()
+	if err := startSchedule(ctx, pause); err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		logger.Log("WARN", "Получен сигнал, начинаем graceful shutdown", "signal", sig.String())
+		fmt.Printf("\n[INFO] Получен сигнал %s, завершаем работу...\n", sig.String())
+		cancel()
+	}()
+
+	verifier := wipe.NewVerifier(cfg.Wipe.Verify, nil, logger)
+	if fullVerify {
+		verifier = wipe.NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 1}, nil, logger)
+	}
+
+	// Прогресс выводится так же, как при затирании свободного места
+	var progress chan wipe.ProgressInfo
+	progressDone := make(chan struct{})
+	if dryRun {
+		close(progressDone)
+	} else {
+		progress = make(chan wipe.ProgressInfo, 16)
+		go func() {
+			printWipeProgress(progress)
+			close(progressDone)
+		}()
+	}
+
+	wipe.Limiters().Configure(cfg.Wipe.MaxSpeedMBps, cfg.Wipe.DiskSpeedMBps)
+	op := wipe.WipeDevice(ctx, device, wipe.DeviceWipeOptions{
+		Method:   method,
+		Passes:   passes,
+		Offset:   offset,
+		Length:   length,
+		DirectIO: cfg.Wipe.DirectIO,
+		DryRun:   dryRun,
+		Limiter:  wipe.Limiters().ForDisk(device),
+		Pause:    pause,
+		Verifier: verifier,
+		Progress: progress,
+	}, logger)
+	if progress != nil {
+		close(progress)
+	}
+	<-progressDone
+
+	status := "✓"
+	if op.Status == "CANCELLED" {
+		status = "⚠"
+	} else if op.Status != "COMPLETED" {
+		status = "✗"
+	}
+	fmt.Printf("\n%s %s - %s (%.1f GB, проходов: %d, %.1f MB/s)\n", status, op.Disk, op.Status,
+		float64(op.BytesWiped)/(1024*1024*1024), op.Passes, op.SpeedMBps)
+	if v := op.Verification; v != nil {
+		fmt.Printf("  Проверка чтением: %d блоков, несовпадений: %d, ошибок чтения: %d\n",
+			v.BlocksChecked, v.MismatchBlocks, v.ReadErrors)
+	}
+	if op.Warning != "" {
+		fmt.Printf("  Предупреждение: %s\n", op.Warning)
+	}
+	if op.Error != "" {
+		fmt.Printf("  Ошибка: %s\n", op.Error)
+	}
+	printActiveIntervals(op.ActiveIntervals)
+
+	exitCode := EXIT_SUCCESS
+	if op.Status == "FAILED" {
+		exitCode = EXIT_ERROR
+	}
+	if err := generateAndSaveReport([]*wipe.WipeOperation{op}, cfg, engine, profile, dryRun, maxDuration, startTime, time.Now(), exitCode, logger); err != nil {
+		logger.Log("WARN", "Ошибка сохранения отчёта", "error", err.Error())
+	}
+
+	if op.Status == "FAILED" {
+		return fmt.Errorf("затирание устройства не выполнено: %s", op.Error)
+	}
+	return nil
+}
+
+// printWipeProgress выводит прогресс затирания дисков одной обновляемой строкой
+func printWipeProgress(progress <-chan wipe.ProgressInfo) {
+	latest := make(map[string]wipe.ProgressInfo)
+	width := 0
+	for info := range progress {
+		latest[info.Disk] = info
+		disks := make([]string, 0, len(latest))
+		for disk := range latest {
+			disks = append(disks, disk)
+		}
+		sort.Strings(disks)
+
+		parts := make([]string, 0, len(disks))
+		for _, disk := range disks {
+			p := latest[disk]
+			eta := "ETA: Calculating..."
+			if p.Done {
+				eta = "Done"
+			} else if p.Paused {
+				eta = "PAUSED"
+			} else if p.EstimatedTime > 0 {
+				eta = fmt.Sprintf("ETA: %02d:%02d:%02d", int(p.EstimatedTime.Hours()), int(p.EstimatedTime.Minutes())%60, int(p.EstimatedTime.Seconds())%60)
+			}
+			parts = append(parts, fmt.Sprintf("[Disk %s] Pass %d/%d | %.1f%% | %.1f MB/s | %s",
+				disk, p.Pass, p.Passes, p.Percentage, p.SpeedMBps, eta))
+		}
+
+		line := strings.Join(parts, "  ")
+		fmt.Printf("\r%-*s", width, line)
+		width = max(width, len(line))
+	}
+	if width > 0 {
+		fmt.Println()
+	}
+}
+
 func generateAndSaveReport(operations []*wipe.WipeOperation, cfg *config.Config, engine, profile string, dryRun bool, maxDuration time.Duration, startTime, endTime time.Time, exitCode int, logger *logging.EnterpriseLogger) error {
 	if cfg != nil && cfg.Reporting.Enabled {
 		// Generate legacy report
@@ -463,6 +817,217 @@ func runClean(cmd *cobra.Command, args []string) error {
 	return system.CleanTempFiles(ctx, logger, dryRun)
 }
 
+func runRecover(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+
+	logger, err = logging.NewEnterpriseLogger(cfg, verbose)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации логгера: %w", err)
+	}
+	defer logger.Close()
+
+	report, err := wipe.RecoverAllVolumes(context.Background(), cfg.Wipe.StateDir, dryRun, logger)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Восстановление после прерванного затирания:")
+	fmt.Println("===========================================")
+	action := "Удален"
+	if dryRun {
+		action = "Будет удален"
+	}
+	for _, a := range report.Removed {
+		fmt.Printf("✓ %s: %s (%.1f MB)\n", action, a.Path, float64(a.Size)/(1024*1024))
+	}
+	for _, s := range report.Skipped {
+		fmt.Printf("⚠ Пропущен: %s - %s\n", s.Path, s.Reason)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("✗ Ошибка: %s\n", e)
+	}
+	if len(report.Removed) == 0 && len(report.Skipped) == 0 && len(report.Errors) == 0 {
+		fmt.Println("Артефакты затирания не найдены")
+	}
+	fmt.Printf("\nОсвобождено: %.2f GB\n", float64(report.BytesReclaimed)/(1024*1024*1024))
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("не все артефакты удалены")
+	}
+	return nil
+}
+
+func runShred(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.LoadWithProfile(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+	if err := wipe.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("невалидная конфигурация: %w", err)
+	}
+
+	logger, err = logging.NewEnterpriseLogger(cfg, verbose)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации логгера: %w", err)
+	}
+	defer logger.Close()
+
+	methodName, _ := cmd.Flags().GetString("method")
+	method, err := wipe.ValidateMethod(methodName)
+	if err != nil {
+		return err
+	}
+	passes, _ := cmd.Flags().GetInt("passes")
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	renames, _ := cmd.Flags().GetInt("renames")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if !force && !dryRun && cfg.Security.RequireConfirmation {
+		fmt.Printf("ВНИМАНИЕ: Будут безвозвратно уничтожены (метод %s):\n", method)
+		for _, arg := range args {
+			fmt.Printf("  %s\n", arg)
+		}
+		fmt.Print("Продолжить? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			logger.Log("INFO", "Операция отменена пользователем")
+			return nil
+		}
+	}
+
+	// SIGINT/SIGTERM прерывают уничтожение между блоками записи, как и затирание
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if maxDurationStr != "" {
+		duration, err := time.ParseDuration(maxDurationStr)
+		if err != nil {
+			return fmt.Errorf("неверный формат max-duration: %w", err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	wipe.Limiters().Configure(cfg.Wipe.MaxSpeedMBps, cfg.Wipe.DiskSpeedMBps)
+	report, err := wipe.Shred(ctx, args, wipe.ShredOptions{
+		Method:    method,
+		Passes:    passes,
+		Recursive: recursive,
+		Renames:   renames,
+		DryRun:    dryRun,
+		Guard:     func(path string) error { return security.CheckProtectedPath(cfg, path) },
+		Limiter:   wipe.Limiters().Global(),
+		Verifier:  wipe.NewVerifier(cfg.Wipe.Verify, nil, logger),
+	}, logger)
+	if err != nil && report == nil {
+		return err
+	}
+
+	action := "Уничтожен"
+	if dryRun {
+		action = "Будет уничтожен"
+	}
+	for _, f := range report.Files {
+		fmt.Printf("✓ %s: %s (%.1f MB, проходов: %d)\n", action, f.Path, float64(f.Size)/(1024*1024), f.Passes)
+	}
+	for _, d := range report.Dirs {
+		fmt.Printf("✓ %s каталог: %s\n", action, d)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("✗ Ошибка: %s\n", e)
+	}
+	fmt.Printf("\nПерезаписано: %.2f MB\n", float64(report.BytesOverwritten)/(1024*1024))
+
+	if err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("некоторые операции завершились с ошибками")
+	}
+	return nil
+}
+
+func runSlack(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+
+	logger, err = logging.NewEnterpriseLogger(cfg, verbose)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации логгера: %w", err)
+	}
+	defer logger.Close()
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !dryRun && cfg.Security.RequireConfirmation {
+		fmt.Println("ВНИМАНИЕ: Будут затерты хвосты кластеров файлов в:")
+		for _, arg := range args {
+			fmt.Printf("  %s\n", arg)
+		}
+		fmt.Print("Продолжить? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			logger.Log("INFO", "Операция отменена пользователем")
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	if maxDurationStr != "" {
+		duration, err := time.ParseDuration(maxDurationStr)
+		if err != nil {
+			return fmt.Errorf("неверный формат max-duration: %w", err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	report, err := wipe.WipeSlack(ctx, args, wipe.SlackOptions{
+		DryRun: dryRun,
+		Guard:  func(path string) error { return security.CheckProtectedPath(cfg, path) },
+	}, logger)
+	if err != nil && report == nil {
+		return err
+	}
+
+	if verbose {
+		for _, f := range report.Files {
+			fmt.Printf("✓ %s: %d байт\n", f.Path, f.Slack)
+		}
+		for _, s := range report.Skipped {
+			fmt.Printf("- Пропущен: %s (%s)\n", s.Path, s.Reason)
+		}
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("✗ Ошибка: %s\n", e)
+	}
+
+	action := "Затерто"
+	if dryRun {
+		action = "Будет затерто"
+	}
+	fmt.Printf("\n%s: %.2f MB в %d файлах, пропущено файлов: %d\n",
+		action, float64(report.SlackBytes)/(1024*1024), len(report.Files), len(report.Skipped))
+
+	if err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("некоторые операции завершились с ошибками")
+	}
+	return nil
+}
+
 func runInfo(cmd *cobra.Command, args []string) error {
 	disks, err := system.GetDiskInfo(verbose)
 	if err != nil {
@@ -652,6 +1217,10 @@ func runMaintenance(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
 	}
+	// Затирание в плане использует методы конфигурации
+	if err := wipe.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("невалидная конфигурация: %w", err)
+	}
 
 	// Создаем логгер
 	logger, err := logging.NewEnterpriseLogger(cfg, verbose)
@@ -678,8 +1247,8 @@ func runMaintenance(cmd *cobra.Command, args []string) error {
 	// Создаем оркестратор
 	orchestrator := maintenance.NewMaintenanceOrchestrator(cfg, logger, dryRun, verbose)
 
-	// Создаем контекст
-	ctx, cancel := context.WithTimeout(context.Background(), plan.Timeout)
+	// Создаем контекст; таймаут плана без учета времени вне окон обслуживания соблюдает оркестратор
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Установка обработчиков сигналов
@@ -729,6 +1298,7 @@ func runMaintenance(cmd *cobra.Command, args []string) error {
 			if result.Error != "" {
 				fmt.Printf("    Ошибка: %s\n", result.Error)
 			}
+			printActiveIntervals(result.ActiveIntervals)
 		}
 	}
 
@@ -1044,12 +1614,7 @@ func checkAndElevateAdmin() bool {
 	elevatedArgs := append(os.Args[1:], "--elevated")
 
 	// Используем ShellExecute для запроса UAC
-	err = windows.ShellExecute(0,
-		windows.StringToUTF16Ptr(verb),
-		windows.StringToUTF16Ptr(exePath),
-		windows.StringToUTF16Ptr(strings.Join(elevatedArgs, " ")),
-		windows.StringToUTF16Ptr(cwd),
-		windows.SW_NORMAL)
+	err = relaunchElevated(verb, exePath, strings.Join(elevatedArgs, " "), cwd)
 	if err != nil {
 		fmt.Printf("Не удалось перезапустить с правами администратора: %v\n", err)
 		fmt.Println("Пожалуйста, запустите программу вручную от имени администратора")
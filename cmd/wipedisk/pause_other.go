@@ -0,0 +1,50 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"wipedisk_enterprise/internal/wipe"
+)
+
+// notifyPause приостанавливает затирание по SIGUSR1 и возобновляет по SIGUSR2.
+// Возвращает функцию, отключающую обработку сигналов.
+func notifyPause(pause *wipe.PauseController) func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+
+	logger.Log("INFO", "Пауза затирания доступна по сигналам", "pause", fmt.Sprintf("kill -USR1 %d", os.Getpid()),
+		"resume", fmt.Sprintf("kill -USR2 %d", os.Getpid()))
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigChan:
+				if sig == syscall.SIGUSR1 && pause.Pause() {
+					logger.Log("INFO", "Затирание приостановлено", "signal", sig.String())
+					fmt.Printf("\n[INFO] Затирание приостановлено, для продолжения: kill -USR2 %d\n", os.Getpid())
+				} else if sig == syscall.SIGUSR2 && pause.Resume() {
+					if pause.Paused() {
+						logger.Log("INFO", "Пауза снята, затирание продолжится при открытии окна обслуживания", "signal", sig.String())
+						fmt.Println("\n[INFO] Пауза снята, затирание продолжится при открытии окна обслуживания")
+						continue
+					}
+					logger.Log("INFO", "Затирание возобновлено", "signal", sig.String())
+					fmt.Println("\n[INFO] Затирание возобновлено")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
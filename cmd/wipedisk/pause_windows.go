@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "wipedisk_enterprise/internal/wipe"
+
+// notifyPause - в Windows нет сигналов SIGUSR1/SIGUSR2: пауза доступна из
+// графического интерфейса
+func notifyPause(pause *wipe.PauseController) func() {
+	return func() {}
+}
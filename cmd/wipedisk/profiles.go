@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/wipe"
+)
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Профили производительности",
+	Long: "Встроенные профили и профили из секции profiles конфигурации. Пользовательский профиль " +
+		"переопределяет поля секции wipe и может наследовать другой профиль (inherits).",
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Список профилей с итоговыми параметрами",
+	Args:  cobra.NoArgs,
+	RunE:  runProfilesList,
+}
+
+var profilesShowCmd = &cobra.Command{
+	Use:     "show <профиль>",
+	Short:   "Итоговые параметры wipe с профилем",
+	Example: `  wipedisk profiles show balanced --config config.yaml`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runProfilesShow,
+}
+
+func init() {
+	rootCmd.AddCommand(profilesCmd)
+	profilesCmd.AddCommand(profilesListCmd, profilesShowCmd)
+}
+
+// loadProfilesConfig загружает конфигурацию без профиля
+func loadProfilesConfig() (*config.Config, error) {
+	base, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+	return base, nil
+}
+
+// effectiveConfig возвращает копию конфигурации с примененным профилем
+// и проверяет ее
+func effectiveConfig(base *config.Config, name string) (*config.Config, error) {
+	eff := *base
+	if err := config.ApplyProfile(&eff, name); err != nil {
+		return nil, err
+	}
+	if err := wipe.ValidateConfig(&eff); err != nil {
+		return &eff, fmt.Errorf("невалидная конфигурация: %w", err)
+	}
+	return &eff, nil
+}
+
+func runProfilesList(cmd *cobra.Command, args []string) error {
+	base, err := loadProfilesConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  %-14s %-10s %-12s %-38s %-7s %6s %12s %-6s\n",
+		"Профиль", "Источник", "Основа", "Метод SSD/HDD", "Проходы", "Блок", "Скорость", "Direct")
+	for _, p := range config.Profiles(base) {
+		source := "config"
+		if p.Builtin {
+			source = "встроенный"
+		}
+		inherits := p.Inherits
+		if inherits == "" {
+			inherits = "-"
+		}
+
+		eff, err := effectiveConfig(base, p.Name)
+		if eff == nil {
+			fmt.Printf("  %-14s %-10s %-12s ✗ %v\n", p.Name, source, inherits, err)
+		} else {
+			w := eff.Wipe
+			fmt.Printf("  %-14s %-10s %-12s %-38s %-7s %4dMB %12s %-6s\n", p.Name, source, inherits,
+				w.SSDMethod+"/"+w.HDDMethod, fmt.Sprintf("%d/%d", w.SSDPasses, w.HDDPasses),
+				w.ChunkSize>>20, speedLimitName(w.MaxSpeedMBps), yesNo(w.DirectIO))
+			if err != nil {
+				fmt.Printf("  %14s ✗ %v\n", "", err)
+			}
+		}
+		if p.Description != "" {
+			fmt.Printf("  %14s %s\n", "", p.Description)
+		}
+	}
+	return nil
+}
+
+func runProfilesShow(cmd *cobra.Command, args []string) error {
+	base, err := loadProfilesConfig()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+
+	chain, err := config.ProfileChain(base, name)
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	fmt.Printf("Профиль %s: %s\n", name, strings.Join(chain, " → "))
+	for _, p := range config.Profiles(base) {
+		if p.Name == name && p.Description != "" {
+			fmt.Printf("  %s\n", p.Description)
+		}
+	}
+
+	eff, validateErr := effectiveConfig(base, name)
+	if eff == nil {
+		return validateErr
+	}
+	changed, err := changedWipeFields(base, eff)
+	if err != nil {
+		return err
+	}
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(eff.Wipe); err != nil {
+		return fmt.Errorf("ошибка вывода параметров: %w", err)
+	}
+	enc.Close()
+
+	fmt.Println("\nИтоговые параметры wipe (* - изменено профилем):")
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		mark := " "
+		if key, _, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, " ") && changed[key] {
+			mark = "*"
+		}
+		fmt.Printf("%s %s\n", mark, line)
+	}
+
+	if validateErr != nil {
+		fmt.Printf("\n✗ %v\n", validateErr)
+		return fmt.Errorf("профиль %s дает невалидную конфигурацию", name)
+	}
+	fmt.Println("\n✓ Конфигурация с профилем валидна")
+	return nil
+}
+
+// changedWipeFields возвращает поля секции wipe, которые профиль изменил
+func changedWipeFields(before, after *config.Config) (map[string]bool, error) {
+	toMap := func(cfg *config.Config) (map[string]any, error) {
+		data, err := yaml.Marshal(cfg.Wipe)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]any
+		return m, yaml.Unmarshal(data, &m)
+	}
+	b, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for key, value := range a {
+		if !reflect.DeepEqual(b[key], value) {
+			changed[key] = true
+		}
+	}
+	return changed, nil
+}
+
+// speedLimitName описывает ограничение скорости
+func speedLimitName(mbps float64) string {
+	if mbps <= 0 {
+		return "без лимита"
+	}
+	return fmt.Sprintf("%.0f MB/s", mbps)
+}
+
+// yesNo описывает логический параметр
+func yesNo(v bool) string {
+	if v {
+		return "да"
+	}
+	return "нет"
+}
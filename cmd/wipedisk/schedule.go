@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"wipedisk_enterprise/internal/wipe"
+)
+
+// startSchedule включает окна обслуживания из конфигурации: вне окон
+// затирание приостанавливается через pause до отмены ctx
+func startSchedule(ctx context.Context, pause *wipe.PauseController) error {
+	schedule, err := wipe.NewWipeSchedule(cfg.Wipe.Schedule)
+	if err != nil {
+		return fmt.Errorf("ошибка расписания затирания: %w", err)
+	}
+	if schedule == nil {
+		return nil
+	}
+
+	schedule.Start(ctx, pause, logger)
+	if open, change := schedule.State(time.Now()); !open {
+		fmt.Printf("[INFO] Вне окна обслуживания, затирание начнется в %s\n", change.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// printActiveIntervals выводит интервалы работы, если затирание прерывалось
+func printActiveIntervals(intervals []wipe.ActiveInterval) {
+	if len(intervals) < 2 {
+		return
+	}
+	fmt.Println("  Активные интервалы:")
+	for _, i := range intervals {
+		fmt.Printf("    %s - %s (%s)\n", i.Start.Format("2006-01-02 15:04:05"), i.End.Format("2006-01-02 15:04:05"),
+			i.End.Sub(i.Start).Round(time.Second))
+	}
+}
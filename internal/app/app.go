@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"wipedisk_enterprise/internal/config"
@@ -26,6 +27,13 @@ type App struct {
 	maintenanceRunner *maintenance.MaintenanceRunner
 	dryRun            bool
 	silentMode        bool
+
+	// Latest progress of the running StartWipe, polled by the frontend
+	progressMu sync.Mutex
+	progress   wipe.ProgressInfo
+
+	// Pauses and resumes running wipes
+	pause *wipe.PauseController
 }
 
 // NewApp creates a new App instance
@@ -43,6 +51,7 @@ func NewApp() *App {
 		logger:     logger,
 		config:     config.Default(),
 		wipeEngine: wipeEngine,
+		pause:      wipe.NewPauseController(),
 	}
 }
 
@@ -54,6 +63,7 @@ func NewAppWithDependencies(logger *logging.EnterpriseLogger, wipeEngine *wipe.W
 		config:            config.Default(),
 		wipeEngine:        wipeEngine,
 		maintenanceRunner: maintenanceRunner,
+		pause:             wipe.NewPauseController(),
 	}
 }
 
@@ -128,6 +138,7 @@ type WipeProgress struct {
 	Percentage    float64 `json:"percentage"`
 	ElapsedTime   string  `json:"elapsedTime"`
 	EstimatedTime string  `json:"estimatedTime"`
+	Paused        bool    `json:"paused"`
 }
 
 // WipeResult represents the result of a wipe operation
@@ -141,14 +152,32 @@ type WipeResult struct {
 
 // StartWipe starts wiping the specified drive
 func (a *App) StartWipe(drive string) error {
-	// Create progress channel
-	progressChan := make(chan wipe.ProgressInfo, 100)
-
-	// Set progress channel on engine
-	a.wipeEngine.SetProgressChannel(progressChan)
+	a.recoverArtifacts(a.ctx, []string{drive})
+	wipe.Limiters().Configure(a.config.Wipe.MaxSpeedMBps, a.config.Wipe.DiskSpeedMBps)
+	stopSchedule, err := a.startSchedule(a.ctx)
+	if err != nil {
+		return err
+	}
+	defer stopSchedule()
+
+	// The reader drains this channel until it is closed and keeps the latest value
+	a.progressMu.Lock()
+	a.progress = wipe.ProgressInfo{}
+	a.progressMu.Unlock()
+	progressChan := make(chan wipe.ProgressInfo, 16)
+	defer close(progressChan)
+	go func() {
+		for info := range progressChan {
+			a.progressMu.Lock()
+			a.progress = info
+			a.progressMu.Unlock()
+		}
+	}()
 
-	// Start wipe and wait for completion
-	result, err := a.wipeEngine.WipeDrive(a.ctx, drive, nil)
+	// Start wipe and wait for completion. The per-run engine keeps this
+	// channel private, so concurrent wipes never send on it after close
+	engine := a.wipeEngine.ForRun(a.config, progressChan, a.pause)
+	result, err := engine.WipeDrive(a.ctx, drive, nil)
 	if err != nil {
 		a.logger.Log("ERROR", "Wipe failed", "drive", drive, "error", err.Error())
 		return err
@@ -158,6 +187,115 @@ func (a *App) StartWipe(drive string) error {
 	return nil
 }
 
+// GetWipeProgress returns the progress of the running wipe
+func (a *App) GetWipeProgress() WipeProgress {
+	a.progressMu.Lock()
+	info := a.progress
+	a.progressMu.Unlock()
+
+	progress := WipeProgress{
+		BytesWritten: float64(info.BytesWritten) / (1024 * 1024 * 1024),
+		SpeedMBps:    info.SpeedMBps,
+		Percentage:   info.Percentage,
+		Paused:       a.pause.Paused(),
+	}
+	if !info.StartTime.IsZero() {
+		progress.ElapsedTime = time.Since(info.StartTime).Round(time.Second).String()
+	}
+	if info.EstimatedTime > 0 {
+		progress.EstimatedTime = info.EstimatedTime.Round(time.Second).String()
+	}
+	return progress
+}
+
+// PauseWipe pauses running wipes at the next chunk boundary; their files are
+// closed until ResumeWipe. Returns false if the wipe is already paused.
+func (a *App) PauseWipe() bool {
+	if !a.pause.Pause() {
+		return false
+	}
+	a.logger.Log("INFO", "Wipe paused")
+	return true
+}
+
+// ResumeWipe resumes paused wipes. Returns false if nothing was paused.
+func (a *App) ResumeWipe() bool {
+	if !a.pause.Resume() {
+		return false
+	}
+	a.logger.Log("INFO", "Wipe resumed")
+	return true
+}
+
+// IsWipePaused reports whether wipes are paused
+func (a *App) IsWipePaused() bool {
+	return a.pause.Paused()
+}
+
+// startSchedule pauses wipes outside the configured maintenance windows
+// until the returned stop function is called
+func (a *App) startSchedule(ctx context.Context) (func(), error) {
+	schedule, err := wipe.NewWipeSchedule(a.config.Wipe.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wipe schedule: %w", err)
+	}
+	ctx, stop := context.WithCancel(ctx)
+	schedule.Start(ctx, a.pause, a.logger)
+	return stop, nil
+}
+
+// recoverArtifacts removes signed wipe files left on the drives by crashed runs
+func (a *App) recoverArtifacts(ctx context.Context, drives []string) {
+	report, err := wipe.RecoverArtifacts(ctx, a.config.Wipe.StateDir, drives, a.dryRun, a.logger)
+	if err != nil {
+		a.logger.Log("WARN", "Artifact recovery skipped", "error", err.Error())
+		return
+	}
+	if len(report.Removed) > 0 {
+		a.logger.Log("INFO", "Reclaimed space from interrupted wipes", "files", len(report.Removed), "bytes", report.BytesReclaimed)
+	}
+}
+
+// wipeDrives wipes several drives in parallel, honoring wipe.max_concurrent
+// and never running two jobs on the same physical device
+func (a *App) wipeDrives(ctx context.Context, drives []string) []*wipe.WipeOperation {
+	a.recoverArtifacts(ctx, drives)
+	wipe.Limiters().Configure(a.config.Wipe.MaxSpeedMBps, a.config.Wipe.DiskSpeedMBps)
+	stopSchedule, err := a.startSchedule(ctx)
+	if err != nil {
+		a.logger.Log("ERROR", "Wipes skipped", "error", err.Error())
+		return nil
+	}
+	defer stopSchedule()
+
+	// Nobody reads progress in batch mode
+	engine := a.wipeEngine.ForRun(a.config, nil, a.pause)
+
+	var jobs []wipe.WipeJob
+	for _, drive := range drives {
+		disk, err := system.ResolveWipeTarget(drive)
+		if err != nil {
+			disk = system.DiskInfo{Letter: drive}
+		}
+
+		drive := drive
+		jobs = append(jobs, wipe.WipeJob{
+			Disk: disk,
+			Run: func(ctx context.Context) *wipe.WipeOperation {
+				op := engine.WipeVolume(ctx, drive)
+				if op.Status == "FAILED" {
+					a.logger.Log("ERROR", "Wipe failed", "drive", drive, "error", op.Error)
+				} else if op.Status == "COMPLETED" {
+					a.logger.Log("INFO", "Wipe completed successfully", "drive", drive, "bytesWritten", op.BytesWiped)
+				}
+				return op
+			},
+		})
+	}
+
+	return wipe.NewScheduler(a.config.Wipe.MaxConcurrent, a.logger).Run(ctx, jobs)
+}
+
 // DiagnosticLevel represents diagnostic levels
 type DiagnosticLevel string
 
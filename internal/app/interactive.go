@@ -129,7 +129,7 @@ func (im *InteractiveMenu) showSecureWipeMenu() error {
 		return fmt.Errorf("неверный выбор")
 	}
 
-	drive := strings.TrimRight(drives[idx-1].Letter, ".\\") + "\\"
+	drive := system.VolumeRoot(system.NormalizePath(drives[idx-1].Letter))
 
 	fmt.Println("\n1. Quick (1 pass)\n2. Standard (3 passes)")
 	m := im.prompt("Метод: ")
@@ -277,22 +277,35 @@ func (im *InteractiveMenu) wipeAllDrives() error {
 		return fmt.Errorf("операция отменена - неверное подтверждение")
 	}
 
-	fmt.Println("\n🔥 НАЧИНАЮ ЗАТИРАНИЕ ВСЕХ ДИСКОВ...\n")
+	fmt.Print("\n🔥 НАЧИНАЮ ЗАТИРАНИЕ ВСЕХ ДИСКОВ...\n\n")
 
-	// Затираем каждый диск последовательно
+	// Затираем диски параллельно (не более max_concurrent одновременно)
+	var paths []string
 	for i, d := range drives {
-		drive := strings.TrimRight(d.Letter, ".\\") + "\\"
-		fmt.Printf("\n[ДИСК %d/%d] Затираю: %s\n", i+1, len(drives), drive)
+		drive := system.VolumeRoot(system.NormalizePath(d.Letter))
+		fmt.Printf("[ДИСК %d/%d] В очереди: %s\n", i+1, len(drives), drive)
+		paths = append(paths, drive)
+	}
 
-		err := im.app.StartWipe(drive)
-		if err != nil {
-			fmt.Printf("❌ Ошибка при затирании диска %s: %v\n", drive, err)
-			continue
+	failed := 0
+	for _, op := range im.app.wipeDrives(im.ctx, paths) {
+		switch op.Status {
+		case "COMPLETED":
+			fmt.Printf("✅ Диск %s затерт успешно\n", op.Disk)
+		case "FAILED":
+			failed++
+			fmt.Printf("❌ Ошибка при затирании диска %s: %s\n", op.Disk, op.Error)
+		default:
+			failed++
+			fmt.Printf("⚠️  Диск %s: %s %s\n", op.Disk, op.Status, op.Warning)
 		}
-		fmt.Printf("✅ Диск %s затерт успешно\n", drive)
 	}
 
-	fmt.Println("\n🎉 ВСЕ ДИСКИ ЗАТЕРТЫ УСПЕШНО!")
+	if failed == 0 {
+		fmt.Println("\n🎉 ВСЕ ДИСКИ ЗАТЕРТЫ УСПЕШНО!")
+	} else {
+		fmt.Printf("\n⚠️  Не все диски затерты: %d из %d с ошибками или прерваны\n", failed, len(paths))
+	}
 	im.pause()
 	return nil
 }
@@ -4,11 +4,57 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// DefaultStateDir каталог состояния (журналы контрольных точек, ключ маркеров)
+// по умолчанию. Путь абсолютный и не зависит от рабочего каталога запуска.
+var DefaultStateDir = defaultStateDir()
+
+// AdaptiveThrottleConfig настройки адаптивного ограничения скорости.
+// Задержки нормируются на 1 МБ записанных данных, чтобы не зависеть от размера чанка.
+type AdaptiveThrottleConfig struct {
+	Enabled        bool    `yaml:"enabled"`
+	MinSpeedMBps   float64 `yaml:"min_speed_mbps"`
+	MaxSpeedMBps   float64 `yaml:"max_speed_mbps"`   // 0 - wipe.max_speed_mbps
+	WriteLatencyMs float64 `yaml:"write_latency_ms"` // Порог задержки write на 1 МБ
+	SyncLatencyMs  float64 `yaml:"sync_latency_ms"`  // Порог задержки fsync на 1 МБ
+	MaxIOPressure  float64 `yaml:"max_io_pressure"`  // Порог /proc/pressure/io (some avg10, %)
+	Interval       string  `yaml:"interval"`         // Период пересчета скорости
+}
+
+// VerifyConfig настройки проверки записанных данных чтением перед удалением
+// файлов затирания
+type VerifyConfig struct {
+	Enabled    bool    `yaml:"enabled"`
+	SampleRate float64 `yaml:"sample_rate"` // Доля проверяемых блоков файла: 1 - файл целиком
+}
+
+// MetadataScrubConfig настройки очистки метаданных после затирания свободного
+// места: множество мелких файлов занимает освободившиеся записи MFT, иноды и
+// записи каталогов, в которых могли остаться резидентные данные
+type MetadataScrubConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	MaxFiles    int    `yaml:"max_files"`     // Предел числа файлов
+	MaxDuration string `yaml:"max_duration"`  // Предел длительности фазы
+	MaxFileSize int    `yaml:"max_file_size"` // Наибольший размер файла, байт
+}
+
+// TunedConfig параметры записи, подобранные командой wipedisk benchmark для
+// профиля tuned. Пустой chunk_size - замер не выполнялся.
+type TunedConfig struct {
+	Volume         string  `yaml:"volume"`           // Том, на котором выполнен замер
+	MeasuredAt     string  `yaml:"measured_at"`      // Время замера (RFC 3339)
+	ChunkSize      int     `yaml:"chunk_size"`       // Размер блока записи, байт
+	SyncIntervalMB int     `yaml:"sync_interval_mb"` // Период fsync при записи файла
+	DirectIO       bool    `yaml:"direct_io"`        // Запись в обход кэша ОС
+	ThroughputMBps float64 `yaml:"throughput_mbps"`  // Измеренная скорость записи
+	SyncLatencyMs  float64 `yaml:"sync_latency_ms"`  // Средняя задержка fsync
+}
+
 // Enterprise конфигурация
 type Config struct {
 	Security struct {
@@ -29,11 +75,37 @@ type Config struct {
 		EnableTrim    bool    `yaml:"enable_trim"`
 		MaxConcurrent int     `yaml:"max_concurrent"`
 		MaxSpeedMBps  float64 `yaml:"max_speed_mbps"`
+		DiskSpeedMBps float64 `yaml:"max_disk_speed_mbps"`
 		MaxDuration   string  `yaml:"max_duration"`
 		FileDelayMs   int     `yaml:"file_delay_ms"`
 		TargetDrive   string  `yaml:"target_drive"`
+		StateDir      string  `yaml:"state_dir"`
+
+		// Прямой ввод-вывод в обход кэша ОС (O_DIRECT / FILE_FLAG_NO_BUFFERING)
+		DirectIO bool `yaml:"direct_io"`
+
+		// Адаптивное ограничение скорости по задержкам записи и нагрузке системы
+		Adaptive AdaptiveThrottleConfig `yaml:"adaptive"`
+
+		// Проверка записанных данных чтением
+		Verify VerifyConfig `yaml:"verify"`
+
+		// Очистка метаданных файловой системы после проходов
+		MetadataScrub MetadataScrubConfig `yaml:"metadata_scrub"`
+
+		// Окна обслуживания, вне которых затирание приостанавливается
+		Schedule ScheduleConfig `yaml:"schedule"`
+
+		// Параметры записи профиля tuned (wipedisk benchmark --save)
+		Tuned TunedConfig `yaml:"tuned"`
 	} `yaml:"wipe"`
 
+	// Пользовательские профили производительности (--profile)
+	Profiles map[string]ProfileConfig `yaml:"profiles"`
+
+	// Пользовательские методы затирания (ssd_method, hdd_method, --method)
+	Methods map[string]MethodConfig `yaml:"methods"`
+
 	Logging struct {
 		Level       string `yaml:"level"`
 		File        string `yaml:"file"`
@@ -96,9 +168,23 @@ func Default() *Config {
 			EnableTrim    bool    `yaml:"enable_trim"`
 			MaxConcurrent int     `yaml:"max_concurrent"`
 			MaxSpeedMBps  float64 `yaml:"max_speed_mbps"`
+			DiskSpeedMBps float64 `yaml:"max_disk_speed_mbps"`
 			MaxDuration   string  `yaml:"max_duration"`
 			FileDelayMs   int     `yaml:"file_delay_ms"`
 			TargetDrive   string  `yaml:"target_drive"`
+			StateDir      string  `yaml:"state_dir"`
+
+			DirectIO bool `yaml:"direct_io"`
+
+			Adaptive AdaptiveThrottleConfig `yaml:"adaptive"`
+
+			Verify VerifyConfig `yaml:"verify"`
+
+			MetadataScrub MetadataScrubConfig `yaml:"metadata_scrub"`
+
+			Schedule ScheduleConfig `yaml:"schedule"`
+
+			Tuned TunedConfig `yaml:"tuned"`
 		}{
 			Enabled:       true,
 			SSDMethod:     "cipher",
@@ -108,10 +194,40 @@ func Default() *Config {
 			ChunkSize:     4 * 1024 * 1024, // 4MB
 			EnableTrim:    true,
 			MaxConcurrent: 2,
-			MaxSpeedMBps:  100, // 100MB/s по умолчанию
+			MaxSpeedMBps:  100, // 100MB/s по умолчанию (суммарно на все диски)
+			DiskSpeedMBps: 0,   // Без отдельного лимита на диск
 			MaxDuration:   "2h",
 			FileDelayMs:   100,
 			TargetDrive:   "",
+			StateDir:      DefaultStateDir,
+
+			DirectIO: false,
+
+			Adaptive: AdaptiveThrottleConfig{
+				Enabled:        false,
+				MinSpeedMBps:   10,
+				MaxSpeedMBps:   0, // Верхняя граница - max_speed_mbps
+				WriteLatencyMs: 40,
+				SyncLatencyMs:  50,
+				MaxIOPressure:  20,
+				Interval:       "2s",
+			},
+
+			Verify: VerifyConfig{
+				Enabled:    false,
+				SampleRate: 0.05, // 5% блоков каждого файла
+			},
+
+			MetadataScrub: MetadataScrubConfig{
+				Enabled:     false,
+				MaxFiles:    200000,
+				MaxDuration: "10m",
+				MaxFileSize: 700, // Резидентные данные MFT и inline data ext4 меньше
+			},
+
+			Schedule: ScheduleConfig{
+				Enabled: false, // Затирание в любое время
+			},
 		},
 		Logging: struct {
 			Level       string `yaml:"level"`
@@ -163,14 +279,21 @@ func Default() *Config {
 
 // Load загружает конфигурацию из файла
 func Load(path string) (*Config, error) {
+	return LoadWithProfile(path, "")
+}
+
+// LoadWithProfile загружает конфигурацию из файла и применяет профиль
+// производительности; валидация выполняется после применения профиля.
+// Пустой profile - без профиля.
+func LoadWithProfile(path, profile string) (*Config, error) {
 	if path == "" {
-		return Default(), nil
+		return defaultWithProfile(profile)
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return Default(), nil
+			return defaultWithProfile(profile)
 		}
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
@@ -180,14 +303,43 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
 
+	if profile != "" {
+		if err := ApplyProfile(&config, profile); err != nil {
+			return nil, fmt.Errorf("ошибка применения профиля %s: %w", profile, err)
+		}
+	}
+
 	// Валидация конфигурации
 	if err := Validate(&config); err != nil {
+		if profile != "" {
+			return nil, fmt.Errorf("invalid configuration with profile %s: %w", profile, err)
+		}
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	return &config, nil
 }
 
+// defaultWithProfile возвращает конфигурацию по умолчанию с профилем;
+// валидация - как у конфигурации из файла
+func defaultWithProfile(profile string) (*Config, error) {
+	config := Default()
+	if profile != "" {
+		if err := ApplyProfile(config, profile); err != nil {
+			return nil, fmt.Errorf("ошибка применения профиля %s: %w", profile, err)
+		}
+	}
+
+	if err := Validate(config); err != nil {
+		if profile != "" {
+			return nil, fmt.Errorf("invalid configuration with profile %s: %w", profile, err)
+		}
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
 // Validate проверяет конфигурацию на валидность
 func Validate(config *Config) error {
 	// Валидация security секции
@@ -225,6 +377,9 @@ func Validate(config *Config) error {
 		if config.Wipe.MaxSpeedMBps > 1000 { // 1GB/s max
 			return fmt.Errorf("max speed too high (max 1000MB/s), got %f", config.Wipe.MaxSpeedMBps)
 		}
+		if config.Wipe.DiskSpeedMBps < 0 || config.Wipe.DiskSpeedMBps > 1000 {
+			return fmt.Errorf("max disk speed must be between 0 and 1000MB/s, got %f", config.Wipe.DiskSpeedMBps)
+		}
 
 		// Проверяем duration
 		if config.Wipe.MaxDuration != "" {
@@ -238,17 +393,58 @@ func Validate(config *Config) error {
 			return fmt.Errorf("file delay must be between 0 and 60000ms, got %d", config.Wipe.FileDelayMs)
 		}
 
-		// Валидация методов
-		validMethods := map[string]bool{
-			"random": true,
-			"zeros":  true,
-			"cipher": true,
+		// Проверяем адаптивное ограничение скорости
+		if a := config.Wipe.Adaptive; a.Enabled {
+			if a.MinSpeedMBps <= 0 {
+				return fmt.Errorf("adaptive min speed must be positive, got %f", a.MinSpeedMBps)
+			}
+			if a.MaxSpeedMBps != 0 && a.MaxSpeedMBps < a.MinSpeedMBps {
+				return fmt.Errorf("adaptive max speed %f is below min speed %f", a.MaxSpeedMBps, a.MinSpeedMBps)
+			}
+			if a.WriteLatencyMs < 0 || a.SyncLatencyMs < 0 || a.MaxIOPressure < 0 || a.MaxIOPressure > 100 {
+				return fmt.Errorf("adaptive latency and pressure thresholds must be non-negative (pressure up to 100)")
+			}
+			if a.Interval != "" {
+				if _, err := time.ParseDuration(a.Interval); err != nil {
+					return fmt.Errorf("invalid adaptive interval format: %s", a.Interval)
+				}
+			}
 		}
-		if !validMethods[config.Wipe.SSDMethod] {
-			return fmt.Errorf("invalid SSD method: %s", config.Wipe.SSDMethod)
+
+		// Проверяем настройки проверки чтением
+		if v := config.Wipe.Verify; v.Enabled && (v.SampleRate <= 0 || v.SampleRate > 1) {
+			return fmt.Errorf("verify sample rate must be in (0, 1], got %f", v.SampleRate)
 		}
-		if !validMethods[config.Wipe.HDDMethod] {
-			return fmt.Errorf("invalid HDD method: %s", config.Wipe.HDDMethod)
+
+		// Проверяем очистку метаданных
+		if m := config.Wipe.MetadataScrub; m.Enabled {
+			if m.MaxFiles <= 0 {
+				return fmt.Errorf("metadata scrub max files must be positive, got %d", m.MaxFiles)
+			}
+			if m.MaxFileSize <= 0 || m.MaxFileSize > 64*1024 {
+				return fmt.Errorf("metadata scrub max file size must be between 1 and 65536 bytes, got %d", m.MaxFileSize)
+			}
+			if _, err := time.ParseDuration(m.MaxDuration); err != nil {
+				return fmt.Errorf("invalid metadata scrub duration format: %s", m.MaxDuration)
+			}
+		}
+
+		// Проверяем параметры профиля tuned
+		if t := config.Wipe.Tuned; t.ChunkSize < 0 || t.ChunkSize > 100*1024*1024 || t.SyncIntervalMB < 0 {
+			return fmt.Errorf("tuned chunk size must be between 0 and 100MB and sync interval non-negative")
+		}
+
+		// Проверяем окна обслуживания
+		if err := validateSchedule(config.Wipe.Schedule); err != nil {
+			return err
+		}
+
+		// Имена методов по реестру схем проверяет wipe.ValidateConfig
+		if config.Wipe.SSDMethod == "" {
+			return fmt.Errorf("invalid SSD method: empty method")
+		}
+		if config.Wipe.HDDMethod == "" {
+			return fmt.Errorf("invalid HDD method: empty method")
 		}
 	}
 
@@ -283,6 +479,14 @@ func Validate(config *Config) error {
 		}
 	}
 
+	// Валидация пользовательских методов и профилей
+	if err := validateMethods(config.Methods); err != nil {
+		return err
+	}
+	if err := validateProfiles(config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -327,13 +531,35 @@ func (config *Config) GetMaxDuration() time.Duration {
 
 // isAdmin проверяет права администратора
 func isAdmin() bool {
+	if runtime.GOOS != "windows" {
+		return os.Geteuid() == 0
+	}
+
 	// Упрощенная проверка
 	_, err := os.Open("\\\\.\\PHYSICALDRIVE0")
 	return err == nil
 }
 
+// defaultStateDir возвращает каталог состояния ОС: %ProgramData%\WipeDisk\state
+// в Windows, /var/lib/wipedisk в Linux
+func defaultStateDir() string {
+	if runtime.GOOS != "windows" {
+		return "/var/lib/wipedisk"
+	}
+
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		programData = `C:\ProgramData` // Fallback
+	}
+	return filepath.Join(programData, "WipeDisk", "state")
+}
+
 // getSystemDrive возвращает системный диск (C:, D:, и т.д.)
 func getSystemDrive() string {
+	if runtime.GOOS != "windows" {
+		return "/"
+	}
+
 	// Получаем путь к системной директории
 	windir := os.Getenv("WINDIR")
 	if windir == "" {
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Типы проходов пользовательского метода
+const (
+	PassTypeFixed      = "fixed"      // Один байт
+	PassTypeSequence   = "sequence"   // Повторяющаяся последовательность байт
+	PassTypeComplement = "complement" // Инверсия предыдущего прохода или bytes
+	PassTypeRandom     = "random"     // Случайные данные
+)
+
+const (
+	// maxMethodPasses - наибольшее число проходов пользовательского метода
+	maxMethodPasses = 100
+
+	// maxSequenceLength - наибольшая длина последовательности прохода
+	maxSequenceLength = 4096
+)
+
+// MethodConfig пользовательский метод затирания из секции methods -
+// последовательность проходов, доступная по имени в ssd_method, hdd_method
+// и --method
+type MethodConfig struct {
+	Description string       `yaml:"description"`
+	ChunkSize   int          `yaml:"chunk_size"` // Размер блока записи, 0 - 16 МБ
+	Passes      []MethodPass `yaml:"passes"`
+}
+
+// MethodPass проход пользовательского метода
+type MethodPass struct {
+	Type   string `yaml:"type"`   // fixed, sequence, complement, random
+	Byte   *int   `yaml:"byte"`   // fixed: байт 0x00-0xFF
+	Bytes  []int  `yaml:"bytes"`  // sequence: последовательность; complement: инвертируемые байты (пусто - предыдущий проход)
+	Verify bool   `yaml:"verify"` // Проверить проход чтением целиком
+}
+
+// validateMethods проверяет определения пользовательских методов
+func validateMethods(methods map[string]MethodConfig) error {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		m := methods[name]
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("method name cannot be empty")
+		}
+		if m.ChunkSize < 0 || m.ChunkSize > 100*1024*1024 {
+			return fmt.Errorf("method %s: chunk size must be between 0 and 100MB, got %d", name, m.ChunkSize)
+		}
+		if len(m.Passes) == 0 || len(m.Passes) > maxMethodPasses {
+			return fmt.Errorf("method %s: number of passes must be between 1 and %d, got %d", name, maxMethodPasses, len(m.Passes))
+		}
+		for i := range m.Passes {
+			if err := validateMethodPass(m.Passes, i); err != nil {
+				return fmt.Errorf("method %s, pass %d: %w", name, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMethodPass проверяет проход i метода
+func validateMethodPass(passes []MethodPass, i int) error {
+	p := passes[i]
+	for _, b := range p.Bytes {
+		if b < 0 || b > 0xFF {
+			return fmt.Errorf("byte %d out of range 0x00-0xFF", b)
+		}
+	}
+
+	switch p.Type {
+	case PassTypeFixed:
+		if p.Byte == nil || len(p.Bytes) > 0 {
+			return fmt.Errorf("fixed pass requires byte and no bytes")
+		}
+		if *p.Byte < 0 || *p.Byte > 0xFF {
+			return fmt.Errorf("byte %d out of range 0x00-0xFF", *p.Byte)
+		}
+	case PassTypeSequence:
+		if p.Byte != nil || len(p.Bytes) == 0 || len(p.Bytes) > maxSequenceLength {
+			return fmt.Errorf("sequence pass requires 1 to %d bytes and no byte", maxSequenceLength)
+		}
+	case PassTypeComplement:
+		if p.Byte != nil || len(p.Bytes) > maxSequenceLength {
+			return fmt.Errorf("complement pass takes up to %d bytes and no byte", maxSequenceLength)
+		}
+		if len(p.Bytes) == 0 && (i == 0 || passes[i-1].Type == PassTypeRandom) {
+			return fmt.Errorf("complement pass without bytes requires a non-random previous pass")
+		}
+	case PassTypeRandom:
+		if p.Byte != nil || len(p.Bytes) > 0 {
+			return fmt.Errorf("random pass takes no byte or bytes")
+		}
+	default:
+		return fmt.Errorf("unknown pass type %q (expected fixed, sequence, complement or random)", p.Type)
+	}
+	return nil
+}
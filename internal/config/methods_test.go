@@ -0,0 +1,94 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string // Пусто - метод корректен
+	}{
+		{"все типы проходов", `
+corp:
+  description: Corporate
+  chunk_size: 1048576
+  passes:
+    - {type: fixed, byte: 0x00}
+    - {type: complement}
+    - {type: sequence, bytes: [0x92, 0x49, 0x24]}
+    - {type: complement, bytes: [0x0F]}
+    - {type: random, verify: true}`, ""},
+		{"неизвестный тип", `
+corp:
+  passes: [{type: zero}]`, `method corp, pass 1: unknown pass type "zero"`},
+		{"без проходов", `
+corp:
+  description: empty`, "method corp: number of passes must be between 1 and 100, got 0"},
+		{"fixed без byte", `
+corp:
+  passes: [{type: fixed}]`, "fixed pass requires byte"},
+		{"fixed с bytes", `
+corp:
+  passes: [{type: fixed, byte: 1, bytes: [2]}]`, "fixed pass requires byte"},
+		{"байт вне диапазона", `
+corp:
+  passes: [{type: fixed, byte: 256}]`, "byte 256 out of range"},
+		{"отрицательный байт последовательности", `
+corp:
+  passes: [{type: sequence, bytes: [1, -1]}]`, "byte -1 out of range"},
+		{"пустая последовательность", `
+corp:
+  passes: [{type: sequence}]`, "sequence pass requires 1 to 4096 bytes"},
+		{"инверсия первым проходом", `
+corp:
+  passes: [{type: complement}]`, "complement pass without bytes requires a non-random previous pass"},
+		{"инверсия после random", `
+corp:
+  passes: [{type: random}, {type: complement}]`, "method corp, pass 2: complement pass without bytes"},
+		{"random с байтом", `
+corp:
+  passes: [{type: random, byte: 0}]`, "random pass takes no byte or bytes"},
+		{"размер блока", `
+corp:
+  chunk_size: 209715200
+  passes: [{type: random}]`, "method corp: chunk size must be between 0 and 100MB"},
+		{"пустое имя", `
+" ":
+  passes: [{type: random}]`, "method name cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var methods map[string]MethodConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &methods); err != nil {
+				t.Fatal(err)
+			}
+			err := validateMethods(methods)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateMethods: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateMethods: ошибка %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMethodsTooManyPasses(t *testing.T) {
+	passes := make([]MethodPass, maxMethodPasses+1)
+	for i := range passes {
+		passes[i].Type = PassTypeRandom
+	}
+	err := validateMethods(map[string]MethodConfig{"corp": {Passes: passes}})
+	if err == nil || !strings.Contains(err.Error(), "number of passes") {
+		t.Errorf("validateMethods: ошибка %v", err)
+	}
+}
@@ -1,37 +1,71 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
 )
 
-// ApplyProfile применяет профиль производительности к конфигурации
-func ApplyProfile(cfg *Config, profile string) error {
-	switch profile {
-	case "safe":
+// TunedProfile - профиль с параметрами записи, подобранными wipedisk benchmark
+const TunedProfile = "tuned"
+
+// ProfileConfig пользовательский профиль из секции profiles: переопределяет
+// поля секции wipe поверх базового профиля inherits или, без него, поверх
+// файла конфигурации
+type ProfileConfig struct {
+	Inherits    string    `yaml:"inherits"`       // Базовый профиль (встроенный или пользовательский)
+	Description string    `yaml:"description"`    // Описание для wipedisk profiles list
+	Wipe        yaml.Node `yaml:"wipe,omitempty"` // Переопределяемые поля секции wipe
+}
+
+// builtinProfile встроенный профиль производительности
+type builtinProfile struct {
+	name        string
+	description string
+	apply       func(cfg *Config) error
+}
+
+// builtinProfiles встроенные профили в порядке вывода
+var builtinProfiles = []builtinProfile{
+	{"safe", "Бережное затирание с низкой нагрузкой на систему", func(cfg *Config) error {
 		cfg.Wipe.MaxSpeedMBps = 10
 		cfg.Wipe.ChunkSize = 8 * 1024 * 1024 // 8MB
 		cfg.Wipe.FileDelayMs = 500
 		cfg.Wipe.SSDPasses = 1
 		cfg.Wipe.HDDPasses = 1
-	case "balanced":
+		cfg.Wipe.DirectIO = true
+		return nil
+	}},
+	{"balanced", "Умеренная скорость, три прохода на HDD", func(cfg *Config) error {
 		cfg.Wipe.MaxSpeedMBps = 25
 		cfg.Wipe.ChunkSize = 32 * 1024 * 1024 // 32MB
 		cfg.Wipe.FileDelayMs = 200
 		cfg.Wipe.SSDPasses = 1
 		cfg.Wipe.HDDPasses = 3
-	case "aggressive":
-		cfg.Wipe.MaxSpeedMBps = 0              // unlimited
-		cfg.Wipe.ChunkSize = 128 * 1024 * 1024 // 128MB
+		cfg.Wipe.DirectIO = false
+		return nil
+	}},
+	{"aggressive", "Без ограничения скорости, больше проходов", func(cfg *Config) error {
+		cfg.Wipe.MaxSpeedMBps = 0             // unlimited
+		cfg.Wipe.ChunkSize = 64 * 1024 * 1024 // 64MB
 		cfg.Wipe.FileDelayMs = 0
 		cfg.Wipe.SSDPasses = 2
 		cfg.Wipe.HDDPasses = 5
-	case "fast":
+		cfg.Wipe.DirectIO = true
+		return nil
+	}},
+	{"fast", "Без ограничения скорости, один проход", func(cfg *Config) error {
 		cfg.Wipe.MaxSpeedMBps = 0              // unlimited
-		cfg.Wipe.ChunkSize = 256 * 1024 * 1024 // 256MB
+		cfg.Wipe.ChunkSize = 100 * 1024 * 1024 // 100MB - предел chunk_size
 		cfg.Wipe.FileDelayMs = 0
 		cfg.Wipe.SSDPasses = 1
 		cfg.Wipe.HDDPasses = 1
-	case "sdelete":
+		cfg.Wipe.DirectIO = true
+		return nil
+	}},
+	{"sdelete", "Совместимость с SDelete", func(cfg *Config) error {
 		cfg.Wipe.SSDMethod = "sdelete-compatible"
 		cfg.Wipe.HDDMethod = "sdelete-compatible"
 		cfg.Wipe.SSDPasses = 1
@@ -40,8 +74,160 @@ func ApplyProfile(cfg *Config, profile string) error {
 		cfg.Wipe.ChunkSize = 64 * 1024 * 1024 // 64MB
 		cfg.Wipe.FileDelayMs = 100
 		cfg.Wipe.EnableTrim = true
-	default:
-		return fmt.Errorf("неизвестный профиль: %s", profile)
+		cfg.Wipe.DirectIO = false
+		return nil
+	}},
+	{TunedProfile, "Параметры записи из wipedisk benchmark --save", func(cfg *Config) error {
+		// Размер блока и период fsync применяет движок затирания из wipe.tuned
+		if cfg.Wipe.Tuned.ChunkSize <= 0 {
+			return fmt.Errorf("профиль %s не настроен: выполните wipedisk benchmark --save", TunedProfile)
+		}
+		cfg.Wipe.ChunkSize = int64(cfg.Wipe.Tuned.ChunkSize)
+		cfg.Wipe.DirectIO = cfg.Wipe.Tuned.DirectIO
+		cfg.Wipe.FileDelayMs = 0
+		return nil
+	}},
+}
+
+// findBuiltinProfile возвращает встроенный профиль по имени
+func findBuiltinProfile(name string) *builtinProfile {
+	for i := range builtinProfiles {
+		if builtinProfiles[i].name == name {
+			return &builtinProfiles[i]
+		}
+	}
+	return nil
+}
+
+// ProfileInfo описание профиля для вывода списка
+type ProfileInfo struct {
+	Name        string
+	Builtin     bool
+	Inherits    string
+	Description string
+}
+
+// Profiles возвращает встроенные профили и затем пользовательские по имени
+func Profiles(cfg *Config) []ProfileInfo {
+	var list []ProfileInfo
+	for _, p := range builtinProfiles {
+		list = append(list, ProfileInfo{Name: p.name, Builtin: true, Description: p.description})
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		p := cfg.Profiles[name]
+		list = append(list, ProfileInfo{Name: name, Inherits: p.Inherits, Description: p.Description})
+	}
+	return list
+}
+
+// ProfileChain возвращает цепочку наследования профиля: сам профиль, его
+// базовый профиль и так далее до корня
+func ProfileChain(cfg *Config, name string) ([]string, error) {
+	var chain []string
+	for name != "" {
+		if slices.Contains(chain, name) {
+			return nil, fmt.Errorf("циклическое наследование профилей: %v", append(chain, name))
+		}
+		chain = append(chain, name)
+
+		if p, ok := cfg.Profiles[name]; ok {
+			name = p.Inherits
+			continue
+		}
+		if findBuiltinProfile(name) == nil {
+			if len(chain) > 1 {
+				return nil, fmt.Errorf("профиль %s наследует неизвестный профиль %s", chain[len(chain)-2], name)
+			}
+			return nil, fmt.Errorf("неизвестный профиль: %s", name)
+		}
+		break
+	}
+	return chain, nil
+}
+
+// BaseProfile возвращает встроенный профиль в корне цепочки наследования
+// или пустую строку, если профиль не наследует встроенный. По нему
+// стратегии выбирают размер файлов затирания.
+func BaseProfile(cfg *Config, name string) string {
+	chain, err := ProfileChain(cfg, name)
+	if err != nil || len(chain) == 0 {
+		return ""
+	}
+	root := chain[len(chain)-1]
+	if _, ok := cfg.Profiles[root]; ok {
+		return ""
+	}
+	return root
+}
+
+// ApplyProfile применяет профиль производительности к конфигурации: сначала
+// корневой профиль цепочки наследования, затем переопределения потомков.
+// Валидация итоговой конфигурации - на вызывающей стороне.
+func ApplyProfile(cfg *Config, profile string) error {
+	chain, err := ProfileChain(cfg, profile)
+	if err != nil {
+		return err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		name := chain[i]
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			if err := findBuiltinProfile(name).apply(cfg); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := decodeWipeOverrides(&p.Wipe, cfg); err != nil {
+			return fmt.Errorf("профиль %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// decodeWipeOverrides накладывает поля узла node на секцию wipe. Заданные
+// поля заменяются, вложенные секции объединяются; неизвестные поля - ошибка.
+func decodeWipeOverrides(node *yaml.Node, cfg *Config) error {
+	if node.Kind == 0 || node.Tag == "!!null" {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("wipe must be a mapping")
+	}
+	if _, tuned := mappingEntry(node, "tuned"); tuned != nil {
+		return fmt.Errorf("wipe.tuned is written by wipedisk benchmark and cannot be overridden")
+	}
+
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(&cfg.Wipe)
+}
+
+// validateProfiles проверяет определения пользовательских профилей:
+// имена, наследование и поля переопределений
+func validateProfiles(config *Config) error {
+	for name, p := range config.Profiles {
+		if name == "" {
+			return fmt.Errorf("profile name cannot be empty")
+		}
+		if findBuiltinProfile(name) != nil {
+			return fmt.Errorf("profile %s: name is reserved for a built-in profile", name)
+		}
+		if _, err := ProfileChain(config, name); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		scratch := *config
+		if err := decodeWipeOverrides(&p.Wipe, &scratch); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseConfig разбирает YAML поверх конфигурации по умолчанию, как Load
+func parseConfig(t *testing.T, src string) *Config {
+	t.Helper()
+	cfg := Default()
+	if err := yaml.Unmarshal([]byte(src), cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+const testProfiles = `
+wipe:
+  max_speed_mbps: 40
+  file_delay_ms: 300
+profiles:
+  night:
+    inherits: safe
+    description: Ночное окно
+    wipe:
+      max_speed_mbps: 5
+      adaptive:
+        enabled: true
+  deep:
+    inherits: night
+    wipe:
+      hdd_passes: 7
+      adaptive:
+        min_speed_mbps: 2
+  solo:
+    wipe:
+      file_delay_ms: 1
+  loop-a:
+    inherits: loop-b
+  loop-b:
+    inherits: loop-a
+  self:
+    inherits: self
+  orphan:
+    inherits: missing
+`
+
+func TestProfileChain(t *testing.T) {
+	cfg := parseConfig(t, testProfiles)
+
+	tests := []struct {
+		name     string
+		profile  string
+		want     []string
+		wantBase string
+		wantErr  string
+	}{
+		{"встроенный", "fast", []string{"fast"}, "fast", ""},
+		{"наследует встроенный", "night", []string{"night", "safe"}, "safe", ""},
+		{"цепочка", "deep", []string{"deep", "night", "safe"}, "safe", ""},
+		{"без базового", "solo", []string{"solo"}, "", ""},
+		{"цикл", "loop-a", nil, "", "циклическое наследование профилей: [loop-a loop-b loop-a]"},
+		{"наследует себя", "self", nil, "", "циклическое наследование профилей: [self self]"},
+		{"неизвестный базовый", "orphan", nil, "", "профиль orphan наследует неизвестный профиль missing"},
+		{"неизвестный", "missing", nil, "", "неизвестный профиль: missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ProfileChain(cfg, tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ProfileChain(%q): ошибка %v, want %q", tt.profile, err, tt.wantErr)
+				}
+			} else if err != nil || !reflect.DeepEqual(chain, tt.want) {
+				t.Errorf("ProfileChain(%q) = %v, %v; want %v", tt.profile, chain, err, tt.want)
+			}
+			if got := BaseProfile(cfg, tt.profile); got != tt.wantBase {
+				t.Errorf("BaseProfile(%q) = %q, want %q", tt.profile, got, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestApplyProfileInheritance(t *testing.T) {
+	type result struct {
+		MaxSpeedMBps float64
+		FileDelayMs  int
+		HDDPasses    int
+		ChunkSize    int64
+		DirectIO     bool
+		Adaptive     bool
+		AdaptiveMin  float64
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    result
+	}{
+		// safe: 10 МБ/с, блок 8 МБ, пауза 500 мс, 1 проход, direct I/O;
+		// нижняя граница адаптивного регулятора по умолчанию - 10 МБ/с
+		{"встроенный", "safe", result{10, 500, 1, 8 << 20, true, false, 10}},
+		{"потомок переопределяет поле", "night", result{5, 500, 1, 8 << 20, true, true, 10}},
+		{"вложенные секции объединяются", "deep", result{5, 500, 7, 8 << 20, true, true, 2}},
+		{"поверх файла конфигурации", "solo", result{40, 1, 1, Default().Wipe.ChunkSize, Default().Wipe.DirectIO, false, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseConfig(t, testProfiles)
+			if err := ApplyProfile(cfg, tt.profile); err != nil {
+				t.Fatal(err)
+			}
+			w := cfg.Wipe
+			got := result{w.MaxSpeedMBps, w.FileDelayMs, w.HDDPasses, w.ChunkSize, w.DirectIO, w.Adaptive.Enabled, w.Adaptive.MinSpeedMBps}
+			if got != tt.want {
+				t.Errorf("ApplyProfile(%q):\n got %+v\nwant %+v", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string // Пусто - профили корректны
+	}{
+		{"корректный", `
+profiles:
+  night: {inherits: balanced, wipe: {max_speed_mbps: 5}}`, ""},
+		{"имя встроенного", `
+profiles:
+  fast: {wipe: {max_speed_mbps: 5}}`, "profile fast: name is reserved for a built-in profile"},
+		{"цикл", `
+profiles:
+  a: {inherits: a}`, "profile a: циклическое наследование"},
+		{"неизвестный базовый", `
+profiles:
+  a: {inherits: turbo}`, "profile a: профиль a наследует неизвестный профиль turbo"},
+		{"неизвестное поле", `
+profiles:
+  a: {wipe: {max_speed: 5}}`, "field max_speed not found"},
+		{"переопределение tuned", `
+profiles:
+  a: {wipe: {tuned: {chunk_size: 1048576}}}`, "wipe.tuned is written by wipedisk benchmark"},
+		{"wipe не секция", `
+profiles:
+  a: {wipe: [1, 2]}`, "wipe must be a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseConfig(t, tt.yaml)
+			err := validateProfiles(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateProfiles: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateProfiles: ошибка %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyProfileTuned(t *testing.T) {
+	cfg := Default()
+	if err := ApplyProfile(cfg, TunedProfile); err == nil {
+		t.Error("ApplyProfile(tuned) без замера: ожидалась ошибка")
+	}
+
+	cfg.Wipe.Tuned.ChunkSize = 4 << 20
+	cfg.Wipe.Tuned.DirectIO = true
+	if err := ApplyProfile(cfg, TunedProfile); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Wipe.ChunkSize != 4<<20 || !cfg.Wipe.DirectIO || cfg.Wipe.FileDelayMs != 0 {
+		t.Errorf("tuned: ChunkSize %d, DirectIO %v, FileDelayMs %d", cfg.Wipe.ChunkSize, cfg.Wipe.DirectIO, cfg.Wipe.FileDelayMs)
+	}
+}
+
+func TestLoadWithProfile(t *testing.T) {
+	// Полный файл конфигурации: значения по умолчанию с корректными профилями
+	base := parseConfig(t, `
+wipe:
+  max_speed_mbps: 40
+profiles:
+  night:
+    inherits: safe
+    wipe:
+      max_speed_mbps: 5
+  deep:
+    inherits: night
+    wipe:
+      hdd_passes: 7
+`)
+	base.Security.RequireAdmin = false
+	data, err := yaml.Marshal(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		noFile    bool // Файла конфигурации нет: профиль применяется к значениям по умолчанию
+		profile   string
+		wantSpeed float64
+		wantErr   string
+	}{
+		{"без профиля", false, "", 40, ""},
+		{"встроенный профиль", false, "fast", 0, ""},
+		{"пользовательский профиль", false, "deep", 5, ""},
+		{"неизвестный профиль", false, "missing", 0, "missing"},
+		{"нет файла: профиль проверяется", true, "tuned", 0, "wipedisk benchmark"},
+		{"нет файла: неизвестный профиль", true, "missing", 0, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if !tt.noFile {
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := LoadWithProfile(path, tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ошибка %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Wipe.MaxSpeedMBps != tt.wantSpeed {
+				t.Errorf("max_speed_mbps = %v, want %v", cfg.Wipe.MaxSpeedMBps, tt.wantSpeed)
+			}
+		})
+	}
+}
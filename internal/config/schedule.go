@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleConfig окна обслуживания: затирание выполняется только внутри
+// разрешенных окон, вне их приостанавливается и продолжается при открытии
+// следующего окна
+type ScheduleConfig struct {
+	Enabled bool             `yaml:"enabled" json:"enabled"`
+	Windows []ScheduleWindow `yaml:"windows" json:"windows,omitempty"`
+}
+
+// ScheduleWindow окно с from до to по местному времени в дни days.
+// Окно, у которого to не позже from, переходит через полночь и относится
+// к дню своего начала. Пустой days - каждый день.
+type ScheduleWindow struct {
+	Days []string `yaml:"days" json:"days,omitempty"` // mon, tue, wed, thu, fri, sat, sun
+	From string   `yaml:"from" json:"from"`           // "20:00"
+	To   string   `yaml:"to" json:"to"`               // "07:00"; "24:00" - до конца суток
+}
+
+// weekdays сопоставляет имена дней в конфигурации дням недели
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"sun": time.Sunday,
+}
+
+// Weekdays возвращает дни недели окна; пустой список - каждый день
+func (w ScheduleWindow) Weekdays() ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, len(w.Days))
+	for _, name := range w.Days {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule day: %s", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// Bounds возвращает начало и конец окна как смещение от полуночи
+func (w ScheduleWindow) Bounds() (from, to time.Duration, err error) {
+	if from, err = parseClock(w.From); err != nil {
+		return 0, 0, err
+	}
+	if to, err = parseClock(w.To); err != nil {
+		return 0, 0, err
+	}
+	if from == 24*time.Hour {
+		return 0, 0, fmt.Errorf("schedule window cannot start at 24:00")
+	}
+	return from, to, nil
+}
+
+// parseClock разбирает время суток в формате ЧЧ:ММ
+func parseClock(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("invalid schedule time %q, expected HH:MM", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid schedule time %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// validateSchedule проверяет окна обслуживания
+func validateSchedule(s ScheduleConfig) error {
+	if !s.Enabled {
+		return nil
+	}
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("schedule is enabled but has no windows")
+	}
+	for i, w := range s.Windows {
+		if _, err := w.Weekdays(); err != nil {
+			return fmt.Errorf("schedule window %d: %w", i+1, err)
+		}
+		if _, _, err := w.Bounds(); err != nil {
+			return fmt.Errorf("schedule window %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
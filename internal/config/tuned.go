@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SaveTuned записывает параметры профиля tuned в секцию wipe.tuned файла
+// конфигурации. Заменяются только строки этой секции, остальной файл с
+// комментариями и форматированием не меняется; если файла нет, он
+// создается из конфигурации по умолчанию.
+func SaveTuned(path string, tuned TunedConfig) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		cfg := Default()
+		cfg.Wipe.Tuned = tuned
+		data, err = yaml.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	newline := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var block bytes.Buffer
+	enc := yaml.NewEncoder(&block)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]TunedConfig{"tuned": tuned}); err != nil {
+		return fmt.Errorf("failed to marshal tuned profile: %w", err)
+	}
+	enc.Close()
+	tunedLines := strings.Split(strings.TrimSuffix(block.String(), "\n"), "\n")
+
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	wipeKey, wipeValue := mappingEntry(root, "wipe")
+	switch {
+	case wipeKey == nil:
+		// Секции wipe нет: добавляем ее в конец файла
+		lines = append(lines, "wipe:")
+		lines = append(lines, indentLines(tunedLines, "  ")...)
+	case wipeValue.Kind == yaml.MappingNode && wipeValue.Style&yaml.FlowStyle == 0 && len(wipeValue.Content) > 0:
+		indent := strings.Repeat(" ", wipeValue.Content[0].Column-1)
+		wipeEnd := entryEnd(lines, root, wipeKey, len(lines))
+		if tunedKey, _ := mappingEntry(wipeValue, "tuned"); tunedKey != nil {
+			start := tunedKey.Line - 1
+			end := entryEnd(lines, wipeValue, tunedKey, wipeEnd)
+			lines = append(lines[:start], append(indentLines(tunedLines, indent), lines[end:]...)...)
+		} else {
+			lines = append(lines[:wipeEnd], append(indentLines(tunedLines, indent), lines[wipeEnd:]...)...)
+		}
+	default:
+		return fmt.Errorf("config file %s: wipe section must be a block mapping", path)
+	}
+
+	result := strings.Join(lines, "\n") + "\n"
+	if err := yaml.Unmarshal([]byte(result), &Config{}); err != nil {
+		return fmt.Errorf("failed to update config file %s: %w", path, err)
+	}
+	return os.WriteFile(path, []byte(strings.ReplaceAll(result, "\n", newline)), 0644)
+}
+
+// mappingEntry возвращает узлы ключа и значения key словаря node
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// entryEnd возвращает индекс строки, следующей за записью key словаря
+// node: начало следующего ключа или limit. Пустые строки и комментарии
+// перед следующим ключом к записи не относятся.
+func entryEnd(lines []string, node, key *yaml.Node, limit int) int {
+	end := limit
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i] == key && i+2 < len(node.Content) {
+			end = node.Content[i+2].Line - 1
+		}
+	}
+	for end > key.Line {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// indentLines добавляет отступ к строкам
+func indentLines(lines []string, indent string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = indent + line
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSaveTuned(t *testing.T) {
+	tuned := TunedConfig{Volume: "/data", ChunkSize: 4 << 20, SyncIntervalMB: 64, DirectIO: true, ThroughputMBps: 512.5}
+	tests := []struct {
+		name     string
+		existing string // Содержимое файла до записи ("" - файла нет)
+		wantKeep []string
+		wantErr  bool
+	}{
+		{"файла нет", "", nil, false},
+		{"нет секции wipe", "# Комментарий\nlogging:\n  level: INFO\n", []string{"# Комментарий", "  level: INFO"}, false},
+		{"нет секции tuned", "wipe:\n  # Метод для SSD\n  ssd_method: random\nlogging:\n  level: DEBUG\n",
+			[]string{"  # Метод для SSD", "  ssd_method: random", "logging:\n  level: DEBUG"}, false},
+		{"замена tuned", "wipe:\n    tuned:\n        chunk_size: 1048576\n        volume: C:\\\n    ssd_method: random # после tuned\n",
+			[]string{"    ssd_method: random # после tuned"}, false},
+		{"переводы строк CRLF", "wipe:\r\n  ssd_method: random\r\n", []string{"  ssd_method: random\r\n"}, false},
+		{"wipe в строку", "wipe: {ssd_method: random}\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := SaveTuned(path, tuned)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, want %v", err, tt.wantErr)
+			}
+			data, _ := os.ReadFile(path)
+			if tt.wantErr {
+				if string(data) != tt.existing {
+					t.Error("файл изменен при ошибке")
+				}
+				return
+			}
+
+			for _, keep := range tt.wantKeep {
+				if !strings.Contains(string(data), keep) {
+					t.Errorf("потеряна строка %q:\n%s", keep, data)
+				}
+			}
+			if strings.Contains(tt.existing, "\r\n") != strings.Contains(string(data), "\r\n") {
+				t.Error("изменились переводы строк")
+			}
+			if strings.Contains(tt.existing, "tuned:") && strings.Count(string(data), "tuned:") != 1 {
+				t.Error("старая секция tuned не заменена")
+			}
+
+			var cfg Config
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Wipe.Tuned != tuned {
+				t.Errorf("wipe.tuned = %+v, want %+v", cfg.Wipe.Tuned, tuned)
+			}
+		})
+	}
+}
@@ -44,6 +44,8 @@ type PhaseResult struct {
 	BytesCleaned uint64           `json:"bytes_cleaned,omitempty"`
 	StartTime    time.Time        `json:"start_time"`
 	EndTime      time.Time        `json:"end_time"`
+
+	ActiveIntervals []wipe.ActiveInterval `json:"active_intervals,omitempty"` // Интервалы работы затирания в окнах обслуживания
 }
 
 // MaintenanceReport содержит отчёт о выполнении плана
@@ -65,6 +67,9 @@ type MaintenanceOrchestrator struct {
 	config  *config.Config
 	dryRun  bool
 	verbose bool
+
+	// Пауза затирания вне окон обслуживания; время паузы не входит в таймауты плана и фаз
+	pause *wipe.PauseController
 }
 
 // NewMaintenanceOrchestrator создает новый оркестратор
@@ -74,6 +79,7 @@ func NewMaintenanceOrchestrator(cfg *config.Config, logger *logging.EnterpriseLo
 		config:  cfg,
 		dryRun:  dryRun,
 		verbose: verbose,
+		pause:   wipe.NewPauseController(),
 	}
 }
 
@@ -87,8 +93,13 @@ func (mo *MaintenanceOrchestrator) ExecutePlan(ctx context.Context, plan *Mainte
 
 	startTime := time.Now()
 
+	// Освобождаем место, занятое файлами аварийно завершенных запусков затирания
+	if _, err := wipe.RecoverAllVolumes(ctx, mo.config.Wipe.StateDir, mo.dryRun, mo.logger); err != nil {
+		mo.logger.Log("WARN", "Восстановление артефактов не выполнено", "error", err.Error())
+	}
+
 	// Создаем контекст с таймаутом для всего плана
-	planCtx, cancel := context.WithTimeout(ctx, plan.Timeout)
+	planCtx, cancel := mo.pause.WithDuration(ctx, plan.Timeout)
 	defer cancel()
 
 	report := &MaintenanceReport{
@@ -191,7 +202,7 @@ func (mo *MaintenanceOrchestrator) executePhase(ctx context.Context, phase Maint
 		phaseTimeout = 1 * time.Hour
 	}
 
-	phaseCtx, cancel := context.WithTimeout(ctx, phaseTimeout)
+	phaseCtx, cancel := mo.pause.WithDuration(ctx, phaseTimeout)
 	defer cancel()
 
 	var err error
@@ -216,6 +227,9 @@ func (mo *MaintenanceOrchestrator) executePhase(ctx context.Context, phase Maint
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
 	result.BytesCleaned = bytesCleaned
+	if phase == PhaseWipeFreeSpace && !mo.dryRun {
+		result.ActiveIntervals = mo.pause.ActiveIntervals(result.StartTime, result.EndTime)
+	}
 
 	if err != nil {
 		result.Status = "FAILED"
@@ -277,6 +291,15 @@ func (mo *MaintenanceOrchestrator) wipeFreeSpace(ctx context.Context, plan *Main
 		return 0, fmt.Errorf("ошибка получения дисков: %w", err)
 	}
 
+	// Вне окон обслуживания затирание приостанавливается до открытия следующего окна
+	schedule, err := wipe.NewWipeSchedule(mo.config.Wipe.Schedule)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка расписания затирания: %w", err)
+	}
+	scheduleCtx, stopSchedule := context.WithCancel(ctx)
+	defer stopSchedule()
+	schedule.Start(scheduleCtx, mo.pause, mo.logger)
+
 	var totalWiped uint64
 	for _, disk := range disks {
 		// Пропускаем системный диск без явного разрешения
@@ -286,7 +309,7 @@ func (mo *MaintenanceOrchestrator) wipeFreeSpace(ctx context.Context, plan *Main
 		}
 
 		// Выполняем затирание
-		op := wipe.WipeWithStrategy(ctx, disk, mo.config, mo.logger, false, 0, wipe.ModeStandard, "balanced")
+		op := wipe.WipeWithStrategy(ctx, disk, mo.config, mo.logger, false, 0, wipe.ModeStandard, "balanced", nil, nil, mo.pause)
 		if op.Status == "COMPLETED" {
 			totalWiped += op.BytesWiped
 		} else if op.Error != "" {
@@ -303,7 +326,7 @@ func (mo *MaintenanceOrchestrator) optimizeDisk(ctx context.Context) (uint64, er
 		return 0, nil
 	}
 
-	// Заглушка - реальная реализация будет вызывать defrag/TRIM
+	// TRIM для SSD; дефрагментация HDD пока не реализована
 	return system.OptimizeDisks(ctx, mo.logger)
 }
 
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package maintenance
+
+import "fmt"
+
+// emptyRecycleBin не поддерживается вне Windows
+func emptyRecycleBin() error {
+	return fmt.Errorf("очистка корзины поддерживается только в Windows")
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package maintenance
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// emptyRecycleBin очищает корзину через Shell32.dll
+func emptyRecycleBin() error {
+	// Загружаем Shell32.dll
+	shell32, err := windows.LoadDLL("shell32.dll")
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки shell32.dll: %w", err)
+	}
+
+	// Получаем процедуру SHEmptyRecycleBinW
+	emptyRecycleBin, err := shell32.FindProc("SHEmptyRecycleBinW")
+	if err != nil {
+		return fmt.Errorf("ошибка поиска процедуры SHEmptyRecycleBinW: %w", err)
+	}
+
+	// Вызываем SHEmptyRecycleBinW
+	// Параметры: hwnd, pszRootPath, dwFlags
+	// Используем SHERB_NOCONFIRMATION | SHERB_NOPROGRESSUI | SHERB_NOSOUND
+	const (
+		SHERB_NOCONFIRMATION = 0x00000001
+		SHERB_NOPROGRESSUI   = 0x00000002
+		SHERB_NOSOUND        = 0x00000004
+	)
+
+	ret, _, err := emptyRecycleBin.Call(
+		0, // hwnd (null)
+		0, // pszRootPath (null - все диски)
+		uintptr(SHERB_NOCONFIRMATION|SHERB_NOPROGRESSUI|SHERB_NOSOUND),
+	)
+
+	if ret != 0 {
+		// Возвращаемое значение 0 означает успех
+		return fmt.Errorf("ошибка очистки корзины: %v", err)
+	}
+
+	return nil
+}
@@ -38,6 +38,8 @@ type VerificationReport struct {
 	Method            string                `json:"method"`
 	Passes            int                   `json:"passes"`
 	SuccessRate       float64               `json:"success_rate"`
+
+	ReadBack *wipe.VerifyStats `json:"read_back,omitempty"` // Проверка чтением во время затирания
 }
 
 // VerificationAnomaly описывает аномалию при проверке
@@ -135,6 +137,8 @@ func (pv *PhysicalVerifier) VerifyReport(ctx context.Context, report *wipe.WipeO
 		err = pv.performAggressiveVerification(verifyCtx, report, verificationReport)
 	}
 
+	pv.addReadBackResults(report, verificationReport)
+
 	if err != nil {
 		verificationReport.Anomalies = append(verificationReport.Anomalies, VerificationAnomaly{
 			Type:        "verification_error",
@@ -151,6 +155,9 @@ func (pv *PhysicalVerifier) VerifyReport(ctx context.Context, report *wipe.WipeO
 	verificationReport.Compliance = pv.determineCompliance(verificationReport)
 
 	verificationReport.WipeVerified = verificationReport.SuccessRate >= 95.0
+	if rb := verificationReport.ReadBack; rb != nil && rb.MismatchBlocks > 0 {
+		verificationReport.WipeVerified = false // Данные на диске не совпали с записанными
+	}
 
 	pv.logger.Log("INFO", "Верификация завершена",
 		"verified", verificationReport.WipeVerified,
@@ -160,6 +167,32 @@ func (pv *PhysicalVerifier) VerifyReport(ctx context.Context, report *wipe.WipeO
 	return verificationReport, nil
 }
 
+// addReadBackResults переносит итоги проверки чтением, выполненной при затирании
+func (pv *PhysicalVerifier) addReadBackResults(report *wipe.WipeOperation, vr *VerificationReport) {
+	stats := report.Verification
+	if stats == nil {
+		return
+	}
+	vr.ReadBack = stats
+
+	if stats.MismatchBlocks > 0 {
+		vr.Anomalies = append(vr.Anomalies, VerificationAnomaly{
+			Type:        "readback_mismatch",
+			Description: fmt.Sprintf("При проверке чтением не совпали %d блоков (%d байт) из %d", stats.MismatchBlocks, stats.MismatchBytes, stats.BlocksChecked),
+			Location:    report.Disk,
+			Severity:    "high",
+		})
+	}
+	if stats.ReadErrors > 0 {
+		vr.Anomalies = append(vr.Anomalies, VerificationAnomaly{
+			Type:        "readback_read_error",
+			Description: fmt.Sprintf("Ошибок чтения при проверке: %d", stats.ReadErrors),
+			Location:    report.Disk,
+			Severity:    "medium",
+		})
+	}
+}
+
 // performBasicVerification выполняет базовую проверку
 func (pv *PhysicalVerifier) performBasicVerification(ctx context.Context, report *wipe.WipeOperation, vr *VerificationReport) error {
 	pv.logger.Log("INFO", "Выполнение базовой верификации")
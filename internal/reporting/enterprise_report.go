@@ -226,7 +226,7 @@ func analyzeOperations(operations []*wipe.WipeOperation) []SecurityCategory {
 		if op.Error != "" {
 			// Failed operations are critical findings
 			dataRemnants.Files = append(dataRemnants.Files, FileExample{
-				Path:         op.Disk,
+				Path:         op.Target(),
 				Size:         formatBytes(op.BytesWiped),
 				ModifiedDate: op.StartTime,
 				RiskFactors:  []string{"Сбой операции стирания", "Данные потенциально не стерты"},
@@ -235,7 +235,7 @@ func analyzeOperations(operations []*wipe.WipeOperation) []SecurityCategory {
 		} else if op.Warning != "" {
 			// Operations with warnings
 			systemArtifacts.Files = append(systemArtifacts.Files, FileExample{
-				Path:         op.Disk,
+				Path:         op.Target(),
 				Size:         formatBytes(op.BytesWiped),
 				ModifiedDate: op.StartTime,
 				RiskFactors:  []string{"Частичное стирание", "Предупреждение во время операции"},
@@ -244,7 +244,7 @@ func analyzeOperations(operations []*wipe.WipeOperation) []SecurityCategory {
 			// Successful operations - check for potential issues
 			if op.BytesWiped < 1024*1024 { // Less than 1MB
 				tempFiles.Files = append(tempFiles.Files, FileExample{
-					Path:         op.Disk,
+					Path:         op.Target(),
 					Size:         formatBytes(op.BytesWiped),
 					ModifiedDate: op.StartTime,
 					RiskFactors:  []string{"Малый размер данных", "Потенциально неполное стирание"},
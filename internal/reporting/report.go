@@ -29,18 +29,51 @@ type Report struct {
 
 // OperationReport представляет отчёт об операции затирания
 type OperationReport struct {
-	ID         string     `json:"id"`
-	Disk       string     `json:"disk"`
-	Method     string     `json:"method"`
-	Passes     int        `json:"passes"`
-	ChunkSize  int64      `json:"chunk_size"`
-	Status     string     `json:"status"`
-	StartTime  time.Time  `json:"start_time"`
-	EndTime    *time.Time `json:"end_time,omitempty"`
-	BytesWiped uint64     `json:"bytes_wiped"`
-	SpeedMBps  float64    `json:"speed_mbps"`
-	Error      string     `json:"error,omitempty"`
-	Warning    string     `json:"warning,omitempty"`
+	ID                string       `json:"id"`
+	Disk              string       `json:"disk"`
+	Path              string       `json:"path,omitempty"` // Каталог затирания внутри тома disk
+	Method            string       `json:"method"`
+	MethodDescription string       `json:"method_description,omitempty"`
+	PassPatterns      []string     `json:"pass_patterns,omitempty"`
+	Passes            int          `json:"passes"`
+	ChunkSize         int64        `json:"chunk_size"`
+	Status            string       `json:"status"`
+	StartTime         time.Time    `json:"start_time"`
+	EndTime           *time.Time   `json:"end_time,omitempty"`
+	BytesWiped        uint64       `json:"bytes_wiped"`
+	SpeedMBps         float64      `json:"speed_mbps"`
+	Error             string       `json:"error,omitempty"`
+	Warning           string       `json:"warning,omitempty"`
+	PassRecords       []PassReport `json:"pass_records,omitempty"`
+	Runs              []RunReport  `json:"runs,omitempty"`
+
+	// Интервалы работы без пауз и времени вне окон обслуживания
+	ActiveIntervals []wipe.ActiveInterval `json:"active_intervals,omitempty"`
+
+	Verification *wipe.VerifyStats `json:"verification,omitempty"`
+	Trim         *wipe.TrimStats   `json:"trim,omitempty"`
+}
+
+// RunReport представляет один запуск, выполнявший часть операции
+type RunReport struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	FromPass     int       `json:"from_pass"`
+	BytesWritten uint64    `json:"bytes_written"`
+	Status       string    `json:"status"`
+}
+
+// PassReport представляет отчёт об одном проходе затирания
+type PassReport struct {
+	Number       int       `json:"number"`
+	Phase        string    `json:"phase"`
+	Pattern      string    `json:"pattern"`
+	BytesWritten uint64    `json:"bytes_written"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Files        int       `json:"files,omitempty"`
 }
 
 // SummaryReport представляет сводную информацию
@@ -104,6 +137,7 @@ func GenerateReport(operations []*wipe.WipeOperation, cfg *config.Config, engine
 		opReport := OperationReport{
 			ID:         op.ID,
 			Disk:       op.Disk,
+			Path:       op.Path,
 			Method:     op.Method,
 			Passes:     op.Passes,
 			ChunkSize:  op.ChunkSize,
@@ -111,12 +145,52 @@ func GenerateReport(operations []*wipe.WipeOperation, cfg *config.Config, engine
 			StartTime:  op.StartTime,
 			BytesWiped: op.BytesWiped,
 			SpeedMBps:  op.SpeedMBps,
+
+			Verification: op.Verification,
+			Trim:         op.Trim,
+
+			ActiveIntervals: op.ActiveIntervals,
 		}
 
 		if op.EndTime != nil {
 			opReport.EndTime = op.EndTime
 		}
 
+		for _, rec := range op.PassRecords {
+			opReport.PassRecords = append(opReport.PassRecords, PassReport{
+				Number:       rec.Number,
+				Phase:        rec.Phase,
+				Pattern:      rec.Pattern,
+				BytesWritten: rec.BytesWritten,
+				Start:        rec.Start,
+				End:          rec.End,
+				Status:       rec.Status,
+				Error:        rec.Error,
+				Files:        rec.Files,
+			})
+		}
+
+		for _, seg := range op.Segments {
+			opReport.Runs = append(opReport.Runs, RunReport{
+				Start:        seg.Start,
+				End:          seg.End,
+				FromPass:     seg.FromPass,
+				BytesWritten: seg.BytesWritten,
+				Status:       seg.Status,
+			})
+		}
+
+		// Идентификатор запуска из журнала сохраняется между продолжениями
+		if op.RunID != "" {
+			report.RunID = op.RunID
+		}
+
+		// Описание метода берется из реестра схем затирания
+		if scheme, ok := wipe.LookupScheme(op.Method); ok {
+			opReport.MethodDescription = scheme.Description
+			opReport.PassPatterns = scheme.PassDescriptions()
+		}
+
 		if op.Error != "" {
 			opReport.Error = op.Error
 			failed++
@@ -283,6 +357,7 @@ func configToMap(cfg *config.Config) map[string]interface{} {
 			"max_speed_mbps": cfg.Wipe.MaxSpeedMBps,
 			"file_delay_ms":  cfg.Wipe.FileDelayMs,
 			"max_duration":   cfg.Wipe.MaxDuration,
+			"schedule":       cfg.Wipe.Schedule,
 		},
 		"clean": map[string]interface{}{
 			"enabled":          cfg.Clean.Enabled,
@@ -8,6 +8,7 @@ import (
 	"time"
 	"wipedisk_enterprise/internal/maintenance"
 	"wipedisk_enterprise/internal/system"
+	"wipedisk_enterprise/internal/wipe"
 )
 
 // VerificationReportJSON расширенная структура для JSON отчёта
@@ -74,6 +75,11 @@ func saveVerificationReportCSV(report *VerificationReportJSON, outputPath string
 		outputPath = filepath.Join(os.Getenv("TEMP"), fmt.Sprintf("wipedisk_verification_%s.csv", timestamp))
 	}
 
+	var readBack wipe.VerifyStats
+	if report.ReadBack != nil {
+		readBack = *report.ReadBack
+	}
+
 	// Создаем CSV контент
 	csvContent := fmt.Sprintf(`# WipeDisk Verification Report
 # Generated: %s
@@ -92,6 +98,9 @@ Success Rate,%.2f%%
 Test Date,%s
 Anomalies Count,%d
 Compliance Standards,"%s"
+Read-back Blocks Checked,%d
+Read-back Mismatched Blocks,%d
+Read-back Read Errors,%d
 
 # Anomalies:
 `,
@@ -109,6 +118,9 @@ Compliance Standards,"%s"
 		report.TestDate.Format(time.RFC3339),
 		len(report.Anomalies),
 		fmt.Sprintf("%v", report.Compliance),
+		readBack.BlocksChecked,
+		readBack.MismatchBlocks,
+		readBack.ReadErrors,
 	)
 
 	// Добавляем аномалии
@@ -3,6 +3,7 @@ package security
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -33,7 +34,7 @@ func SecurityChecks(cfg *config.Config) error {
 // Проверка прав администратора
 func IsAdmin() bool {
 	if runtime.GOOS != "windows" {
-		return false
+		return system.IsAdmin()
 	}
 
 	return os.Getenv("USERNAME") != "" && os.Getenv("USERDOMAIN") != ""
@@ -82,3 +83,45 @@ func ShouldSkipDisk(cfg *config.Config, disk system.DiskInfo) bool {
 
 	return false
 }
+
+// CheckProtectedPath запрещает операции над путем, совпадающим с одним из
+// Security.ProtectedPaths или лежащим внутри него
+func CheckProtectedPath(cfg *config.Config, path string) error {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("не удалось определить путь %s: %w", path, err)
+	}
+	// Символьные ссылки в пути раскрываются, чтобы не обойти защиту через ссылку
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(dir, filepath.Base(abs))
+	}
+
+	for _, protected := range cfg.Security.ProtectedPaths {
+		if protected == "" {
+			continue
+		}
+		if isWithin(abs, filepath.Clean(protected)) {
+			return fmt.Errorf("путь %s находится в защищенном каталоге %s", path, protected)
+		}
+	}
+	return nil
+}
+
+// isWithin проверяет, совпадает ли path с root или лежит внутри него
+func isWithin(path, root string) bool {
+	if runtime.GOOS == "windows" {
+		path, root = strings.ToLower(path), strings.ToLower(root)
+	}
+	if path == root {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,248 @@
+//go:build linux
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultSysfsRoot корень sysfs в работающей системе
+const DefaultSysfsRoot = "/sys"
+
+// BlockDeviceResolver сопоставляет точку монтирования физическим блочным
+// устройствам через sysfs. Корень sysfs задается явно, чтобы резолвер можно
+// было запускать на заранее подготовленном дереве каталогов.
+type BlockDeviceResolver struct {
+	sysfsRoot string
+}
+
+// BlockDevice физическое (листовое) блочное устройство
+type BlockDevice struct {
+	Name       string
+	Rotational bool
+	Model      string
+	Serial     string
+	Transport  string
+}
+
+// ResolvedVolume результат разрешения тома до физических устройств
+type ResolvedVolume struct {
+	Name    string        // Имя устройства тома (sda1, dm-0, md127, nvme0n1p2)
+	Devices []BlockDevice // Физические устройства, на которых лежит том
+}
+
+// NewBlockDeviceResolver создает резолвер; пустой sysfsRoot означает /sys
+func NewBlockDeviceResolver(sysfsRoot string) *BlockDeviceResolver {
+	if sysfsRoot == "" {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	return &BlockDeviceResolver{sysfsRoot: sysfsRoot}
+}
+
+// Resolve находит физические устройства для блочного устройства major:minor.
+// Разделы поднимаются до родительского диска, device-mapper (LVM, dm-crypt)
+// и md RAID раскрываются рекурсивно через каталог slaves/.
+func (r *BlockDeviceResolver) Resolve(major, minor uint32) (*ResolvedVolume, error) {
+	devPath := filepath.Join(r.sysfsRoot, "dev", "block", fmt.Sprintf("%d:%d", major, minor))
+	dir, err := resolveSysfsDir(devPath)
+	if err != nil {
+		return nil, fmt.Errorf("блочное устройство %d:%d не найдено в sysfs: %w", major, minor, err)
+	}
+
+	vol := &ResolvedVolume{Name: filepath.Base(dir)}
+	seen := make(map[string]bool)
+	if err := r.collect(dir, seen, &vol.Devices, 0); err != nil {
+		return nil, err
+	}
+	if len(vol.Devices) == 0 {
+		return nil, fmt.Errorf("не удалось определить физические устройства для %s", vol.Name)
+	}
+
+	sort.Slice(vol.Devices, func(i, j int) bool {
+		return vol.Devices[i].Name < vol.Devices[j].Name
+	})
+
+	return vol, nil
+}
+
+// maxStackDepth ограничивает глубину раскрытия slaves/ (защита от циклов)
+const maxStackDepth = 16
+
+// collect рекурсивно собирает листовые устройства начиная с каталога dir
+func (r *BlockDeviceResolver) collect(dir string, seen map[string]bool, out *[]BlockDevice, depth int) error {
+	if depth > maxStackDepth {
+		return fmt.Errorf("слишком глубокая вложенность устройств в %s", dir)
+	}
+
+	// Раздел: поднимаемся к диску, которому он принадлежит
+	if fileExists(filepath.Join(dir, "partition")) {
+		dir = filepath.Dir(dir)
+	}
+
+	name := filepath.Base(dir)
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	slaves, _ := os.ReadDir(filepath.Join(dir, "slaves"))
+	if len(slaves) == 0 {
+		*out = append(*out, r.readDevice(dir))
+		return nil
+	}
+
+	for _, slave := range slaves {
+		slaveDir, err := resolveSysfsDir(filepath.Join(dir, "slaves", slave.Name()))
+		if err != nil {
+			slaveDir, err = resolveSysfsDir(filepath.Join(r.sysfsRoot, "class", "block", slave.Name()))
+			if err != nil {
+				return fmt.Errorf("устройство %s (slave of %s) не найдено: %w", slave.Name(), name, err)
+			}
+		}
+		if err := r.collect(slaveDir, seen, out, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readDevice читает атрибуты физического диска из его каталога sysfs
+func (r *BlockDeviceResolver) readDevice(dir string) BlockDevice {
+	dev := BlockDevice{
+		Name:       filepath.Base(dir),
+		Rotational: readSysfsString(filepath.Join(dir, "queue", "rotational")) == "1",
+		Model:      readSysfsString(filepath.Join(dir, "device", "model")),
+		Serial:     readSysfsString(filepath.Join(dir, "device", "serial")),
+	}
+
+	if dev.Serial == "" {
+		dev.Serial = readSysfsString(filepath.Join(dir, "device", "wwid"))
+	}
+	if dev.Serial == "" {
+		dev.Serial = readSysfsString(filepath.Join(dir, "serial"))
+	}
+
+	dev.Transport = detectTransport(dev.Name, dir)
+	return dev
+}
+
+// detectTransport определяет шину подключения по имени и пути устройства в sysfs
+func detectTransport(name, dir string) string {
+	switch {
+	case strings.HasPrefix(name, "nvme"):
+		return "NVMe"
+	case strings.HasPrefix(name, "mmcblk"):
+		return "MMC"
+	case strings.HasPrefix(name, "loop"):
+		return "Loop"
+	}
+
+	path := dir
+	if target, err := filepath.EvalSymlinks(filepath.Join(dir, "device")); err == nil {
+		path = target
+	}
+
+	switch {
+	case strings.Contains(path, "/usb"):
+		return "USB"
+	case strings.Contains(path, "/virtio") || strings.HasPrefix(name, "vd"):
+		return "VirtIO"
+	case strings.Contains(path, "/ata"):
+		return "SATA"
+	case strings.Contains(path, "/host"):
+		return "SCSI"
+	}
+
+	return "Unknown"
+}
+
+// Type возвращает "HDD", если хотя бы одно устройство вращающееся, иначе "SSD"
+func (v *ResolvedVolume) Type() string {
+	for _, d := range v.Devices {
+		if d.Rotational {
+			return "HDD"
+		}
+	}
+	return "SSD"
+}
+
+// Names возвращает имена физических устройств
+func (v *ResolvedVolume) Names() []string {
+	names := make([]string, 0, len(v.Devices))
+	for _, d := range v.Devices {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+// applyTo заполняет поля DiskInfo по разрешенным устройствам
+func (v *ResolvedVolume) applyTo(info *DiskInfo) {
+	info.Type = v.Type()
+	info.BackingDevices = v.Names()
+
+	var models, serials, transports []string
+	for _, d := range v.Devices {
+		models = appendUnique(models, d.Model)
+		serials = appendUnique(serials, d.Serial)
+		transports = appendUnique(transports, d.Transport)
+	}
+
+	if len(models) > 0 {
+		info.Model = strings.Join(models, ", ")
+	}
+	if len(serials) > 0 {
+		info.Serial = strings.Join(serials, ", ")
+	}
+	if len(transports) > 0 {
+		info.Interface = strings.Join(transports, ", ")
+	}
+}
+
+// resolveSysfsDir раскрывает символическую ссылку sysfs в реальный каталог
+func resolveSysfsDir(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	st, err := os.Stat(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !st.IsDir() {
+		return "", fmt.Errorf("%s не является каталогом", resolved)
+	}
+	return resolved, nil
+}
+
+// readSysfsString читает однострочный атрибут sysfs; ошибки дают пустую строку
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// fileExists проверяет существование файла
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// appendUnique добавляет непустое значение, если его еще нет в списке
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,113 @@
+//go:build linux
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtureDevBlock - ссылки /sys/dev/block/<major>:<minor> дерева
+// testdata/sysfs. Имена с двоеточием нельзя хранить в репозитории (их не
+// примет checkout в Windows), поэтому ссылки создаются в тесте.
+var fixtureDevBlock = map[string]string{
+	"8:0":   "devices/pci0000_00/ata1/host0/target0_0_0/0_0_0_0/block/sda",
+	"8:1":   "devices/pci0000_00/ata1/host0/target0_0_0/0_0_0_0/block/sda/sda1",
+	"259:1": "devices/pci0000_00/nvme/nvme0/nvme0n1/nvme0n1p1",
+	"253:0": "devices/virtual/block/dm-0",
+	"253:1": "devices/virtual/block/dm-1",
+	"9:127": "devices/virtual/block/md127",
+}
+
+// newFixtureResolver создает резолвер на копии корня testdata/sysfs с каталогом dev/block
+func newFixtureResolver(t *testing.T) *BlockDeviceResolver {
+	t.Helper()
+	devices, err := filepath.Abs(filepath.Join("testdata", "sysfs", "devices"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	if err := os.Symlink(devices, filepath.Join(root, "devices")); err != nil {
+		t.Fatal(err)
+	}
+	devBlock := filepath.Join(root, "dev", "block")
+	if err := os.MkdirAll(devBlock, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for dev, target := range fixtureDevBlock {
+		if err := os.Symlink(filepath.Join("..", "..", target), filepath.Join(devBlock, dev)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewBlockDeviceResolver(root)
+}
+
+func TestBlockDeviceResolverResolve(t *testing.T) {
+	r := newFixtureResolver(t)
+
+	tests := []struct {
+		name         string
+		major, minor uint32
+		wantName     string
+		wantDevices  []string
+		wantType     string
+	}{
+		{"диск", 8, 0, "sda", []string{"sda"}, "HDD"},
+		{"раздел", 8, 1, "sda1", []string{"sda"}, "HDD"},
+		{"раздел nvme", 259, 1, "nvme0n1p1", []string{"nvme0n1"}, "SSD"},
+		{"LVM на двух дисках", 253, 0, "dm-0", []string{"nvme0n1", "sda"}, "HDD"},
+		{"md RAID1", 9, 127, "md127", []string{"sdb", "sdc"}, "HDD"},
+		{"dm-crypt поверх md", 253, 1, "dm-1", []string{"sdb", "sdc"}, "HDD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vol, err := r.Resolve(tt.major, tt.minor)
+			if err != nil {
+				t.Fatalf("Resolve(%d:%d): %v", tt.major, tt.minor, err)
+			}
+			if vol.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", vol.Name, tt.wantName)
+			}
+			if got := vol.Names(); !reflect.DeepEqual(got, tt.wantDevices) {
+				t.Errorf("Names() = %v, want %v", got, tt.wantDevices)
+			}
+			if got := vol.Type(); got != tt.wantType {
+				t.Errorf("Type() = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestBlockDeviceResolverAttributes(t *testing.T) {
+	r := newFixtureResolver(t)
+
+	vol, err := r.Resolve(253, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BlockDevice{
+		{Name: "nvme0n1", Rotational: false, Model: "Samsung SSD 980 500GB", Serial: "S64DNX0R123456", Transport: "NVMe"},
+		{Name: "sda", Rotational: true, Model: "ST2000DM008-2FR1", Serial: "t10.ATA     ST2000DM008-2FR102                      ZFL0A1B2", Transport: "SATA"},
+	}
+	if !reflect.DeepEqual(vol.Devices, want) {
+		t.Errorf("Devices = %+v\nwant %+v", vol.Devices, want)
+	}
+
+	var info DiskInfo
+	vol.applyTo(&info)
+	if info.Interface != "NVMe, SATA" || info.Type != "HDD" || !reflect.DeepEqual(info.BackingDevices, []string{"nvme0n1", "sda"}) {
+		t.Errorf("applyTo: Interface %q, Type %q, BackingDevices %v", info.Interface, info.Type, info.BackingDevices)
+	}
+}
+
+func TestBlockDeviceResolverUnknownDevice(t *testing.T) {
+	r := newFixtureResolver(t)
+
+	if _, err := r.Resolve(8, 99); err == nil {
+		t.Error("Resolve(8:99): ожидалась ошибка для отсутствующего устройства")
+	}
+}
@@ -1,72 +1,50 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
-	"unsafe"
-
-	"golang.org/x/sys/windows"
 )
 
-// GetDiskInfo gets information about disks via Windows API
-func GetDiskInfo(verbose bool) ([]DiskInfo, error) {
-	var disks []DiskInfo
-
-	// Direct A-Z enumeration for maximum compatibility
-	for c := 'A'; c <= 'Z'; c++ {
-		drive := string(c) + ":"
-		driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
-
-		// Skip non-existent drives
-		if driveType == windows.DRIVE_NO_ROOT_DIR || driveType == windows.DRIVE_UNKNOWN {
-			continue
-		}
-
-		// Include all valid drive types
-		if driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
-			driveType == windows.DRIVE_REMOTE || driveType == windows.DRIVE_RAMDISK ||
-			driveType == windows.DRIVE_CDROM {
-
-			info, err := getDriveInfo(drive, driveType)
-			if err != nil {
-				if verbose {
-					fmt.Printf("[WARN] Skipping drive %s: %v\n", drive, err)
-				}
-				continue // Skip inaccessible drives
-			}
-
-			disks = append(disks, info)
-		}
-	}
+// diskPlatform абстрагирует платформенно-зависимую работу с томами.
+// Реализации находятся в disk_windows.go и disk_linux.go.
+type diskPlatform interface {
+	// listDisks перечисляет все доступные тома с подробной информацией
+	listDisks(verbose bool) ([]DiskInfo, error)
+	// diskSpace возвращает свободное и общее место тома, содержащего path
+	diskSpace(path string) (free uint64, total uint64, err error)
+	// logicalDrives возвращает идентификаторы томов (буквы или точки монтирования)
+	logicalDrives() []string
+	// availableDrives возвращает краткий список томов для меню выбора
+	availableDrives() []DriveInfo
+	// diskInfoForPath возвращает информацию о томе, содержащем path
+	diskInfoForPath(path string) (DiskInfo, error)
+	// isLocal проверяет, является ли том локальным (не сетевым)
+	isLocal(path string) bool
+	// isSystem проверяет, является ли том системным
+	isSystem(path string) bool
+	// clusterSize возвращает единицу выделения места тома, содержащего path
+	clusterSize(path string) (uint64, error)
+	// targetSpace возвращает место, доступное текущему пользователю для
+	// записи в каталог dir, с учетом квот
+	targetSpace(dir string) (uint64, error)
+}
 
-	return disks, nil
+// GetDiskInfo gets information about disks via platform API
+func GetDiskInfo(verbose bool) ([]DiskInfo, error) {
+	return platform.listDisks(verbose)
 }
 
-// GetDiskSpace gets free space information via Windows API
+// GetDiskSpace gets free space information via platform API
 func GetDiskSpace(drive string, verbose bool) (uint64, uint64) {
-	// Convert path to UTF16 for Windows API
-	drivePath, err := syscall.UTF16PtrFromString(drive)
+	freeBytes, totalBytes, err := platform.diskSpace(drive)
 	if err != nil {
-		return 0, 0
-	}
-
-	var freeBytesAvailable, totalBytes, freeBytes uint64
-
-	// Call GetDiskFreeSpaceExW with proper uintptr casting
-	ret, _, err := procGetDiskFreeSpaceExW.Call(
-		uintptr(unsafe.Pointer(drivePath)),
-		uintptr(unsafe.Pointer(&freeBytesAvailable)),
-		uintptr(unsafe.Pointer(&totalBytes)),
-		uintptr(unsafe.Pointer(&freeBytes)),
-	)
-
-	if ret == 0 {
-		// API call error
 		if verbose {
-			fmt.Printf("[ERROR] GetDiskFreeSpaceExW failed for %s: %v\n", drive, err)
+			fmt.Printf("[ERROR] Failed to get disk space for %s: %v\n", drive, err)
 		}
 		return 0, 0
 	}
@@ -74,103 +52,41 @@ func GetDiskSpace(drive string, verbose bool) (uint64, uint64) {
 	return freeBytes, totalBytes
 }
 
+// ClusterSize возвращает размер кластера (единицы выделения места) тома,
+// на котором находится path
+func ClusterSize(path string) (uint64, error) {
+	return platform.clusterSize(path)
+}
+
 // isLocalDrive checks if drive is local (not network)
 func isLocalDrive(drive string) bool {
-	driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
-	return driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
-		driveType == windows.DRIVE_RAMDISK || driveType == windows.DRIVE_CDROM
+	return platform.isLocal(drive)
 }
 
 // getLogicalDrives gets list of logical drives
 func getLogicalDrives() []string {
-	var drives []string
-
-	// Use Windows API to get drives
-	kernel32, err := syscall.LoadLibrary("kernel32.dll")
-	if err != nil {
-		// Fallback to simple method
-		for c := 'A'; c <= 'Z'; c++ {
-			drive := string(c) + ":"
-			if _, err := os.Stat(drive + "\\"); err == nil {
-				drives = append(drives, drive)
-			}
-		}
-		return drives
-	}
-	defer syscall.FreeLibrary(kernel32)
-
-	getLogicalDrivesProc, err := syscall.GetProcAddress(kernel32, "GetLogicalDrives")
-	if err != nil {
-		return drives
-	}
-
-	ret, _, _ := syscall.Syscall(uintptr(getLogicalDrivesProc), 0, 0, 0, 0)
-	drivesMask := uint32(ret)
-
-	for c := 0; c < 26; c++ {
-		if drivesMask&(1<<c) != 0 {
-			drive := string('A'+c) + ":"
-			drives = append(drives, drive)
-		}
-	}
-
-	return drives
-}
-
-// getDriveInfo gets detailed drive information
-func getDriveInfo(drive string, driveType uint32) (DiskInfo, error) {
-	info := DiskInfo{
-		Letter:     drive,
-		Type:       getDriveTypeName(driveType),
-		IsSystem:   isSystemDrive(drive),
-		IsWritable: true,
-		Model:      "Unknown Model",
-		Serial:     "Unknown Serial",
-		Interface:  "Unknown Interface",
-	}
-
-	// Get free space information
-	freeSize, totalSize := GetDiskSpace(drive, false)
-	info.FreeSize = freeSize
-	info.TotalSize = totalSize
-
-	// Determine disk type based on Windows drive type
-	switch driveType {
-	case windows.DRIVE_FIXED:
-		if info.IsSystem {
-			info.Type = "SSD" // Assume SSD for system drive
-		} else {
-			info.Type = "HDD" // Assume HDD for other fixed drives
-		}
-	case windows.DRIVE_REMOVABLE:
-		info.Type = "USB/Flash"
-	case windows.DRIVE_CDROM:
-		info.Type = "CD/DVD"
-	case windows.DRIVE_RAMDISK:
-		info.Type = "RAM Disk"
-	case windows.DRIVE_REMOTE:
-		info.Type = "Network"
-	default:
-		info.Type = "Unknown"
-	}
-
-	// Check write access
-	info.IsWritable = checkWriteAccess(drive)
-
-	return info, nil
+	return platform.logicalDrives()
 }
 
 // isSystemDrive checks if drive is system drive
 func isSystemDrive(drive string) bool {
-	// Get system drive dynamically
-	systemDrive := getSystemDrive()
-	return strings.EqualFold(drive, systemDrive)
+	return platform.isSystem(drive)
+}
+
+// VolumeRoot возвращает корень тома, пригодный для построения путей к файлам.
+// Для буквы диска ("D:") добавляется разделитель, точки монтирования
+// возвращаются без изменений.
+func VolumeRoot(volume string) string {
+	volume = strings.TrimRight(strings.TrimSpace(volume), ".")
+	if len(volume) == 2 && volume[1] == ':' {
+		return volume + `\`
+	}
+	return volume
 }
 
 // checkWriteAccess checks write access to drive
 func checkWriteAccess(drive string) bool {
-	testPath := drive + "\\"
-	testFile := filepath.Join(testPath, ".wipedisk_write_test")
+	testFile := filepath.Join(VolumeRoot(drive), ".wipedisk_write_test")
 
 	file, err := os.Create(testFile)
 	if err != nil {
@@ -204,7 +120,7 @@ func ValidateDrive(drive string) error {
 	for _, availableDrive := range availableDrives {
 		if normalizePath(availableDrive.Letter) == normalizedDrive {
 			// Check if drive is accessible
-			if _, err := os.Stat(normalizedDrive + "\\"); err != nil {
+			if _, err := os.Stat(VolumeRoot(normalizedDrive)); err != nil {
 				return fmt.Errorf("диск %s недоступен: %w", normalizedDrive, err)
 			}
 			return nil
@@ -222,42 +138,7 @@ func ValidateDrive(drive string) error {
 
 // GetAvailableDrives returns list of available local drives with types
 func GetAvailableDrives() []DriveInfo {
-	var drives []DriveInfo
-
-	// Direct A-Z enumeration for maximum compatibility
-	for c := 'A'; c <= 'Z'; c++ {
-		drive := string(c) + ":"
-		driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
-
-		// Skip non-existent drives
-		if driveType == windows.DRIVE_NO_ROOT_DIR || driveType == windows.DRIVE_UNKNOWN {
-			continue
-		}
-
-		// Include all valid drive types
-		if driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
-			driveType == windows.DRIVE_REMOTE || driveType == windows.DRIVE_RAMDISK ||
-			driveType == windows.DRIVE_CDROM {
-
-			// Get free space
-			freeSpace, totalSpace := GetDiskSpace(drive, false)
-
-			// Mark as [Not Ready] if total space is 0 (unformatted or inaccessible)
-			driveTypeStr := getDriveTypeName(driveType)
-			if totalSpace == 0 {
-				driveTypeStr += " [Not Ready]"
-			}
-
-			drives = append(drives, DriveInfo{
-				Letter:   drive,
-				Type:     driveTypeStr,
-				IsSystem: isSystemDrive(drive),
-				FreeSize: freeSpace,
-			})
-		}
-	}
-
-	return drives
+	return platform.availableDrives()
 }
 
 // DriveInfo represents information about a drive
@@ -268,24 +149,6 @@ type DriveInfo struct {
 	FreeSize uint64
 }
 
-// getDriveTypeName converts Windows drive type to readable string
-func getDriveTypeName(driveType uint32) string {
-	switch driveType {
-	case windows.DRIVE_FIXED:
-		return "Fixed Drive"
-	case windows.DRIVE_REMOVABLE:
-		return "Removable Drive"
-	case windows.DRIVE_CDROM:
-		return "CD-ROM"
-	case windows.DRIVE_RAMDISK:
-		return "RAM Disk"
-	case windows.DRIVE_REMOTE:
-		return "Network Drive"
-	default:
-		return "Unknown"
-	}
-}
-
 // ValidatePath validates and normalizes path
 func ValidatePath(path string) (string, error) {
 	if path == "" {
@@ -370,114 +233,33 @@ func GetSafeTempPaths() ([]string, error) {
 }
 
 // IsDiskFullError проверяет, является ли ошибка ошибкой "Недостаточно места на диске"
+// или исчерпания квоты
 func IsDiskFullError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if errno, ok := err.(syscall.Errno); ok && errno == 112 {
-		return true
-	}
-
-	// Also check for Windows syscall errors
-	if pathErr, ok := err.(*os.PathError); ok {
-		if errno, ok := pathErr.Err.(syscall.Errno); ok && errno == 112 {
+	// ENOSPC и EDQUOT на Unix-системах, ERROR_DISK_FULL и ошибки квот на Windows
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		if errno == syscall.ENOSPC || errno == syscall.EDQUOT {
 			return true
 		}
-	}
-
-	return false
-}
-
-// IsAdmin checks if current process has administrator privileges
-func IsAdmin() bool {
-	var sid *windows.SID
-
-	// Create well-known SID for administrators group
-	err := windows.AllocateAndInitializeSid(
-		&windows.SECURITY_NT_AUTHORITY,
-		2,
-		windows.SECURITY_BUILTIN_DOMAIN_RID,
-		windows.DOMAIN_ALIAS_RID_ADMINS,
-		0, 0, 0, 0, 0, 0,
-		&sid,
-	)
-	if err != nil {
-		// Fallback: try opening physical drive
-		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
-		if fallbackErr == nil {
-			return true
-		}
-		return false
-	}
-	defer windows.FreeSid(sid)
-
-	// Get current process token
-	token, err := windows.OpenCurrentProcessToken()
-	if err != nil {
-		// Fallback: try opening physical drive
-		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
-		if fallbackErr == nil {
-			return true
-		}
-		return false
-	}
-	defer token.Close()
-
-	// Check if token is member of administrators group
-	member, err := token.IsMember(sid)
-	if err != nil {
-		// Fallback: try opening physical drive
-		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
-		if fallbackErr == nil {
-			return true
+		// Коды Windows совпадают с другими errno Unix (39 - ENOTEMPTY)
+		if runtime.GOOS == "windows" {
+			switch errno {
+			case ERROR_DISK_FULL, ERROR_HANDLE_DISK_FULL, ERROR_DISK_QUOTA_EXCEEDED, ERROR_NOT_ENOUGH_QUOTA:
+				return true
+			}
 		}
-		return false
 	}
 
-	return member
+	return false
 }
 
-// Windows API functions for GetDiskFreeSpaceEx
-var (
-	kernel32                = syscall.NewLazyDLL("kernel32.dll")
-	procGetDiskFreeSpaceExW = kernel32.NewProc("GetDiskFreeSpaceExW")
-)
-
 // GetDiskInfoForPath получает информацию о конкретном диске по пути
 func GetDiskInfoForPath(drivePath string) (DiskInfo, error) {
-	drivePath = normalizePath(drivePath)
-
-	var freeBytesAvailable, totalBytes, freeBytes uint64
-
-	err := windows.GetDiskFreeSpaceEx(
-		windows.StringToUTF16Ptr(drivePath),
-		&freeBytesAvailable,
-		&totalBytes,
-		&freeBytes,
-	)
-	if err != nil {
-		return DiskInfo{}, fmt.Errorf("ошибка получения информации о диске: %w", err)
-	}
-
-	// Определение типа диска
-	diskType := getDiskType(drivePath)
-
-	// Проверка системного диска
-	isSystem := isSystemDisk(drivePath)
-
-	return DiskInfo{
-		Letter:     drivePath,
-		Type:       diskType,
-		TotalSize:  totalBytes,
-		FreeSize:   freeBytes,
-		UsedSize:   totalBytes - freeBytes,
-		IsSystem:   isSystem,
-		IsWritable: checkWriteAccess(drivePath),
-		Model:      "",
-		Serial:     "",
-		Interface:  "",
-	}, nil
+	return platform.diskInfoForPath(normalizePath(drivePath))
 }
 
 // NormalizePath нормализует путь к диску (публичная функция)
@@ -491,7 +273,7 @@ func normalizePath(path string) string {
 	path = strings.TrimRight(path, ". ")
 
 	// Handle single letter (e.g., "F")
-	if len(path) == 1 {
+	if len(path) == 1 && path != "/" {
 		return strings.ToUpper(path) + ":"
 	}
 
@@ -516,27 +298,3 @@ func normalizePath(path string) string {
 	// Return as-is if no pattern matches
 	return path
 }
-
-// getDiskType определяет тип диска (HDD/SSD)
-func getDiskType(drivePath string) string {
-	// В реальной реализации здесь будет определение типа диска
-	// через WMI или другие Windows API
-	// Пока возвращаем Unknown
-	return "Unknown"
-}
-
-// isSystemDisk проверяет, является ли диск системным
-func isSystemDisk(drivePath string) bool {
-	// Получаем путь к системной директории
-	sysDir, err := windows.GetSystemDirectory()
-	if err != nil {
-		return false
-	}
-
-	if len(sysDir) >= 2 {
-		systemDrive := sysDir[:2]
-		return normalizePath(drivePath) == systemDrive
-	}
-
-	return false
-}
@@ -0,0 +1,312 @@
+//go:build linux
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// linuxDisks реализует diskPlatform через /proc/self/mountinfo и statfs
+type linuxDisks struct {
+	resolver *BlockDeviceResolver
+}
+
+var platform diskPlatform = linuxDisks{resolver: NewBlockDeviceResolver(DefaultSysfsRoot)}
+
+func (l linuxDisks) listDisks(verbose bool) ([]DiskInfo, error) {
+	mounts, err := dataMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []DiskInfo
+	for _, m := range mounts {
+		info, err := l.mountDiskInfo(m)
+		if err != nil {
+			if verbose {
+				fmt.Printf("[WARN] Skipping mount %s: %v\n", m.MountPoint, err)
+			}
+			continue
+		}
+		disks = append(disks, info)
+	}
+
+	return disks, nil
+}
+
+func (linuxDisks) diskSpace(path string) (uint64, uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(VolumeRoot(path), &st); err != nil {
+		return 0, 0, fmt.Errorf("statfs failed: %w", err)
+	}
+
+	bs := blockSize(&st)
+	return st.Bavail * bs, st.Blocks * bs, nil
+}
+
+func (linuxDisks) clusterSize(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, fmt.Errorf("statfs failed: %w", err)
+	}
+	return blockSize(&st), nil
+}
+
+func (linuxDisks) targetSpace(dir string) (uint64, error) {
+	// Bavail не включает блоки, зарезервированные для root; для каталога
+	// с project quota ext4 и xfs возвращают остаток квоты каталога
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return 0, fmt.Errorf("statfs failed: %w", err)
+	}
+	free := st.Bavail * blockSize(&st)
+
+	// Файлы затирания учитываются в квотах текущего пользователя и его группы
+	m, err := mountForPath(dir)
+	if err != nil {
+		return free, nil
+	}
+	if left, ok := quotaLeft(m.Source, usrQuota, os.Geteuid()); ok {
+		free = min(free, left)
+	}
+	if left, ok := quotaLeft(m.Source, grpQuota, os.Getegid()); ok {
+		free = min(free, left)
+	}
+	return free, nil
+}
+
+func (linuxDisks) isLocal(path string) bool {
+	m, err := mountForPath(path)
+	if err != nil {
+		return false
+	}
+	return !isNetworkFS(m.FSType)
+}
+
+func (linuxDisks) logicalDrives() []string {
+	mounts, err := dataMounts()
+	if err != nil {
+		return nil
+	}
+
+	drives := make([]string, 0, len(mounts))
+	for _, m := range mounts {
+		drives = append(drives, m.MountPoint)
+	}
+	return drives
+}
+
+func (linuxDisks) availableDrives() []DriveInfo {
+	mounts, err := dataMounts()
+	if err != nil {
+		return nil
+	}
+
+	var drives []DriveInfo
+	for _, m := range mounts {
+		freeSpace, totalSpace := GetDiskSpace(m.MountPoint, false)
+
+		// Mark as [Not Ready] if total space is 0 (inaccessible)
+		driveTypeStr := mountTypeName(m)
+		if totalSpace == 0 {
+			driveTypeStr += " [Not Ready]"
+		}
+
+		drives = append(drives, DriveInfo{
+			Letter:   m.MountPoint,
+			Type:     driveTypeStr,
+			IsSystem: m.MountPoint == "/",
+			FreeSize: freeSpace,
+		})
+	}
+
+	return drives
+}
+
+func (l linuxDisks) diskInfoForPath(path string) (DiskInfo, error) {
+	m, err := mountForPath(path)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("ошибка получения информации о диске: %w", err)
+	}
+
+	info, err := l.mountDiskInfo(m)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("ошибка получения информации о диске: %w", err)
+	}
+	return info, nil
+}
+
+func (linuxDisks) isSystem(path string) bool {
+	return filepath.Clean(path) == "/"
+}
+
+// IsAdmin checks if current process runs as root
+func IsAdmin() bool {
+	return os.Geteuid() == 0
+}
+
+// dataMounts возвращает точки монтирования, хранящие пользовательские данные.
+// Псевдо-ФС пропускаются, повторные монтирования одного устройства
+// (bind mounts) схлопываются в одну запись.
+func dataMounts() ([]mountEntry, error) {
+	entries, err := readMountInfo(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	byDevice := make(map[[2]uint32]int)
+	var mounts []mountEntry
+	for _, e := range entries {
+		if isPseudoFS(e.FSType) {
+			continue
+		}
+
+		key := [2]uint32{e.Major, e.Minor}
+		if idx, ok := byDevice[key]; ok {
+			// Предпочитаем монтирование корня ФС, затем более короткий путь
+			prev := mounts[idx]
+			if (e.Root == "/" && prev.Root != "/") ||
+				(e.Root == prev.Root && len(e.MountPoint) < len(prev.MountPoint)) {
+				mounts[idx] = e
+			}
+			continue
+		}
+
+		byDevice[key] = len(mounts)
+		mounts = append(mounts, e)
+	}
+
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].MountPoint < mounts[j].MountPoint
+	})
+
+	return mounts, nil
+}
+
+// mountForPath находит точку монтирования, содержащую path (самый длинный префикс)
+func mountForPath(path string) (mountEntry, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return mountEntry{}, err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	entries, err := readMountInfo(mountInfoPath)
+	if err != nil {
+		return mountEntry{}, err
+	}
+
+	var best mountEntry
+	found := false
+	for _, e := range entries {
+		if !pathWithin(abs, e.MountPoint) {
+			continue
+		}
+		// Более поздние записи перекрывают более ранние с той же точкой монтирования
+		if !found || len(e.MountPoint) >= len(best.MountPoint) {
+			best = e
+			found = true
+		}
+	}
+
+	if !found {
+		return mountEntry{}, fmt.Errorf("точка монтирования для %s не найдена", path)
+	}
+	return best, nil
+}
+
+// pathWithin проверяет, что path находится внутри mountPoint
+func pathWithin(path, mountPoint string) bool {
+	if mountPoint == "/" {
+		return true
+	}
+	return path == mountPoint || strings.HasPrefix(path, mountPoint+"/")
+}
+
+// mountDiskInfo заполняет DiskInfo по записи mountinfo, statfs и sysfs
+func (l linuxDisks) mountDiskInfo(m mountEntry) (DiskInfo, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(m.MountPoint, &st); err != nil {
+		return DiskInfo{}, fmt.Errorf("statfs failed: %w", err)
+	}
+	if st.Blocks == 0 {
+		return DiskInfo{}, fmt.Errorf("пустая файловая система %s", m.FSType)
+	}
+
+	bs := blockSize(&st)
+	total := st.Blocks * bs
+
+	diskType := "Unknown"
+	if isNetworkFS(m.FSType) {
+		diskType = "Network"
+	}
+
+	info := DiskInfo{
+		Letter:     m.MountPoint,
+		Type:       diskType,
+		TotalSize:  total,
+		FreeSize:   st.Bavail * bs,
+		UsedSize:   (st.Blocks - st.Bfree) * bs,
+		IsSystem:   m.MountPoint == "/",
+		IsWritable: !m.hasOption("ro") && unix.Access(m.MountPoint, unix.W_OK) == nil,
+		Model:      "Unknown Model",
+		Serial:     "Unknown Serial",
+		Interface:  "Unknown Interface",
+		FSType:     m.FSType,
+		Device:     m.Source,
+	}
+
+	// Тип носителя определяется по физическим устройствам под томом
+	if !isNetworkFS(m.FSType) {
+		if vol, err := l.resolveMount(m); err == nil {
+			vol.applyTo(&info)
+		}
+	}
+
+	return info, nil
+}
+
+// resolveMount разрешает том до физических устройств. Если major:minor из
+// mountinfo не соответствует блочному устройству (btrfs, subvolume и т.п.),
+// используется номер устройства из исходного узла /dev.
+func (l linuxDisks) resolveMount(m mountEntry) (*ResolvedVolume, error) {
+	vol, err := l.resolver.Resolve(m.Major, m.Minor)
+	if err == nil {
+		return vol, nil
+	}
+
+	if !strings.HasPrefix(m.Source, "/dev/") {
+		return nil, err
+	}
+
+	var st unix.Stat_t
+	if statErr := unix.Stat(m.Source, &st); statErr != nil || st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return nil, err
+	}
+
+	return l.resolver.Resolve(unix.Major(uint64(st.Rdev)), unix.Minor(uint64(st.Rdev)))
+}
+
+// mountTypeName возвращает читаемое описание тома для меню выбора
+func mountTypeName(m mountEntry) string {
+	if isNetworkFS(m.FSType) {
+		return "Network Drive (" + m.FSType + ")"
+	}
+	return "Local Drive (" + m.FSType + ")"
+}
+
+// blockSize возвращает размер блока, в котором statfs считает Blocks/Bfree/Bavail
+func blockSize(st *unix.Statfs_t) uint64 {
+	if st.Frsize > 0 {
+		return uint64(st.Frsize)
+	}
+	return uint64(st.Bsize)
+}
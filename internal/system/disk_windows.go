@@ -0,0 +1,476 @@
+//go:build windows
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsDisks реализует diskPlatform через Windows API
+type windowsDisks struct{}
+
+var platform diskPlatform = windowsDisks{}
+
+func (windowsDisks) listDisks(verbose bool) ([]DiskInfo, error) {
+	var disks []DiskInfo
+
+	// Direct A-Z enumeration for maximum compatibility
+	for c := 'A'; c <= 'Z'; c++ {
+		drive := string(c) + ":"
+		driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
+
+		// Skip non-existent drives
+		if driveType == windows.DRIVE_NO_ROOT_DIR || driveType == windows.DRIVE_UNKNOWN {
+			continue
+		}
+
+		// Include all valid drive types
+		if driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
+			driveType == windows.DRIVE_REMOTE || driveType == windows.DRIVE_RAMDISK ||
+			driveType == windows.DRIVE_CDROM {
+
+			info, err := getDriveInfo(drive, driveType)
+			if err != nil {
+				if verbose {
+					fmt.Printf("[WARN] Skipping drive %s: %v\n", drive, err)
+				}
+				continue // Skip inaccessible drives
+			}
+
+			disks = append(disks, info)
+		}
+	}
+
+	return disks, nil
+}
+
+func (windowsDisks) diskSpace(drive string) (uint64, uint64, error) {
+	// Convert path to UTF16 for Windows API
+	drivePath, err := syscall.UTF16PtrFromString(drive)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var freeBytesAvailable, totalBytes, freeBytes uint64
+
+	// Call GetDiskFreeSpaceExW with proper uintptr casting
+	ret, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(drivePath)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)),
+		uintptr(unsafe.Pointer(&totalBytes)),
+		uintptr(unsafe.Pointer(&freeBytes)),
+	)
+
+	if ret == 0 {
+		return 0, 0, fmt.Errorf("GetDiskFreeSpaceExW failed: %w", err)
+	}
+
+	return freeBytes, totalBytes, nil
+}
+
+func (windowsDisks) isLocal(drive string) bool {
+	driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
+	return driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
+		driveType == windows.DRIVE_RAMDISK || driveType == windows.DRIVE_CDROM
+}
+
+func (windowsDisks) logicalDrives() []string {
+	var drives []string
+
+	// Use Windows API to get drives
+	kernel32, err := syscall.LoadLibrary("kernel32.dll")
+	if err != nil {
+		// Fallback to simple method
+		for c := 'A'; c <= 'Z'; c++ {
+			drive := string(c) + ":"
+			if _, err := os.Stat(drive + "\\"); err == nil {
+				drives = append(drives, drive)
+			}
+		}
+		return drives
+	}
+	defer syscall.FreeLibrary(kernel32)
+
+	getLogicalDrivesProc, err := syscall.GetProcAddress(kernel32, "GetLogicalDrives")
+	if err != nil {
+		return drives
+	}
+
+	ret, _, _ := syscall.Syscall(uintptr(getLogicalDrivesProc), 0, 0, 0, 0)
+	drivesMask := uint32(ret)
+
+	for c := 0; c < 26; c++ {
+		if drivesMask&(1<<c) != 0 {
+			drive := string(rune('A'+c)) + ":"
+			drives = append(drives, drive)
+		}
+	}
+
+	return drives
+}
+
+func (windowsDisks) availableDrives() []DriveInfo {
+	var drives []DriveInfo
+
+	// Direct A-Z enumeration for maximum compatibility
+	for c := 'A'; c <= 'Z'; c++ {
+		drive := string(c) + ":"
+		driveType := windows.GetDriveType(windows.StringToUTF16Ptr(drive))
+
+		// Skip non-existent drives
+		if driveType == windows.DRIVE_NO_ROOT_DIR || driveType == windows.DRIVE_UNKNOWN {
+			continue
+		}
+
+		// Include all valid drive types
+		if driveType == windows.DRIVE_FIXED || driveType == windows.DRIVE_REMOVABLE ||
+			driveType == windows.DRIVE_REMOTE || driveType == windows.DRIVE_RAMDISK ||
+			driveType == windows.DRIVE_CDROM {
+
+			// Get free space
+			freeSpace, totalSpace := GetDiskSpace(drive, false)
+
+			// Mark as [Not Ready] if total space is 0 (unformatted or inaccessible)
+			driveTypeStr := getDriveTypeName(driveType)
+			if totalSpace == 0 {
+				driveTypeStr += " [Not Ready]"
+			}
+
+			drives = append(drives, DriveInfo{
+				Letter:   drive,
+				Type:     driveTypeStr,
+				IsSystem: isSystemDrive(drive),
+				FreeSize: freeSpace,
+			})
+		}
+	}
+
+	return drives
+}
+
+func (windowsDisks) diskInfoForPath(path string) (DiskInfo, error) {
+	// Каталог разрешается до тома: буквы диска или смонтированной папки
+	root, err := volumePathName(VolumeRoot(path))
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("ошибка получения информации о диске: %w", err)
+	}
+	drivePath := strings.TrimSuffix(root, `\`)
+
+	var freeBytesAvailable, totalBytes, freeBytes uint64
+
+	err = windows.GetDiskFreeSpaceEx(
+		windows.StringToUTF16Ptr(drivePath),
+		&freeBytesAvailable,
+		&totalBytes,
+		&freeBytes,
+	)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("ошибка получения информации о диске: %w", err)
+	}
+
+	// Определение типа диска
+	diskType := getDiskType(drivePath)
+
+	// Проверка системного диска
+	isSystem := isSystemDisk(drivePath)
+
+	return DiskInfo{
+		Letter:         drivePath,
+		Type:           diskType,
+		TotalSize:      totalBytes,
+		FreeSize:       freeBytes,
+		UsedSize:       totalBytes - freeBytes,
+		IsSystem:       isSystem,
+		IsWritable:     checkWriteAccess(drivePath),
+		BackingDevices: backingDevices(root),
+		Model:          "",
+		Serial:         "",
+		Interface:      "",
+	}, nil
+}
+
+// ioctlVolumeGetVolumeDiskExtents - IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS (winioctl.h)
+const ioctlVolumeGetVolumeDiskExtents = 0x00560000
+
+// diskExtent - DISK_EXTENT
+type diskExtent struct {
+	DiskNumber     uint32
+	_              uint32
+	StartingOffset int64
+	ExtentLength   int64
+}
+
+// volumeDiskExtents - VOLUME_DISK_EXTENTS с запасом на составные тома
+type volumeDiskExtents struct {
+	NumberOfDiskExtents uint32
+	_                   uint32
+	Extents             [16]diskExtent
+}
+
+// backingDevices возвращает физические диски тома с корнем root (D:\ или
+// смонтированная папка) в виде PhysicalDriveN. nil - диски определить не
+// удалось (сетевой диск, нет доступа к тому).
+func backingDevices(root string) []string {
+	if !strings.HasSuffix(root, `\`) {
+		root += `\`
+	}
+	mountPoint, err := windows.UTF16PtrFromString(root)
+	if err != nil {
+		return nil
+	}
+	name := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumeNameForVolumeMountPoint(mountPoint, &name[0], uint32(len(name))); err != nil {
+		return nil
+	}
+
+	// \\?\Volume{GUID}\ без завершающей косой черты открывает сам том
+	disks, err := volumeDiskNumbers(strings.TrimSuffix(windows.UTF16ToString(name), `\`))
+	if err != nil {
+		return nil
+	}
+	devices := make([]string, 0, len(disks))
+	for _, n := range disks {
+		devices = append(devices, fmt.Sprintf("PhysicalDrive%d", n))
+	}
+	return devices
+}
+
+// volumeDiskNumbers возвращает номера физических дисков, на которых лежат
+// экстенты тома (\\.\D: или \\?\Volume{GUID}), через
+// IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS
+func volumeDiskNumbers(volume string) ([]uint32, error) {
+	name, err := windows.UTF16PtrFromString(volume)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := windows.CreateFile(name, 0, windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var extents volumeDiskExtents
+	var returned uint32
+	if err := windows.DeviceIoControl(handle, ioctlVolumeGetVolumeDiskExtents, nil, 0,
+		(*byte)(unsafe.Pointer(&extents)), uint32(unsafe.Sizeof(extents)), &returned, nil); err != nil {
+		return nil, fmt.Errorf("IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS failed: %w", err)
+	}
+
+	var disks []uint32
+	count := min(int(extents.NumberOfDiskExtents), len(extents.Extents))
+	for _, e := range extents.Extents[:count] {
+		if !slices.Contains(disks, e.DiskNumber) {
+			disks = append(disks, e.DiskNumber)
+		}
+	}
+	return disks, nil
+}
+
+func (windowsDisks) targetSpace(dir string) (uint64, error) {
+	// Место, доступное вызывающему, учитывает квоты NTFS и FSRM
+	var freeBytesAvailable, totalBytes, freeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(
+		windows.StringToUTF16Ptr(dir),
+		&freeBytesAvailable,
+		&totalBytes,
+		&freeBytes,
+	); err != nil {
+		return 0, fmt.Errorf("GetDiskFreeSpaceExW failed: %w", err)
+	}
+	return freeBytesAvailable, nil
+}
+
+func (windowsDisks) clusterSize(path string) (uint64, error) {
+	root, err := volumePathName(path)
+	if err != nil {
+		return 0, err
+	}
+	rootPtr, err := windows.UTF16PtrFromString(root)
+	if err != nil {
+		return 0, err
+	}
+
+	var sectorsPerCluster, bytesPerSector, freeClusters, totalClusters uint32
+	ret, _, err := procGetDiskFreeSpaceW.Call(
+		uintptr(unsafe.Pointer(rootPtr)),
+		uintptr(unsafe.Pointer(&sectorsPerCluster)),
+		uintptr(unsafe.Pointer(&bytesPerSector)),
+		uintptr(unsafe.Pointer(&freeClusters)),
+		uintptr(unsafe.Pointer(&totalClusters)),
+	)
+	if ret == 0 {
+		return 0, fmt.Errorf("GetDiskFreeSpaceW failed: %w", err)
+	}
+	return uint64(sectorsPerCluster) * uint64(bytesPerSector), nil
+}
+
+// volumePathName возвращает корень тома, содержащего path: букву диска
+// (D:\) или смонтированную папку (C:\mnt\data\)
+func volumePathName(path string) (string, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+	root := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumePathName(name, &root[0], uint32(len(root))); err != nil {
+		return "", fmt.Errorf("GetVolumePathNameW failed: %w", err)
+	}
+	return windows.UTF16ToString(root), nil
+}
+
+func (windowsDisks) isSystem(drive string) bool {
+	// Get system drive dynamically
+	systemDrive := getSystemDrive()
+	return strings.EqualFold(drive, systemDrive)
+}
+
+// getDriveInfo gets detailed drive information
+func getDriveInfo(drive string, driveType uint32) (DiskInfo, error) {
+	info := DiskInfo{
+		Letter:     drive,
+		Type:       getDriveTypeName(driveType),
+		IsSystem:   isSystemDrive(drive),
+		IsWritable: true,
+		Model:      "Unknown Model",
+		Serial:     "Unknown Serial",
+		Interface:  "Unknown Interface",
+	}
+
+	// Get free space information
+	freeSize, totalSize := GetDiskSpace(drive, false)
+	info.FreeSize = freeSize
+	info.TotalSize = totalSize
+
+	// Determine disk type based on Windows drive type
+	switch driveType {
+	case windows.DRIVE_FIXED:
+		if info.IsSystem {
+			info.Type = "SSD" // Assume SSD for system drive
+		} else {
+			info.Type = "HDD" // Assume HDD for other fixed drives
+		}
+	case windows.DRIVE_REMOVABLE:
+		info.Type = "USB/Flash"
+	case windows.DRIVE_CDROM:
+		info.Type = "CD/DVD"
+	case windows.DRIVE_RAMDISK:
+		info.Type = "RAM Disk"
+	case windows.DRIVE_REMOTE:
+		info.Type = "Network"
+	default:
+		info.Type = "Unknown"
+	}
+
+	// Check write access
+	info.IsWritable = checkWriteAccess(drive)
+	info.BackingDevices = backingDevices(drive)
+
+	return info, nil
+}
+
+// getDriveTypeName converts Windows drive type to readable string
+func getDriveTypeName(driveType uint32) string {
+	switch driveType {
+	case windows.DRIVE_FIXED:
+		return "Fixed Drive"
+	case windows.DRIVE_REMOVABLE:
+		return "Removable Drive"
+	case windows.DRIVE_CDROM:
+		return "CD-ROM"
+	case windows.DRIVE_RAMDISK:
+		return "RAM Disk"
+	case windows.DRIVE_REMOTE:
+		return "Network Drive"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsAdmin checks if current process has administrator privileges
+func IsAdmin() bool {
+	var sid *windows.SID
+
+	// Create well-known SID for administrators group
+	err := windows.AllocateAndInitializeSid(
+		&windows.SECURITY_NT_AUTHORITY,
+		2,
+		windows.SECURITY_BUILTIN_DOMAIN_RID,
+		windows.DOMAIN_ALIAS_RID_ADMINS,
+		0, 0, 0, 0, 0, 0,
+		&sid,
+	)
+	if err != nil {
+		// Fallback: try opening physical drive
+		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
+		if fallbackErr == nil {
+			return true
+		}
+		return false
+	}
+	defer windows.FreeSid(sid)
+
+	// Get current process token
+	token, err := windows.OpenCurrentProcessToken()
+	if err != nil {
+		// Fallback: try opening physical drive
+		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
+		if fallbackErr == nil {
+			return true
+		}
+		return false
+	}
+	defer token.Close()
+
+	// Check if token is member of administrators group
+	member, err := token.IsMember(sid)
+	if err != nil {
+		// Fallback: try opening physical drive
+		_, fallbackErr := os.Open("\\\\.\\PHYSICALDRIVE0")
+		if fallbackErr == nil {
+			return true
+		}
+		return false
+	}
+
+	return member
+}
+
+// Windows API functions for GetDiskFreeSpaceEx
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procGetDiskFreeSpaceExW = kernel32.NewProc("GetDiskFreeSpaceExW")
+	procGetDiskFreeSpaceW   = kernel32.NewProc("GetDiskFreeSpaceW")
+)
+
+// getDiskType определяет тип диска (HDD/SSD)
+func getDiskType(drivePath string) string {
+	// В реальной реализации здесь будет определение типа диска
+	// через WMI или другие Windows API
+	// Пока возвращаем Unknown
+	return "Unknown"
+}
+
+// isSystemDisk проверяет, является ли диск системным
+func isSystemDisk(drivePath string) bool {
+	// Получаем путь к системной директории
+	sysDir, err := windows.GetSystemDirectory()
+	if err != nil {
+		return false
+	}
+
+	if len(sysDir) >= 2 {
+		// Смонтированная папка на системном диске - отдельный том
+		systemDrive := sysDir[:2]
+		return strings.EqualFold(strings.TrimSuffix(drivePath, `\`), systemDrive)
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -121,7 +122,9 @@ func OptimizeDisks(ctx context.Context, logger *logging.EnterpriseLogger) (uint6
 		if disk.Type == "SSD" {
 			// Для SSD выполняем TRIM
 			logger.Log("INFO", "Выполнение TRIM для SSD", "disk", disk.Letter)
-			if err := optimizeSSD(disk.Letter, logger); err != nil {
+			if err := optimizeSSD(ctx, disk.Letter, logger); errors.Is(err, ErrTrimUnsupported) {
+				logger.Log("INFO", "TRIM не поддерживается", "disk", disk.Letter)
+			} else if err != nil {
 				logger.Log("WARN", "Ошибка оптимизации SSD", "disk", disk.Letter, "error", err)
 			}
 		} else {
@@ -168,11 +171,13 @@ func cleanDirectory(path string, logger *logging.EnterpriseLogger) (uint64, erro
 	return totalSize, nil
 }
 
-// optimizeSSD выполняет оптимизацию SSD через TRIM
-func optimizeSSD(drive string, logger *logging.EnterpriseLogger) error {
-	// Заглушка - в реальной реализации нужно использовать Windows API
-	// для отправки TRIM команды
-	logger.Log("INFO", "TRIM выполнен", "drive", drive)
+// optimizeSSD выполняет оптимизацию SSD через TRIM свободного места
+func optimizeSSD(ctx context.Context, drive string, logger *logging.EnterpriseLogger) error {
+	result, err := TrimVolume(ctx, drive)
+	if err != nil {
+		return err
+	}
+	logger.Log("INFO", "TRIM выполнен", "drive", drive, "bytes_trimmed", result.BytesTrimmed, "duration", result.Duration.String())
 	return nil
 }
 
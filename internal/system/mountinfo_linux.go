@@ -0,0 +1,194 @@
+//go:build linux
+
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// mountInfoPath путь к таблице монтирования текущего процесса
+const mountInfoPath = "/proc/self/mountinfo"
+
+// mountEntry одна строка /proc/self/mountinfo
+type mountEntry struct {
+	MountID    int
+	ParentID   int
+	Major      uint32
+	Minor      uint32
+	Root       string
+	MountPoint string
+	Options    []string
+	FSType     string
+	Source     string
+}
+
+// pseudoFilesystems файловые системы, которые не хранят пользовательских данных
+var pseudoFilesystems = map[string]bool{
+	"autofs":      true,
+	"binfmt_misc": true,
+	"bpf":         true,
+	"cgroup":      true,
+	"cgroup2":     true,
+	"configfs":    true,
+	"debugfs":     true,
+	"devpts":      true,
+	"devtmpfs":    true,
+	"efivarfs":    true,
+	"fusectl":     true,
+	"hugetlbfs":   true,
+	"mqueue":      true,
+	"nsfs":        true,
+	"overlay":     true,
+	"proc":        true,
+	"pstore":      true,
+	"ramfs":       true,
+	"rpc_pipefs":  true,
+	"securityfs":  true,
+	"squashfs":    true,
+	"sysfs":       true,
+	"tmpfs":       true,
+	"tracefs":     true,
+}
+
+// networkFilesystems сетевые файловые системы (аналог DRIVE_REMOTE)
+var networkFilesystems = map[string]bool{
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smb3":       true,
+	"9p":         true,
+	"ceph":       true,
+	"glusterfs":  true,
+	"fuse.sshfs": true,
+}
+
+// isPseudoFS проверяет, является ли файловая система псевдо-ФС
+func isPseudoFS(fsType string) bool {
+	return pseudoFilesystems[fsType]
+}
+
+// isNetworkFS проверяет, является ли файловая система сетевой
+func isNetworkFS(fsType string) bool {
+	return networkFilesystems[fsType]
+}
+
+// readMountInfo читает и разбирает таблицу монтирования
+func readMountInfo(path string) ([]mountEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return parseMountInfo(f)
+}
+
+// parseMountInfo разбирает формат mountinfo (см. proc(5)):
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func parseMountInfo(r io.Reader) ([]mountEntry, error) {
+	var entries []mountEntry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		// Опциональные поля заканчиваются разделителем "-"
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || sep+2 >= len(fields) {
+			continue
+		}
+
+		mountID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("некорректный mount ID %q: %w", fields[0], err)
+		}
+		parentID, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("некорректный parent ID %q: %w", fields[1], err)
+		}
+		major, minor, err := parseMajorMinor(fields[2])
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, mountEntry{
+			MountID:    mountID,
+			ParentID:   parentID,
+			Major:      major,
+			Minor:      minor,
+			Root:       unescapeMountField(fields[3]),
+			MountPoint: unescapeMountField(fields[4]),
+			Options:    strings.Split(fields[5], ","),
+			FSType:     fields[sep+1],
+			Source:     unescapeMountField(fields[sep+2]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения mountinfo: %w", err)
+	}
+
+	return entries, nil
+}
+
+// parseMajorMinor разбирает поле "major:minor"
+func parseMajorMinor(s string) (uint32, uint32, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("некорректное поле major:minor %q", s)
+	}
+	major, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректный major %q: %w", s, err)
+	}
+	minor, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректный minor %q: %w", s, err)
+	}
+	return uint32(major), uint32(minor), nil
+}
+
+// unescapeMountField раскрывает восьмеричные escape-последовательности (\040 и т.п.)
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// hasOption проверяет наличие опции монтирования
+func (m mountEntry) hasOption(opt string) bool {
+	for _, o := range m.Options {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package system
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Константы quotactl(2) из linux/quota.h
+const (
+	qGetQuota = 0x800007 // Q_GETQUOTA
+	usrQuota  = 0        // USRQUOTA
+	grpQuota  = 1        // GRPQUOTA
+
+	qifBLimits = 1 // QIF_BLIMITS: заданы лимиты блоков
+	qifSpace   = 4 // QIF_SPACE: известен занятый объем
+
+	quotaBlockSize = 1024 // Единица лимитов блоков (QIF_DQBLKSIZE)
+)
+
+// ifDqblk повторяет struct if_dqblk
+type ifDqblk struct {
+	BHardLimit uint64
+	BSoftLimit uint64
+	CurSpace   uint64
+	IHardLimit uint64
+	ISoftLimit uint64
+	CurInodes  uint64
+	BTime      uint64
+	ITime      uint64
+	Valid      uint32
+}
+
+// quotaLeft возвращает остаток жесткой квоты пользователя или группы id на
+// устройстве device. ok = false, если квоты на ФС выключены, лимит не задан
+// или устройство не поддерживает quotactl (сетевые ФС, tmpfs).
+func quotaLeft(device string, quotaType, id int) (left uint64, ok bool) {
+	special, err := unix.BytePtrFromString(device)
+	if err != nil {
+		return 0, false
+	}
+
+	var dq ifDqblk
+	cmd := qGetQuota<<8 | quotaType
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(special)),
+		uintptr(id), uintptr(unsafe.Pointer(&dq)), 0, 0)
+	if errno != 0 || dq.Valid&(qifBLimits|qifSpace) != qifBLimits|qifSpace || dq.BHardLimit == 0 {
+		return 0, false
+	}
+
+	limit := dq.BHardLimit * quotaBlockSize
+	if dq.CurSpace >= limit {
+		return 0, true
+	}
+	return limit - dq.CurSpace, true
+}
@@ -0,0 +1,17 @@
+package system
+
+// RawDevice блочное устройство или файл образа диска для затирания целиком
+type RawDevice struct {
+	Path       string
+	Size       uint64 // Размер в байтах
+	SectorSize uint32 // Логический размер сектора
+	Block      bool   // true - блочное устройство, false - файл образа
+}
+
+// InspectRawDevice определяет размер и размер сектора устройства или файла
+// образа и проверяет, что оно не используется: не смонтировано, не
+// подключено как swap и не входит в активный массив md или том LVM.
+// Ошибка означает, что затирать устройство нельзя.
+func InspectRawDevice(path string) (*RawDevice, error) {
+	return inspectRawDevice(path)
+}
@@ -0,0 +1,180 @@
+//go:build linux
+
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// swapsPath список активных областей подкачки
+const swapsPath = "/proc/swaps"
+
+func inspectRawDevice(path string) (*RawDevice, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular():
+		if err := checkImageFree(path); err != nil {
+			return nil, err
+		}
+		return &RawDevice{Path: path, Size: uint64(info.Size()), SectorSize: 512}, nil
+	case mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0:
+	default:
+		return nil, fmt.Errorf("%s не является блочным устройством или файлом образа", path)
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("%s: не удалось получить номер устройства", path)
+	}
+	rdev := uint64(st.Rdev)
+	if err := checkBlockDeviceFree(DefaultSysfsRoot, unix.Major(rdev), unix.Minor(rdev)); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка определения размера устройства: %w", err)
+	}
+	sector, err := unix.IoctlGetInt(int(file.Fd()), unix.BLKSSZGET)
+	if err != nil || sector <= 0 {
+		sector = 512
+	}
+
+	return &RawDevice{Path: path, Size: uint64(size), SectorSize: uint32(sector), Block: true}, nil
+}
+
+// checkBlockDeviceFree проверяет, что ни устройство, ни его разделы не
+// смонтированы, не подключены как swap и не заняты устройствами-держателями
+// (md, device-mapper/LVM, dm-crypt)
+func checkBlockDeviceFree(sysfsRoot string, major, minor uint32) error {
+	dir, err := resolveSysfsDir(filepath.Join(sysfsRoot, "dev", "block", fmt.Sprintf("%d:%d", major, minor)))
+	if err != nil {
+		return fmt.Errorf("блочное устройство %d:%d не найдено в sysfs: %w", major, minor, err)
+	}
+
+	// Устройство и все его разделы: номер major:minor -> имя
+	members := map[string]string{fmt.Sprintf("%d:%d", major, minor): filepath.Base(dir)}
+	dirs := []string{dir}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		partDir := filepath.Join(dir, e.Name())
+		if !fileExists(filepath.Join(partDir, "partition")) {
+			continue
+		}
+		if dev := readSysfsString(filepath.Join(partDir, "dev")); dev != "" {
+			members[dev] = e.Name()
+			dirs = append(dirs, partDir)
+		}
+	}
+
+	for _, d := range dirs {
+		holders, _ := os.ReadDir(filepath.Join(d, "holders"))
+		if len(holders) > 0 {
+			names := make([]string, 0, len(holders))
+			for _, h := range holders {
+				names = append(names, h.Name())
+			}
+			return fmt.Errorf("%s входит в активный массив md или том LVM/device-mapper (%s)",
+				filepath.Base(d), strings.Join(names, ", "))
+		}
+	}
+
+	mounts, err := readMountInfo(mountInfoPath)
+	if err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if name, ok := members[fmt.Sprintf("%d:%d", m.Major, m.Minor)]; ok {
+			return fmt.Errorf("%s смонтировано в %s", name, m.MountPoint)
+		}
+	}
+
+	swaps, err := readSwaps(swapsPath)
+	if err != nil {
+		return err
+	}
+	for _, swap := range swaps {
+		var st unix.Stat_t
+		if unix.Stat(swap, &st) != nil || st.Mode&unix.S_IFMT != unix.S_IFBLK {
+			continue
+		}
+		key := fmt.Sprintf("%d:%d", unix.Major(uint64(st.Rdev)), unix.Minor(uint64(st.Rdev)))
+		if name, ok := members[key]; ok {
+			return fmt.Errorf("%s используется как swap", name)
+		}
+	}
+	return nil
+}
+
+// checkImageFree проверяет, что файл образа не подключен как swap и не
+// является файлом loop-устройства
+func checkImageFree(path string) error {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	if target, err = filepath.Abs(target); err != nil {
+		return err
+	}
+
+	swaps, err := readSwaps(swapsPath)
+	if err != nil {
+		return err
+	}
+	for _, swap := range swaps {
+		if swap == target {
+			return fmt.Errorf("%s используется как swap", path)
+		}
+	}
+
+	backing, _ := filepath.Glob(filepath.Join(DefaultSysfsRoot, "block", "loop*", "loop", "backing_file"))
+	for _, b := range backing {
+		if readSysfsString(b) == target {
+			loop := filepath.Base(filepath.Dir(filepath.Dir(b)))
+			return fmt.Errorf("%s подключен как /dev/%s", path, loop)
+		}
+	}
+	return nil
+}
+
+// readSwaps возвращает пути активных областей подкачки
+func readSwaps(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var swaps []string
+	scanner := bufio.NewScanner(file)
+	for first := true; scanner.Scan(); first = false {
+		fields := strings.Fields(scanner.Text())
+		if first || len(fields) == 0 {
+			continue // Заголовок таблицы
+		}
+		swaps = append(swaps, unescapeMountField(fields[0]))
+	}
+	return swaps, scanner.Err()
+}
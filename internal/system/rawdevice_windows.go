@@ -0,0 +1,123 @@
+//go:build windows
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Коды DeviceIoControl (winioctl.h)
+const (
+	ioctlDiskGetDriveGeometry = 0x00070000
+	ioctlDiskGetLengthInfo    = 0x0007405C
+)
+
+// physicalDrivePrefix - префикс пути физического диска (\\.\PhysicalDriveN)
+const physicalDrivePrefix = `\\.\physicaldrive`
+
+// diskGeometry - DISK_GEOMETRY
+type diskGeometry struct {
+	Cylinders         int64
+	MediaType         uint32
+	TracksPerCylinder uint32
+	SectorsPerTrack   uint32
+	BytesPerSector    uint32
+}
+
+func inspectRawDevice(path string) (*RawDevice, error) {
+	lower := strings.ToLower(path)
+	if !strings.HasPrefix(lower, physicalDrivePrefix) {
+		return inspectImage(path)
+	}
+
+	number, err := strconv.ParseUint(lower[len(physicalDrivePrefix):], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("неверный путь физического диска: %s", path)
+	}
+	if err := checkPhysicalDriveFree(uint32(number)); err != nil {
+		return nil, err
+	}
+
+	handle, err := openDevice(path, windows.GENERIC_READ)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия %s: %w", path, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	var length int64
+	var returned uint32
+	if err := windows.DeviceIoControl(handle, ioctlDiskGetLengthInfo, nil, 0,
+		(*byte)(unsafe.Pointer(&length)), uint32(unsafe.Sizeof(length)), &returned, nil); err != nil {
+		return nil, fmt.Errorf("IOCTL_DISK_GET_LENGTH_INFO failed: %w", err)
+	}
+
+	sector := uint32(512)
+	var geometry diskGeometry
+	if err := windows.DeviceIoControl(handle, ioctlDiskGetDriveGeometry, nil, 0,
+		(*byte)(unsafe.Pointer(&geometry)), uint32(unsafe.Sizeof(geometry)), &returned, nil); err == nil && geometry.BytesPerSector > 0 {
+		sector = geometry.BytesPerSector
+	}
+
+	return &RawDevice{Path: path, Size: uint64(length), SectorSize: sector, Block: true}, nil
+}
+
+// inspectImage проверяет файл образа: он не должен быть открыт другим
+// процессом (подключенный VHD или образ виртуальной машины)
+func inspectImage(path string) (*RawDevice, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s не является физическим диском или файлом образа", path)
+	}
+
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := windows.CreateFile(name, windows.GENERIC_READ, 0, nil, windows.OPEN_EXISTING, windows.FILE_ATTRIBUTE_NORMAL, 0)
+	if errors.Is(err, windows.ERROR_SHARING_VIOLATION) {
+		return nil, fmt.Errorf("%s открыт другим процессом (образ подключен?)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	windows.CloseHandle(handle)
+
+	return &RawDevice{Path: path, Size: uint64(info.Size()), SectorSize: 512}, nil
+}
+
+// checkPhysicalDriveFree проверяет, что на диске нет смонтированных томов.
+// Файл подкачки и динамические тома располагаются на томах с буквами и
+// тоже обнаруживаются этой проверкой.
+func checkPhysicalDriveFree(number uint32) error {
+	for _, drive := range platform.logicalDrives() {
+		disks, err := volumeDiskNumbers(`\\.\` + drive)
+		if err != nil {
+			continue
+		}
+		if slices.Contains(disks, number) {
+			return fmt.Errorf("на диске PhysicalDrive%d находится смонтированный том %s", number, drive)
+		}
+	}
+	return nil
+}
+
+// openDevice открывает устройство с общим доступом на чтение и запись
+func openDevice(path string, access uint32) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	return windows.CreateFile(name, access, windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil, windows.OPEN_EXISTING, 0, 0)
+}
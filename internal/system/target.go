@@ -0,0 +1,91 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ResolveWipeTarget разрешает цель затирания свободного места. Буква диска
+// ("D", "D:", "D:\") и корень тома дают том целиком. Любой другой
+// существующий каталог - точка монтирования внутри тома, смонтированная
+// папка Windows, подкаталог общей ФС - дает содержащий его том с Path,
+// равным каталогу: файлы затирания создаются только в нем, а FreeSize -
+// место, доступное в нем текущему пользователю с учетом квот и без блоков,
+// зарезервированных для root.
+func ResolveWipeTarget(target string) (DiskInfo, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return DiskInfo{}, fmt.Errorf("пустой путь к диску")
+	}
+	if isDriveSpec(target) {
+		return GetDiskInfoForPath(target)
+	}
+
+	dir, err := filepath.Abs(target)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("некорректный путь %s: %w", target, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	st, err := os.Stat(dir)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("путь %s недоступен: %w", target, err)
+	}
+	if !st.IsDir() {
+		return DiskInfo{}, fmt.Errorf("%s не является каталогом", target)
+	}
+
+	info, err := platform.diskInfoForPath(dir)
+	if err != nil {
+		return DiskInfo{}, err
+	}
+	if samePath(dir, VolumeRoot(info.Letter)) {
+		return info, nil // Корень тома
+	}
+
+	free, err := platform.targetSpace(dir)
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("ошибка получения свободного места в %s: %w", dir, err)
+	}
+	info.Path = dir
+	info.FreeSize = min(info.FreeSize, free)
+	// Права на корень тома для каталога не важны: на общей ФС пользователь
+	// обычно может писать только в свой каталог
+	info.IsWritable = checkWriteAccess(dir)
+	return info, nil
+}
+
+// SameTarget сравнивает цели затирания: буквы дисков - после нормализации,
+// каталоги и точки монтирования - как пути
+func SameTarget(a, b string) bool {
+	if isDriveSpec(a) || isDriveSpec(b) {
+		return normalizePath(a) == normalizePath(b)
+	}
+	return samePath(a, b)
+}
+
+// isDriveSpec проверяет, что target задает том буквой диска: "D", "D:",
+// "D:\" или "\\.\D:"
+func isDriveSpec(target string) bool {
+	t := strings.TrimPrefix(strings.TrimSpace(target), `\\.\`)
+	t = strings.TrimRight(t, `\/. `)
+	if len(t) == 0 || len(t) > 2 || (len(t) == 2 && t[1] != ':') {
+		return false
+	}
+	c := t[0] | 0x20
+	return c >= 'a' && c <= 'z'
+}
+
+// samePath сравнивает пути без учета завершающего разделителя, в Windows -
+// и без учета регистра
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(strings.TrimSpace(a)), filepath.Clean(strings.TrimSpace(b))
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
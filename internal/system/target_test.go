@@ -0,0 +1,103 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsDriveSpec(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"D", true},
+		{"d:", true},
+		{`D:\`, true},
+		{"D:/", true},
+		{`\\.\D:`, true},
+		{" C: ", true},
+		{"", false},
+		{"1:", false},
+		{"DD", false},
+		{`D:\data`, false},
+		{"/mnt/data", false},
+	}
+	for _, tt := range tests {
+		if got := isDriveSpec(tt.target); got != tt.want {
+			t.Errorf("isDriveSpec(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestSameTarget(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"D", `D:\`, true},
+		{"d:", "D:", true},
+		{"D:", "E:", false},
+		{"/mnt/data", "/mnt/data/", true},
+		{"/mnt/data", "/mnt/data/sub", false},
+		{"D:", "/mnt/data", false},
+	}
+	for _, tt := range tests {
+		if got := SameTarget(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameTarget(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolveWipeTarget(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Каталоги сравниваются после раскрытия ссылок (в macOS TMPDIR - ссылка)
+	wantSub, err := filepath.EvalSymlinks(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantPath string
+		wantErr  bool
+	}{
+		{"подкаталог", sub, wantSub, false},
+		{"завершающий разделитель", sub + string(filepath.Separator), wantSub, false},
+		{"пустой путь", "  ", "", true},
+		{"нет каталога", filepath.Join(dir, "missing"), "", true},
+		{"файл", file, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ResolveWipeTarget(tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ResolveWipeTarget(%q): ожидалась ошибка, получено %+v", tt.target, info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", info.Path, tt.wantPath)
+			}
+			if info.Target() != tt.wantPath {
+				t.Errorf("Target() = %q, want %q", info.Target(), tt.wantPath)
+			}
+			if !info.IsWritable {
+				t.Error("временный каталог должен быть доступен для записи")
+			}
+		})
+	}
+}
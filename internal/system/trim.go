@@ -0,0 +1,30 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTrimUnsupported - файловая система или устройство тома не поддерживает TRIM
+var ErrTrimUnsupported = errors.New("TRIM не поддерживается файловой системой или устройством")
+
+// TrimResult итоги TRIM тома
+type TrimResult struct {
+	BytesTrimmed uint64 // Объем, о котором сообщила файловая система (0 - неизвестен)
+	Duration     time.Duration
+}
+
+// TrimVolume сообщает устройству о всех свободных блоках тома, на котором
+// находится path (FITRIM в Linux, повторный TRIM через defrag /L в Windows).
+// Если том не поддерживает TRIM, возвращается ошибка, обернутая в
+// ErrTrimUnsupported.
+func TrimVolume(ctx context.Context, path string) (*TrimResult, error) {
+	start := time.Now()
+	result, err := platformTrim(ctx, VolumeRoot(path))
+	if err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
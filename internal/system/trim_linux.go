@@ -0,0 +1,44 @@
+//go:build linux
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// fitrim - _IOWR('X', 121, struct fstrim_range)
+const fitrim = 0xc0185879
+
+// fstrimRange - struct fstrim_range
+type fstrimRange struct {
+	start  uint64
+	length uint64
+	minLen uint64
+}
+
+func platformTrim(ctx context.Context, root string) (*TrimResult, error) {
+	dir, err := os.Open(root)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	// Ядро возвращает в length объем переданных устройству блоков
+	r := fstrimRange{length: math.MaxUint64}
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, dir.Fd(), fitrim, uintptr(unsafe.Pointer(&r)))
+	switch {
+	case errno == 0:
+		return &TrimResult{BytesTrimmed: r.length}, nil
+	case errors.Is(errno, unix.EOPNOTSUPP), errors.Is(errno, unix.ENOTTY):
+		return nil, fmt.Errorf("%w: %s", ErrTrimUnsupported, root)
+	default:
+		return nil, fmt.Errorf("FITRIM %s: %w", root, errno)
+	}
+}
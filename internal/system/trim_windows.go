@@ -0,0 +1,67 @@
+//go:build windows
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// IOCTL_STORAGE_QUERY_PROPERTY и StorageDeviceTrimProperty (winioctl.h)
+const (
+	ioctlStorageQueryProperty = 0x002D1400
+	storageDeviceTrimProperty = 8
+)
+
+// storagePropertyQuery - STORAGE_PROPERTY_QUERY
+type storagePropertyQuery struct {
+	PropertyID uint32
+	QueryType  uint32
+	Additional [1]byte
+}
+
+// deviceTrimDescriptor - DEVICE_TRIM_DESCRIPTOR
+type deviceTrimDescriptor struct {
+	Version     uint32
+	Size        uint32
+	TrimEnabled byte
+}
+
+func platformTrim(ctx context.Context, root string) (*TrimResult, error) {
+	drive := strings.TrimRight(root, `\`)
+	if err := checkTrimSupported(drive); err != nil {
+		return nil, err
+	}
+
+	// defrag /L - повторная отправка TRIM для всего свободного места тома
+	output, err := exec.CommandContext(ctx, "defrag.exe", drive, "/L").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("defrag %s /L: %w: %s", drive, err, strings.TrimSpace(string(output)))
+	}
+	return &TrimResult{}, nil
+}
+
+// checkTrimSupported проверяет, принимает ли устройство тома TRIM
+func checkTrimSupported(drive string) error {
+	handle, err := openDevice(`\\.\`+drive, 0)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия тома %s: %w", drive, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	query := storagePropertyQuery{PropertyID: storageDeviceTrimProperty}
+	var desc deviceTrimDescriptor
+	var returned uint32
+	err = windows.DeviceIoControl(handle, ioctlStorageQueryProperty,
+		(*byte)(unsafe.Pointer(&query)), uint32(unsafe.Sizeof(query)),
+		(*byte)(unsafe.Pointer(&desc)), uint32(unsafe.Sizeof(desc)), &returned, nil)
+	if err != nil || desc.TrimEnabled == 0 {
+		return fmt.Errorf("%w: %s", ErrTrimUnsupported, drive)
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package system
 
 // DiskInfo contains information about a disk
 type DiskInfo struct {
-	Letter     string
+	Letter     string // Буква диска (Windows) или точка монтирования (Linux)
 	Type       string // HDD/SSD/Unknown
 	TotalSize  uint64
 	FreeSize   uint64
@@ -12,4 +12,26 @@ type DiskInfo struct {
 	Model      string
 	Serial     string
 	Interface  string
+	FSType     string // Тип файловой системы (ext4, xfs, ...), если известен
+	Device     string // Исходное устройство тома (/dev/sda1, ...), если известно
+
+	BackingDevices []string // Физические диски под томом (sda, nvme0n1, PhysicalDrive0, ...), если известны
+
+	Path string // Каталог затирания внутри тома Letter (пусто - том целиком), см. ResolveWipeTarget
+}
+
+// Target возвращает цель затирания для вывода: каталог или том
+func (d DiskInfo) Target() string {
+	if d.Path != "" {
+		return d.Path
+	}
+	return d.Letter
+}
+
+// WipeRoot возвращает каталог, в котором создаются файлы затирания
+func (d DiskInfo) WipeRoot() string {
+	if d.Path != "" {
+		return d.Path
+	}
+	return VolumeRoot(d.Letter)
 }
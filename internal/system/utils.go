@@ -1,9 +1,16 @@
 package system
 
-import "os"
+import (
+	"os"
+	"runtime"
+)
 
-// GetSystemDrive возвращает системный диск (C:, D:, и т.д.)
+// GetSystemDrive возвращает системный диск (C:, D:, и т.д.), на Unix - корень "/"
 func GetSystemDrive() string {
+	if runtime.GOOS != "windows" {
+		return "/"
+	}
+
 	// Получаем путь к системной директории
 	windir := os.Getenv("WINDIR")
 	if windir == "" {
@@ -4,8 +4,11 @@ import "strings"
 
 const (
 	// Windows error codes
-	ERROR_NOT_READY = 0x15
-	ERROR_DISK_FULL = 112
+	ERROR_NOT_READY           = 0x15
+	ERROR_HANDLE_DISK_FULL    = 39
+	ERROR_DISK_FULL           = 112
+	ERROR_DISK_QUOTA_EXCEEDED = 1295
+	ERROR_NOT_ENOUGH_QUOTA    = 1816
 )
 
 func IsWindowsError(err error, code uint32) bool {
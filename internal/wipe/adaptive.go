@@ -0,0 +1,247 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+// LatencyObserver получает замеры задержек записи, прошедшей через лимитер
+type LatencyObserver interface {
+	ObserveWrite(n int, d time.Duration)
+	ObserveSync(n uint64, d time.Duration)
+}
+
+// AdaptiveThrottle - регулятор скорости затирания. Раз в интервал он
+// сравнивает задержки write/fsync (на 1 МБ) и давление ввода-вывода в системе
+// с порогами и меняет лимит диска: при перегрузке скорость снижается
+// мультипликативно, при запасе - повышается аддитивно, всегда в пределах
+// [MinSpeedMBps, MaxSpeedMBps]. Каждое решение пишется в лог.
+type AdaptiveThrottle struct {
+	limiter  *RateLimiter
+	disk     string
+	logger   *logging.EnterpriseLogger
+	pressure func() (float64, bool)
+
+	minMBps     float64
+	maxMBps     float64
+	writeLimit  float64 // мс на 1 МБ
+	syncLimit   float64 // мс на 1 МБ
+	maxPressure float64
+	interval    time.Duration
+
+	mu         sync.Mutex
+	writeBytes uint64
+	writeTime  time.Duration
+	syncBytes  uint64
+	syncTime   time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Множители регулятора
+const (
+	adaptiveBackoff       = 0.7 // Снижение при превышении порога
+	adaptiveHardBackoff   = 0.5 // Снижение при двукратном превышении
+	adaptiveRaiseFraction = 0.1 // Шаг повышения - доля диапазона скоростей
+)
+
+// NewAdaptiveThrottle создает регулятор для лимитера диска. maxSpeedMBps -
+// верхняя граница по умолчанию, если в настройках она не задана.
+func NewAdaptiveThrottle(limiter *RateLimiter, disk string, settings config.AdaptiveThrottleConfig, maxSpeedMBps float64, logger *logging.EnterpriseLogger) *AdaptiveThrottle {
+	a := &AdaptiveThrottle{
+		limiter:     limiter,
+		disk:        disk,
+		logger:      logger,
+		pressure:    readIOPressure,
+		minMBps:     settings.MinSpeedMBps,
+		maxMBps:     settings.MaxSpeedMBps,
+		writeLimit:  settings.WriteLatencyMs,
+		syncLimit:   settings.SyncLatencyMs,
+		maxPressure: settings.MaxIOPressure,
+		interval:    2 * time.Second,
+	}
+
+	if a.maxMBps <= 0 {
+		a.maxMBps = maxSpeedMBps
+	}
+	if a.maxMBps <= 0 {
+		a.maxMBps = 1000 // Верхний предел конфигурации
+	}
+	if a.minMBps <= 0 || a.minMBps > a.maxMBps {
+		a.minMBps = a.maxMBps
+	}
+	if d, err := time.ParseDuration(settings.Interval); err == nil && d > 0 {
+		a.interval = d
+	}
+
+	return a
+}
+
+// Start устанавливает начальную скорость и запускает регулятор
+func (a *AdaptiveThrottle) Start(ctx context.Context) {
+	a.limiter.SetRate(a.maxMBps)
+	a.limiter.SetObserver(a)
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+
+	a.logger.Log("INFO", "Адаптивное ограничение скорости включено", "disk", a.disk,
+		"min_mbps", a.minMBps, "max_mbps", a.maxMBps, "write_ms_per_mb", a.writeLimit,
+		"sync_ms_per_mb", a.syncLimit, "max_io_pressure", a.maxPressure, "interval", a.interval)
+
+	go a.run(ctx)
+}
+
+// Stop останавливает регулятор
+func (a *AdaptiveThrottle) Stop() {
+	if a.stop == nil {
+		return
+	}
+	close(a.stop)
+	<-a.done
+	a.limiter.SetObserver(nil)
+}
+
+func (a *AdaptiveThrottle) run(ctx context.Context) {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			a.adjust()
+		}
+	}
+}
+
+// ObserveWrite учитывает задержку записи n байт
+func (a *AdaptiveThrottle) ObserveWrite(n int, d time.Duration) {
+	a.mu.Lock()
+	a.writeBytes += uint64(n)
+	a.writeTime += d
+	a.mu.Unlock()
+}
+
+// ObserveSync учитывает задержку fsync после записи n байт
+func (a *AdaptiveThrottle) ObserveSync(n uint64, d time.Duration) {
+	a.mu.Lock()
+	a.syncBytes += n
+	a.syncTime += d
+	a.mu.Unlock()
+}
+
+// sample забирает накопленные за интервал замеры (мс на 1 МБ; -1 - нет данных)
+func (a *AdaptiveThrottle) sample() (writeMs, syncMs float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	writeMs, syncMs = -1, -1
+	if a.writeBytes > 0 {
+		writeMs = msPerMB(a.writeTime, a.writeBytes)
+	}
+	if a.syncBytes > 0 {
+		syncMs = msPerMB(a.syncTime, a.syncBytes)
+	}
+	a.writeBytes, a.writeTime, a.syncBytes, a.syncTime = 0, 0, 0, 0
+	return writeMs, syncMs
+}
+
+func msPerMB(d time.Duration, n uint64) float64 {
+	return float64(d.Microseconds()) / 1000 / (float64(n) / (1024 * 1024))
+}
+
+// adjust принимает решение о скорости по замерам последнего интервала
+func (a *AdaptiveThrottle) adjust() {
+	writeMs, syncMs := a.sample()
+	pressure, hasPressure := a.pressure()
+	if writeMs < 0 && syncMs < 0 && !hasPressure {
+		return // Записи не было и нагрузку измерить нечем
+	}
+
+	// load - худшее отношение замера к порогу: >1 - перегрузка, <0.5 - есть запас
+	var load float64
+	var reasons []string
+	check := func(name string, value, limit float64) {
+		if value < 0 || limit <= 0 {
+			return
+		}
+		ratio := value / limit
+		if ratio > load {
+			load = ratio
+		}
+		if ratio > 1 {
+			reasons = append(reasons, fmt.Sprintf("%s %.1f > %.1f", name, value, limit))
+		}
+	}
+	check("write_ms_per_mb", writeMs, a.writeLimit)
+	check("sync_ms_per_mb", syncMs, a.syncLimit)
+	if hasPressure {
+		check("io_pressure", pressure, a.maxPressure)
+	} else {
+		pressure = -1
+	}
+
+	current := a.limiter.Rate()
+	target := current
+	decision := "hold"
+	switch {
+	case load > 2:
+		target = current * adaptiveHardBackoff
+		decision = "decrease"
+	case load > 1:
+		target = current * adaptiveBackoff
+		decision = "decrease"
+	case load < 0.5:
+		target = current + (a.maxMBps-a.minMBps)*adaptiveRaiseFraction
+		decision = "increase"
+	}
+	if target < a.minMBps {
+		target = a.minMBps
+	}
+	if target > a.maxMBps {
+		target = a.maxMBps
+	}
+	if target == current {
+		decision = "hold"
+	}
+
+	fields := []interface{}{"disk", a.disk, "decision", decision,
+		"from_mbps", current, "to_mbps", target, "load", load,
+		"write_ms_per_mb", writeMs, "sync_ms_per_mb", syncMs, "io_pressure", pressure,
+		"effective_mbps", a.limiter.Throughput()}
+	if len(reasons) > 0 {
+		fields = append(fields, "reason", strings.Join(reasons, "; "))
+	}
+
+	if decision == "hold" {
+		a.logger.Log("DEBUG", "Адаптивное ограничение: скорость без изменений", fields...)
+		return
+	}
+
+	a.limiter.SetRate(target)
+	a.logger.Log("INFO", "Адаптивное ограничение: скорость изменена", fields...)
+}
+
+// startAdaptiveThrottle включает регулятор для лимитера диска, если он
+// разрешен настройками; возвращает функцию остановки
+func startAdaptiveThrottle(ctx context.Context, settings config.AdaptiveThrottleConfig, maxSpeedMBps float64, limiter *RateLimiter, disk string, logger *logging.EnterpriseLogger) func() {
+	if !settings.Enabled {
+		return func() {}
+	}
+
+	a := NewAdaptiveThrottle(limiter, disk, settings, maxSpeedMBps, logger)
+	a.Start(ctx)
+	return a.Stop
+}
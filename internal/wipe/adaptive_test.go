@@ -0,0 +1,95 @@
+package wipe
+
+import (
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+func newTestLogger(t *testing.T) *logging.EnterpriseLogger {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Logging.Level = "ERROR"
+	logger, err := logging.NewEnterpriseLogger(cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logger
+}
+
+func TestAdaptiveThrottleAdjust(t *testing.T) {
+	settings := config.AdaptiveThrottleConfig{
+		MinSpeedMBps:   10,
+		MaxSpeedMBps:   100,
+		WriteLatencyMs: 10,
+		MaxIOPressure:  50,
+	}
+	logger := newTestLogger(t)
+
+	tests := []struct {
+		name     string
+		start    float64
+		writeMs  float64 // мс на 1 МБ, <0 - записи не было
+		pressure float64 // <0 - давление недоступно
+		want     float64
+	}{
+		{"нет замеров", 50, -1, -1, 50},
+		{"запас - повышение на шаг", 50, 3, -1, 59},
+		{"в пределах порога", 50, 6, -1, 50},
+		{"превышение порога", 50, 15, -1, 35},
+		{"двукратное превышение", 50, 25, -1, 25},
+		{"не ниже минимума", 12, 15, -1, 10},
+		{"не выше максимума", 95, 1, -1, 100},
+		{"давление ввода-вывода", 50, -1, 80, 35},
+		{"худший из замеров", 50, 3, 80, 35},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(tt.start, nil)
+			a := NewAdaptiveThrottle(limiter, "/mnt/data", settings, 0, logger)
+			a.pressure = func() (float64, bool) { return tt.pressure, tt.pressure >= 0 }
+
+			if tt.writeMs >= 0 {
+				a.ObserveWrite(1<<20, time.Duration(tt.writeMs*float64(time.Millisecond)))
+			}
+			a.adjust()
+
+			if got := limiter.Rate(); got != tt.want {
+				t.Errorf("скорость %v МБ/с, want %v", got, tt.want)
+			}
+			if w, s := a.sample(); w != -1 || s != -1 {
+				t.Error("замеры интервала не сброшены")
+			}
+		})
+	}
+}
+
+func TestNewAdaptiveThrottleLimits(t *testing.T) {
+	logger := newTestLogger(t)
+
+	tests := []struct {
+		name         string
+		settings     config.AdaptiveThrottleConfig
+		maxSpeedMBps float64
+		wantMin      float64
+		wantMax      float64
+		wantInterval time.Duration
+	}{
+		{"из настроек", config.AdaptiveThrottleConfig{MinSpeedMBps: 10, MaxSpeedMBps: 200, Interval: "500ms"}, 50, 10, 200, 500 * time.Millisecond},
+		{"максимум из max_speed_mbps", config.AdaptiveThrottleConfig{MinSpeedMBps: 10}, 50, 10, 50, 2 * time.Second},
+		{"без верхней границы", config.AdaptiveThrottleConfig{}, 0, 1000, 1000, 2 * time.Second},
+		{"минимум больше максимума", config.AdaptiveThrottleConfig{MinSpeedMBps: 300, MaxSpeedMBps: 200, Interval: "bad"}, 0, 200, 200, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAdaptiveThrottle(NewRateLimiter(0, nil), "/mnt/data", tt.settings, tt.maxSpeedMBps, logger)
+			if a.minMBps != tt.wantMin || a.maxMBps != tt.wantMax || a.interval != tt.wantInterval {
+				t.Errorf("min %v, max %v, interval %v; want %v, %v, %v", a.minMBps, a.maxMBps, a.interval, tt.wantMin, tt.wantMax, tt.wantInterval)
+			}
+		})
+	}
+}
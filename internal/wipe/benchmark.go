@@ -0,0 +1,294 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+const (
+	// benchmarkSampleSize - объем записи одного замера по умолчанию
+	benchmarkSampleSize = 256 * 1024 * 1024
+
+	// benchmarkMinSample - наименьший осмысленный объем замера
+	benchmarkMinSample = 32 * 1024 * 1024
+
+	// benchmarkTolerance - замеры, уступающие лучшему не больше этой доли,
+	// считаются равными по скорости и сравниваются по задержке fsync
+	benchmarkTolerance = 0.05
+)
+
+var (
+	// benchmarkChunkSizes - размеры блока записи по умолчанию
+	benchmarkChunkSizes = []int{1 << 20, 4 << 20, 16 << 20, 32 << 20, 64 << 20}
+
+	// benchmarkSyncIntervals - периоды fsync по умолчанию
+	benchmarkSyncIntervals = []uint64{16 << 20, 64 << 20, 256 << 20}
+)
+
+// BenchmarkOptions параметры замера скорости записи
+type BenchmarkOptions struct {
+	SampleSize    uint64   // Объем записи одного замера, 0 - 256 МБ
+	ChunkSizes    []int    // Размеры блока записи, nil - 1, 4, 16, 32, 64 МБ
+	SyncIntervals []uint64 // Периоды fsync по возрастанию, nil - 16, 64, 256 МБ
+
+	OnSample func(BenchmarkSample) // Вызывается после каждого замера (nil - без вызова)
+	Marker   *ArtifactMarker       // Подпись файла замера для recover (nil - без маркера)
+}
+
+// BenchmarkSample результат одного замера
+type BenchmarkSample struct {
+	ChunkSize      int
+	SyncInterval   uint64
+	DirectIO       bool
+	Bytes          uint64
+	Duration       time.Duration
+	ThroughputMBps float64
+	Syncs          int
+	SyncAvg        time.Duration // Средняя задержка fsync
+	SyncMax        time.Duration // Наибольшая задержка fsync
+}
+
+// BenchmarkResult итоги замера тома
+type BenchmarkResult struct {
+	Volume   string
+	DiskType string
+	Samples  []BenchmarkSample
+	Best     BenchmarkSample // Лучшее сочетание параметров
+	Profile  string          // Рекомендуемый встроенный профиль
+
+	DirectIOUnsupported bool // Файловая система не поддерживает прямой ввод-вывод
+}
+
+// Benchmark измеряет скорость последовательной записи и задержку fsync на
+// томе disk при разных размерах блока, периодах fsync, с кэшем ОС и в обход
+// него. Для каждого режима сначала подбирается размер блока при среднем из
+// периодов fsync, затем для лучшего блока - период fsync. Данные случайные, чтобы
+// сжатие и дедупликация накопителя не искажали результат. Файл замера
+// создается в корне тома с подписанным маркером, поэтому после аварийного
+// завершения его удаляет wipedisk recover. Ограничение скорости записи на
+// замер не действует.
+func Benchmark(ctx context.Context, disk system.DiskInfo, opts BenchmarkOptions, logger *logging.EnterpriseLogger) (*BenchmarkResult, error) {
+	root := system.VolumeRoot(disk.Letter)
+	if err := checkWritable(root); err != nil {
+		return nil, fmt.Errorf("диск недоступен для записи: %w", err)
+	}
+
+	size := opts.SampleSize
+	if size == 0 {
+		size = benchmarkSampleSize
+	}
+	// Замер не должен заполнять том: оставляем запас стратегии по умолчанию
+	reserve := (&StandardStrategy{}).GetMinFreeSpace()
+	if disk.FreeSize < reserve+benchmarkMinSample {
+		return nil, fmt.Errorf("недостаточно свободного места для замера: %.1f MB", float64(disk.FreeSize)/(1024*1024))
+	}
+	size = min(size, disk.FreeSize-reserve)
+
+	chunks := opts.ChunkSizes
+	if len(chunks) == 0 {
+		chunks = benchmarkChunkSizes
+	}
+	syncs := opts.SyncIntervals
+	if len(syncs) == 0 {
+		syncs = benchmarkSyncIntervals
+	}
+	chunkSync := syncs[len(syncs)/2]
+
+	b := &benchmarker{
+		path:   filepath.Join(root, "wipe_bench_001.tmp"),
+		size:   size,
+		opts:   opts,
+		result: &BenchmarkResult{Volume: disk.Letter, DiskType: disk.Type},
+		logger: logger,
+	}
+	defer os.Remove(b.path)
+
+	logger.Log("INFO", "Замер скорости записи", "disk", disk.Letter, "sample_size", size,
+		"chunk_sizes", len(chunks), "sync_intervals", len(syncs))
+
+	for _, direct := range []bool{false, true} {
+		var best *BenchmarkSample
+		for _, chunk := range chunks {
+			s, err := b.sample(ctx, chunk, chunkSync, direct)
+			if err != nil {
+				return b.result, err
+			}
+			if s == nil {
+				break // Прямой ввод-вывод недоступен
+			}
+			if best == nil || s.ThroughputMBps > best.ThroughputMBps {
+				best = s
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		chunk := best.ChunkSize
+		for _, interval := range syncs {
+			if interval == chunkSync {
+				continue // Уже измерено при подборе блока
+			}
+			if _, err := b.sample(ctx, chunk, interval, direct); err != nil {
+				return b.result, err
+			}
+		}
+	}
+
+	b.result.Best = bestSample(b.result.Samples)
+	b.result.Profile = recommendProfile(b.result.Best)
+	logger.Log("INFO", "Замер скорости записи завершен", "disk", disk.Letter,
+		"chunk", b.result.Best.ChunkSize, "sync_interval", b.result.Best.SyncInterval,
+		"direct_io", b.result.Best.DirectIO, "mbps", b.result.Best.ThroughputMBps, "profile", b.result.Profile)
+	return b.result, nil
+}
+
+// Tuned возвращает параметры профиля tuned по лучшему замеру
+func (r *BenchmarkResult) Tuned() config.TunedConfig {
+	return config.TunedConfig{
+		Volume:         r.Volume,
+		MeasuredAt:     time.Now().Format(time.RFC3339),
+		ChunkSize:      r.Best.ChunkSize,
+		SyncIntervalMB: int(r.Best.SyncInterval / (1024 * 1024)),
+		DirectIO:       r.Best.DirectIO,
+		ThroughputMBps: math.Round(r.Best.ThroughputMBps*10) / 10,
+		SyncLatencyMs:  math.Round(float64(r.Best.SyncAvg.Microseconds())/100) / 10,
+	}
+}
+
+// benchmarker хранит состояние замера одного тома
+type benchmarker struct {
+	path   string
+	size   uint64
+	opts   BenchmarkOptions
+	result *BenchmarkResult
+	logger *logging.EnterpriseLogger
+}
+
+// sample записывает файл замера блоками chunk с fsync раз в syncInterval.
+// nil без ошибки - прямой ввод-вывод запрошен, но недоступен.
+func (b *benchmarker) sample(ctx context.Context, chunk int, syncInterval uint64, direct bool) (*BenchmarkSample, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("операция отменена")
+	}
+
+	file, isDirect, err := openWipeFile(b.path, direct)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания файла замера: %w", err)
+	}
+	defer os.Remove(b.path)
+	defer file.Close()
+	if direct && !isDirect {
+		b.result.DirectIOUnsupported = true
+		return nil, nil
+	}
+	if isDirect {
+		chunk = alignUp(chunk) // Прямая запись возможна только выровненными блоками
+	}
+
+	w := NewLimitedWriter(ctx, file, nil)
+	w.SetDirectIO(isDirect)
+	buf := GetBuffer(chunk)
+	defer PutBuffer(buf)
+	if err := FillRandom(buf); err != nil {
+		return nil, fmt.Errorf("ошибка генерации данных: %w", err)
+	}
+	b.opts.Marker.mark(buf, b.path)
+
+	s := BenchmarkSample{ChunkSize: chunk, SyncInterval: syncInterval, DirectIO: isDirect}
+	var syncTotal time.Duration
+	sync := func() error {
+		started := time.Now()
+		if err := w.Sync(); err != nil {
+			return fmt.Errorf("ошибка синхронизации: %w", err)
+		}
+		d := time.Since(started)
+		s.Syncs++
+		syncTotal += d
+		s.SyncMax = max(s.SyncMax, d)
+		return nil
+	}
+
+	start := time.Now()
+	var lastSync uint64
+	for s.Bytes < b.size {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("операция отменена")
+		}
+		n, err := w.Write(buf[:min(uint64(chunk), b.size-s.Bytes)])
+		s.Bytes += uint64(n)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка записи: %w", err)
+		}
+		if s.Bytes-lastSync >= syncInterval {
+			if err := sync(); err != nil {
+				return nil, err
+			}
+			lastSync = s.Bytes
+		}
+	}
+	if s.Bytes > lastSync {
+		if err := sync(); err != nil {
+			return nil, err
+		}
+	}
+	s.Duration = time.Since(start)
+
+	if s.Duration > 0 {
+		s.ThroughputMBps = float64(s.Bytes) / (1024 * 1024) / s.Duration.Seconds()
+	}
+	if s.Syncs > 0 {
+		s.SyncAvg = syncTotal / time.Duration(s.Syncs)
+	}
+
+	b.logger.Log("DEBUG", "Замер записи", "disk", b.result.Volume, "chunk", chunk, "sync_interval", syncInterval,
+		"direct_io", isDirect, "mbps", s.ThroughputMBps, "sync_avg", s.SyncAvg, "sync_max", s.SyncMax)
+	b.result.Samples = append(b.result.Samples, s)
+	if b.opts.OnSample != nil {
+		b.opts.OnSample(s)
+	}
+	return &s, nil
+}
+
+// bestSample выбирает самый быстрый замер; из почти равных по скорости
+// предпочитается замер с меньшей средней задержкой fsync, которая меньше
+// мешает остальной системе
+func bestSample(samples []BenchmarkSample) BenchmarkSample {
+	var fastest float64
+	for _, s := range samples {
+		fastest = max(fastest, s.ThroughputMBps)
+	}
+
+	var best BenchmarkSample
+	for _, s := range samples {
+		if s.ThroughputMBps < fastest*(1-benchmarkTolerance) {
+			continue
+		}
+		if best.ChunkSize == 0 || s.SyncAvg < best.SyncAvg {
+			best = s
+		}
+	}
+	return best
+}
+
+// recommendProfile подбирает встроенный профиль по лучшему замеру: медленный
+// или надолго блокирующий fsync том затирается бережно, быстрый - без
+// ограничения скорости
+func recommendProfile(best BenchmarkSample) string {
+	switch {
+	case best.ThroughputMBps < 40 || best.SyncMax > 2*time.Second:
+		return "safe"
+	case best.ThroughputMBps < 150:
+		return "balanced"
+	default:
+		return "fast"
+	}
+}
@@ -0,0 +1,152 @@
+package wipe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/system"
+)
+
+func TestBenchmark(t *testing.T) {
+	const kb = 1024
+	tests := []struct {
+		name        string
+		opts        BenchmarkOptions
+		freeSize    uint64
+		cancelled   bool
+		wantSamples int // Замеров в режиме с кэшем ОС
+		wantErr     bool
+	}{
+		{"блоки и периоды fsync", BenchmarkOptions{SampleSize: 512 * kb, ChunkSizes: []int{64 * kb, 128 * kb},
+			SyncIntervals: []uint64{128 * kb, 256 * kb, 512 * kb}}, 1 << 40, false, 4, false},
+		{"один период fsync", BenchmarkOptions{SampleSize: 256 * kb, ChunkSizes: []int{64 * kb},
+			SyncIntervals: []uint64{128 * kb}}, 1 << 40, false, 1, false},
+		{"мало места", BenchmarkOptions{SampleSize: 256 * kb}, benchmarkMinSample, false, 0, true},
+		{"отмена", BenchmarkOptions{SampleSize: 256 * kb, ChunkSizes: []int{64 * kb}}, 1 << 40, true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			disk := system.DiskInfo{Letter: root, Type: "SSD", FreeSize: tt.freeSize}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+			var onSample int
+			tt.opts.OnSample = func(BenchmarkSample) { onSample++ }
+
+			result, err := Benchmark(ctx, disk, tt.opts, newTestLogger(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, want %v", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(root, "wipe_bench_001.tmp")); !os.IsNotExist(err) {
+				t.Errorf("файл замера не удален: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var cached int
+			for _, s := range result.Samples {
+				if !s.DirectIO {
+					cached++
+				}
+				if s.Bytes != tt.opts.SampleSize || s.ThroughputMBps <= 0 {
+					t.Errorf("замер %+v", s)
+				}
+			}
+			if cached != tt.wantSamples || onSample != len(result.Samples) {
+				t.Errorf("замеров с кэшем %d (OnSample %d из %d), want %d", cached, onSample, len(result.Samples), tt.wantSamples)
+			}
+			if result.Best.ChunkSize == 0 || result.Profile == "" {
+				t.Errorf("лучший замер %+v, профиль %q", result.Best, result.Profile)
+			}
+			tuned := result.Tuned()
+			if tuned.ChunkSize != result.Best.ChunkSize || tuned.SyncIntervalMB != int(result.Best.SyncInterval/(1024*1024)) {
+				t.Errorf("профиль tuned %+v не соответствует лучшему замеру %+v", tuned, result.Best)
+			}
+		})
+	}
+}
+
+func TestBestSample(t *testing.T) {
+	tests := []struct {
+		name      string
+		samples   []BenchmarkSample
+		wantChunk int
+	}{
+		{"самый быстрый", []BenchmarkSample{
+			{ChunkSize: 1, ThroughputMBps: 100}, {ChunkSize: 2, ThroughputMBps: 200}, {ChunkSize: 3, ThroughputMBps: 150},
+		}, 2},
+		{"почти равные: меньшая задержка fsync", []BenchmarkSample{
+			{ChunkSize: 1, ThroughputMBps: 200, SyncAvg: 50 * time.Millisecond},
+			{ChunkSize: 2, ThroughputMBps: 196, SyncAvg: 10 * time.Millisecond},
+		}, 2},
+		{"медленный с малой задержкой не выбирается", []BenchmarkSample{
+			{ChunkSize: 1, ThroughputMBps: 200, SyncAvg: 50 * time.Millisecond},
+			{ChunkSize: 2, ThroughputMBps: 150, SyncAvg: time.Millisecond},
+		}, 1},
+		{"нет замеров", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bestSample(tt.samples); got.ChunkSize != tt.wantChunk {
+				t.Errorf("выбран блок %d, want %d", got.ChunkSize, tt.wantChunk)
+			}
+		})
+	}
+}
+
+func TestRecommendProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		best BenchmarkSample
+		want string
+	}{
+		{"медленный том", BenchmarkSample{ThroughputMBps: 30}, "safe"},
+		{"долгий fsync", BenchmarkSample{ThroughputMBps: 500, SyncMax: 3 * time.Second}, "safe"},
+		{"средний", BenchmarkSample{ThroughputMBps: 100}, "balanced"},
+		{"быстрый", BenchmarkSample{ThroughputMBps: 400, SyncMax: time.Second}, "fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommendProfile(tt.best); got != tt.want {
+				t.Errorf("профиль %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileWriterTuned(t *testing.T) {
+	scheme, _ := LookupScheme(string(MethodZero))
+	strategy := &StandardStrategy{}
+	tests := []struct {
+		name         string
+		cfg          WipeConfig
+		wantChunk    int
+		wantInterval uint64
+	}{
+		{"из схемы и стратегии", WipeConfig{}, scheme.ChunkSize, strategy.GetSyncInterval()},
+		{"профиль tuned", WipeConfig{ChunkSize: 8 << 20, SyncInterval: 32 << 20}, 8 << 20, 32 << 20},
+		{"только блок", WipeConfig{ChunkSize: 2 << 20}, 2 << 20, strategy.GetSyncInterval()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, ok := newFileWriter(&tt.cfg, strategy, scheme, newTestLogger(t)).(*patternWriter)
+			if !ok {
+				t.Fatal("запись по умолчанию - не patternWriter")
+			}
+			if w.chunkSize != tt.wantChunk || w.syncInterval != tt.wantInterval {
+				t.Errorf("блок %d, fsync %d; want %d и %d", w.chunkSize, w.syncInterval, tt.wantChunk, tt.wantInterval)
+			}
+		})
+	}
+}
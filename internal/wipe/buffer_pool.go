@@ -1,11 +1,11 @@
 package wipe
 
 import (
-	"math/rand"
 	"sync"
 )
 
-// BufferPool управляет пулом буферов для оптимизации памяти
+// BufferPool управляет пулом буферов для оптимизации памяти. Адреса буферов
+// выровнены по DirectIOAlignment, поэтому их можно писать в обход кэша ОС.
 type BufferPool struct {
 	pools map[int]*sync.Pool
 	mu    sync.RWMutex
@@ -49,7 +49,7 @@ func (bp *BufferPool) getBuffer(size int) []byte {
 		if !exists {
 			pool = &sync.Pool{
 				New: func() interface{} {
-					return make([]byte, poolSize)
+					return alignedBlock(poolSize)
 				},
 			}
 			bp.pools[poolSize] = pool
@@ -110,20 +110,11 @@ func FillBufferPattern(buf []byte, pattern byte) error {
 	return nil
 }
 
-// FillRandom безопасно заполняет буфер случайными данными
+// FillRandom безопасно заполняет буфер случайными данными из общего потока AES-CTR
 func FillRandom(buf []byte) error {
 	if len(buf) == 0 {
 		return nil
 	}
 
-	// Защита от panic в rand.Int63n
-	_, err := rand.Read(buf)
-	if err != nil {
-		// Fallback к простому заполнению
-		for i := range buf {
-			buf[i] = byte(rand.Intn(256))
-		}
-	}
-
-	return nil
+	return sharedRandom.Read(buf)
 }
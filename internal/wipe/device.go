@@ -0,0 +1,308 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// DeviceWipeOptions параметры затирания блочного устройства или образа
+type DeviceWipeOptions struct {
+	Method    WipeMethod
+	Passes    int    // Число проходов (см. PatternScheme.TotalPasses), 0 - схема метода один раз
+	Offset    uint64 // Начало диапазона в байтах (кратно размеру сектора)
+	Length    uint64 // Длина диапазона в байтах, 0 - до конца устройства
+	ChunkSize int    // Размер блока записи, 0 - из схемы метода
+	DirectIO  bool   // Запись в обход кэша ОС
+	DryRun    bool
+
+	Limiter  *RateLimiter        // Ограничение скорости записи (nil - без ограничения)
+	Verifier *Verifier           // Проверка последнего прохода чтением (nil - только проходы с Verify)
+	Pause    *PauseController    // Пауза и возобновление (nil - без паузы)
+	Progress chan<- ProgressInfo // Прогресс затирания (nil - без прогресса)
+}
+
+// WipeDevice записывает проходы метода непосредственно на блочное
+// устройство или в файл образа диска в диапазоне [Offset, Offset+Length).
+// Устройство, которое смонтировано, подключено как swap или входит в
+// активный массив md/LVM, не затирается. Запись идет блоками, выровненными
+// по сектору, с fsync после каждого прохода; последний проход при
+// включенной проверке перечитывается.
+func WipeDevice(ctx context.Context, path string, opts DeviceWipeOptions, logger *logging.EnterpriseLogger) *WipeOperation {
+	op := &WipeOperation{
+		ID:        fmt.Sprintf("device_%d", time.Now().UnixNano()),
+		Disk:      path,
+		Method:    string(opts.Method),
+		Status:    "RUNNING",
+		StartTime: time.Now(),
+	}
+	progress := newProgressTracker(opts.Progress, path, op.StartTime)
+	defer func() {
+		now := time.Now()
+		op.EndTime = &now
+		op.ActiveIntervals = opts.Pause.ActiveIntervals(op.StartTime, now)
+		if elapsed := activeDuration(op.ActiveIntervals).Seconds(); elapsed > 0 && !opts.DryRun {
+			op.SpeedMBps = float64(op.BytesWiped) / (1024 * 1024) / elapsed
+		}
+		progress.finish(ctx, op)
+	}()
+
+	fail := func(err error) *WipeOperation {
+		op.Status = "FAILED"
+		op.Error = err.Error()
+		logger.Log("ERROR", "Затирание устройства не выполнено", "device", path, "error", op.Error)
+		return op
+	}
+
+	scheme, ok := LookupScheme(string(opts.Method))
+	if !ok {
+		return fail(fmt.Errorf("неизвестный метод затирания: %s", opts.Method))
+	}
+	op.Method = scheme.Name
+	opts.Verifier = passVerifier(opts.Verifier, scheme, nil, logger)
+
+	dev, err := system.InspectRawDevice(path)
+	if err != nil {
+		return fail(err)
+	}
+	start, length, err := deviceRange(dev, opts.Offset, opts.Length)
+	if err != nil {
+		return fail(err)
+	}
+
+	passes := scheme.TotalPasses(opts.Passes)
+	op.Passes = passes
+
+	// Размер блока кратен и сектору, и выравниванию прямого ввода-вывода
+	align := max(uint64(DirectIOAlignment), uint64(dev.SectorSize))
+	chunk := uint64(opts.ChunkSize)
+	if chunk == 0 {
+		chunk = uint64(scheme.ChunkSize)
+	}
+	chunk = max(chunk/align*align, align)
+	op.ChunkSize = int64(chunk)
+
+	logger.Log("INFO", "Запуск затирания устройства", "device", path, "block_device", dev.Block,
+		"size", dev.Size, "sector", dev.SectorSize, "offset", start, "length", length,
+		"method", scheme.Name, "passes", passes, "chunk", chunk, "dry_run", opts.DryRun)
+
+	if opts.DryRun {
+		op.Status = "COMPLETED"
+		op.BytesWiped = length * uint64(passes)
+		logger.Log("INFO", "DRY RUN: затирание устройства завершено", "device", path, "bytes", op.BytesWiped)
+		return op
+	}
+
+	w := &deviceWriter{
+		path:     path,
+		block:    dev.Block,
+		pause:    opts.Pause,
+		start:    start,
+		length:   length,
+		buf:      alignedBlock(int(chunk)),
+		limiter:  opts.Limiter,
+		progress: progress,
+	}
+	w.file, w.direct, err = openRawDevice(path, dev.Block, opts.DirectIO && start%uint64(DirectIOAlignment) == 0)
+	if err != nil {
+		return fail(fmt.Errorf("ошибка открытия устройства: %w", err))
+	}
+	defer w.close()
+	progress.plan(passes, 0, length)
+
+	// Хвост образа, не кратный выравниванию, пишется мимо прямого ввода-вывода
+	w.align = uint64(dev.SectorSize)
+	if !dev.Block {
+		w.align = DirectIOAlignment
+	}
+
+	for pass := 0; pass < passes; pass++ {
+		spec := scheme.Pass(pass)
+		record := op.beginPass(PhaseOverwrite, spec.String())
+
+		filler, err := spec.NewFiller()
+		if err != nil {
+			err = fmt.Errorf("ошибка генерации паттерна: %w", err)
+			record.finish(0, "FAILED", err)
+			return fail(err)
+		}
+
+		progress.beginPass(pass, 0)
+		written, err := w.writePass(ctx, filler)
+		op.BytesWiped += written
+		if err != nil {
+			if ctx.Err() != nil {
+				record.finish(written, "CANCELLED", nil)
+				op.Status = "CANCELLED"
+				op.Warning = fmt.Sprintf("Операция отменена на проходе %d из %d", pass+1, passes)
+				logger.Log("WARN", "Затирание устройства отменено", "device", path, "pass", pass+1, "bytes", op.BytesWiped)
+				return op
+			}
+			record.finish(written, "FAILED", err)
+			return fail(fmt.Errorf("проход %d: %w", pass+1, err))
+		}
+		record.finish(written, "COMPLETED", nil)
+		progress.endPass(written)
+
+		if pass == passes-1 || spec.Verify {
+			if err := opts.Verifier.VerifyRange(ctx, path, start, length, filler); err != nil {
+				op.Status = "CANCELLED"
+				op.Warning = "Операция отменена во время проверки чтением"
+				return op
+			}
+		}
+	}
+
+	op.Status = "COMPLETED"
+	op.recordVerification(opts.Verifier)
+	logger.Log("INFO", "Затирание устройства завершено", "device", path, "bytes", op.BytesWiped,
+		"passes", passes, "direct_io", w.direct)
+	return op
+}
+
+// deviceRange проверяет и вычисляет диапазон затирания. Границы диапазона
+// должны быть кратны сектору; у образа допускается невыровненный конец файла.
+func deviceRange(dev *system.RawDevice, offset, length uint64) (uint64, uint64, error) {
+	sector := uint64(dev.SectorSize)
+	if offset%sector != 0 {
+		return 0, 0, fmt.Errorf("смещение %d не кратно размеру сектора %d", offset, sector)
+	}
+	if offset >= dev.Size {
+		return 0, 0, fmt.Errorf("смещение %d за пределами устройства (%d байт)", offset, dev.Size)
+	}
+	if length == 0 {
+		length = dev.Size - offset
+	}
+	end := offset + length
+	if end > dev.Size || end < offset {
+		return 0, 0, fmt.Errorf("диапазон %d+%d выходит за пределы устройства (%d байт)", offset, length, dev.Size)
+	}
+	if length%sector != 0 && (dev.Block || end != dev.Size) {
+		return 0, 0, fmt.Errorf("длина %d не кратна размеру сектора %d", length, sector)
+	}
+	return offset, length, nil
+}
+
+// deviceWriter пишет проходы в диапазон устройства
+type deviceWriter struct {
+	path   string
+	file   *os.File
+	tail   *os.File // Обычный дескриптор для невыровненного хвоста образа
+	direct bool
+	align  uint64
+	block  bool
+	pause  *PauseController
+
+	start    uint64
+	length   uint64
+	buf      []byte
+	limiter  *RateLimiter
+	progress *progressTracker
+}
+
+// writePass записывает один проход и сбрасывает его на диск
+func (w *deviceWriter) writePass(ctx context.Context, filler PassFiller) (uint64, error) {
+	var written uint64
+	for pos := uint64(0); pos < w.length; {
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		default:
+		}
+		if w.pause.Paused() {
+			if err := w.hold(ctx); err != nil {
+				return written, err
+			}
+		}
+
+		n := min(uint64(len(w.buf)), w.length-pos)
+		file := w.file
+		if w.direct && n%w.align != 0 {
+			if aligned := n - n%w.align; aligned > 0 {
+				n = aligned
+			} else {
+				tail, err := w.tailFile()
+				if err != nil {
+					return written, err
+				}
+				file = tail
+			}
+		}
+
+		chunk := w.buf[:n]
+		if err := filler.FillAt(chunk, pos); err != nil {
+			return written, fmt.Errorf("ошибка генерации паттерна: %w", err)
+		}
+		if err := w.limiter.WaitN(ctx, len(chunk)); err != nil {
+			return written, err
+		}
+
+		started := time.Now()
+		m, err := file.WriteAt(chunk, int64(w.start+pos))
+		w.limiter.ObserveWrite(m, time.Since(started))
+		written += uint64(m)
+		pos += uint64(m)
+		w.progress.add(uint64(m))
+		if err != nil {
+			return written, fmt.Errorf("ошибка записи по смещению %d: %w", w.start+pos, err)
+		}
+	}
+
+	return written, w.sync()
+}
+
+// sync сбрасывает записанные данные на устройство
+func (w *deviceWriter) sync() error {
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("ошибка синхронизации: %w", err)
+	}
+	if w.tail != nil {
+		if err := w.tail.Sync(); err != nil {
+			return fmt.Errorf("ошибка синхронизации: %w", err)
+		}
+	}
+	return nil
+}
+
+// hold закрывает устройство на время паузы и открывает его снова после
+// возобновления; буфер записи сохраняется
+func (w *deviceWriter) hold(ctx context.Context) error {
+	if err := w.sync(); err != nil {
+		return err
+	}
+	w.close()
+	w.tail = nil
+	if err := holdPause(ctx, w.pause, w.progress); err != nil {
+		return err
+	}
+
+	file, direct, err := openRawDevice(w.path, w.block, w.direct)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия устройства после паузы: %w", err)
+	}
+	w.file, w.direct = file, direct
+	return nil
+}
+
+// tailFile открывает обычный дескриптор для хвоста образа
+func (w *deviceWriter) tailFile() (*os.File, error) {
+	if w.tail == nil {
+		file, err := os.OpenFile(w.path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+		w.tail = file
+	}
+	return w.tail, nil
+}
+
+func (w *deviceWriter) close() {
+	if w.tail != nil {
+		w.tail.Close()
+	}
+	w.file.Close()
+}
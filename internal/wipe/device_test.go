@@ -0,0 +1,87 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"wipedisk_enterprise/internal/config"
+)
+
+func TestWipeDevice(t *testing.T) {
+	const imageSize = 16*DirectIOAlignment + 100 // Невыровненный конец образа
+
+	tests := []struct {
+		name       string
+		opts       DeviceWipeOptions
+		wantStatus string
+		wantPasses int
+		wantRange  [2]uint64 // Затертый диапазон [начало, конец)
+		wantErr    string
+	}{
+		{"образ целиком", DeviceWipeOptions{Method: MethodZero}, "COMPLETED", 1, [2]uint64{0, imageSize}, ""},
+		{"прямой ввод-вывод", DeviceWipeOptions{Method: MethodZero, DirectIO: true, ChunkSize: 3 * DirectIOAlignment},
+			"COMPLETED", 1, [2]uint64{0, imageSize}, ""},
+		{"диапазон", DeviceWipeOptions{Method: MethodZero, Offset: 1024, Length: 4096}, "COMPLETED", 1, [2]uint64{1024, 5120}, ""},
+		{"схема целиком", DeviceWipeOptions{Method: MethodDOD5220, Passes: 1}, "COMPLETED", 3, [2]uint64{0, imageSize}, ""},
+		{"проходы сверх схемы", DeviceWipeOptions{Method: MethodZero, Passes: 2}, "COMPLETED", 2, [2]uint64{0, imageSize}, ""},
+		{"тестовый режим", DeviceWipeOptions{Method: MethodZero, DryRun: true}, "COMPLETED", 1, [2]uint64{}, ""},
+		{"невыровненное смещение", DeviceWipeOptions{Method: MethodZero, Offset: 100}, "FAILED", 0, [2]uint64{}, "не кратно размеру сектора"},
+		{"за пределами образа", DeviceWipeOptions{Method: MethodZero, Offset: 512, Length: imageSize}, "FAILED", 0, [2]uint64{}, "выходит за пределы"},
+		{"невыровненная длина", DeviceWipeOptions{Method: MethodZero, Length: 1000}, "FAILED", 0, [2]uint64{}, "не кратна размеру сектора"},
+		{"неизвестный метод", DeviceWipeOptions{Method: "shred"}, "FAILED", 0, [2]uint64{}, "неизвестный метод"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "disk.img")
+			orig := bytes.Repeat([]byte{0xA5}, imageSize)
+			if err := os.WriteFile(path, orig, 0644); err != nil {
+				t.Fatal(err)
+			}
+			logger := newTestLogger(t)
+			tt.opts.Verifier = NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 1}, nil, logger)
+
+			op := WipeDevice(context.Background(), path, tt.opts, logger)
+			if op.Status != tt.wantStatus || op.Passes != tt.wantPasses || !strings.Contains(op.Error, tt.wantErr) {
+				t.Fatalf("статус %s, проходов %d, ошибка %q; want %s, %d, %q",
+					op.Status, op.Passes, op.Error, tt.wantStatus, tt.wantPasses, tt.wantErr)
+			}
+			if v := op.Verification; v != nil && (v.MismatchBlocks > 0 || v.ReadErrors > 0) {
+				t.Errorf("проверка чтением: %+v", v)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from, to := tt.wantRange[0], tt.wantRange[1]
+			if len(got) != imageSize || !bytes.Equal(got[:from], orig[:from]) || !bytes.Equal(got[to:], orig[to:]) {
+				t.Fatal("изменены данные вне диапазона затирания")
+			}
+			if tt.opts.Method == MethodZero && !bytes.Equal(got[from:to], make([]byte, to-from)) {
+				t.Error("диапазон не затерт нулями")
+			}
+			if want := (to - from) * uint64(tt.wantPasses); !tt.opts.DryRun && op.BytesWiped != want {
+				t.Errorf("записано %d байт, want %d", op.BytesWiped, want)
+			}
+		})
+	}
+}
+
+func TestWipeDeviceCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	op := WipeDevice(ctx, path, DeviceWipeOptions{Method: MethodDOD5220}, newTestLogger(t))
+	if op.Status != "CANCELLED" || len(op.PassRecords) != 1 || op.PassRecords[0].Status != "CANCELLED" {
+		t.Errorf("статус %s, записи проходов %+v", op.Status, op.PassRecords)
+	}
+}
@@ -0,0 +1,133 @@
+package wipe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unsafe"
+)
+
+// DirectIOAlignment - выравнивание адресов буферов, смещений и длин записи
+// при прямом вводе-выводе. 4 КБ покрывает диски с секторами 512 и 4096 байт.
+const DirectIOAlignment = 4096
+
+// errDirectIOTail - запись после невыровненного хвоста файла
+var errDirectIOTail = errors.New("прямая запись после невыровненного конца файла")
+
+// alignedBlock выделяет буфер размера size, адрес которого выровнен по DirectIOAlignment
+func alignedBlock(size int) []byte {
+	raw := make([]byte, size+DirectIOAlignment)
+	off := 0
+	if rem := int(uintptr(unsafe.Pointer(&raw[0])) & (DirectIOAlignment - 1)); rem != 0 {
+		off = DirectIOAlignment - rem
+	}
+	return raw[off : off+size : off+size]
+}
+
+// isAlignedBlock проверяет, можно ли записать буфер напрямую без копирования
+func isAlignedBlock(b []byte) bool {
+	return len(b) > 0 && len(b)%DirectIOAlignment == 0 &&
+		uintptr(unsafe.Pointer(&b[0]))&(DirectIOAlignment-1) == 0
+}
+
+// alignUp округляет n вверх до границы DirectIOAlignment
+func alignUp(n int) int {
+	return (n + DirectIOAlignment - 1) &^ (DirectIOAlignment - 1)
+}
+
+// openWipeFile создает файл затирания. При direct файл открывается в обход
+// кэша ОС (O_DIRECT / FILE_FLAG_NO_BUFFERING); если файловая система этого не
+// поддерживает, файл открывается обычным образом. Второе значение сообщает,
+// включен ли прямой ввод-вывод.
+func openWipeFile(filename string, direct bool) (*os.File, bool, error) {
+	if direct {
+		file, err := createDirect(filename)
+		if err == nil {
+			return file, true, nil
+		}
+		if !errors.Is(err, errDirectIOUnsupported) {
+			return nil, false, err
+		}
+	}
+
+	file, err := os.Create(filename)
+	return file, false, err
+}
+
+// reopenWipeFile снова открывает на запись файл затирания, закрытый на время
+// паузы, и ставит позицию записи на offset. Второе значение сообщает,
+// включен ли прямой ввод-вывод.
+func reopenWipeFile(filename string, direct bool, offset int64) (*os.File, bool, error) {
+	var file *os.File
+	if direct {
+		f, err := openDirectWrite(filename)
+		if err != nil && !errors.Is(err, errDirectIOUnsupported) {
+			return nil, false, err
+		}
+		file = f
+	}
+	isDirect := file != nil
+	if file == nil {
+		f, err := os.OpenFile(filename, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		file = f
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, false, err
+	}
+	return file, isDirect, nil
+}
+
+// openVerifyFile открывает файл для проверки чтением в обход кэша ОС,
+// а если это невозможно - обычным образом
+func openVerifyFile(filename string) (*os.File, error) {
+	file, err := openDirectRead(filename)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, errDirectIOUnsupported) {
+		return nil, err
+	}
+	return os.Open(filename)
+}
+
+// errDirectIOUnsupported - прямой ввод-вывод недоступен для файла или платформы
+var errDirectIOUnsupported = errors.New("прямой ввод-вывод не поддерживается")
+
+// writeDirect записывает данные в файл, открытый для прямого ввода-вывода.
+// Выровненные буферы пишутся как есть; невыровненный буфер копируется в
+// выровненный блок, а неполный последний сектор дополняется нулями, после
+// чего файл обрезается до фактической длины. Такой хвост может быть только в
+// конце файла. offset - текущая логическая длина файла.
+func writeDirect(file *os.File, data []byte, offset int64) (int, bool, error) {
+	if isAlignedBlock(data) {
+		n, err := file.Write(data)
+		return n, false, err
+	}
+
+	block := GetBuffer(alignUp(len(data)))
+	defer PutBuffer(block)
+	copy(block, data)
+	clear(block[len(data):])
+
+	n, err := file.Write(block)
+	if n > len(data) {
+		n = len(data)
+	}
+	if err != nil {
+		return n, false, err
+	}
+
+	tail := len(data)%DirectIOAlignment != 0
+	if tail {
+		if err := file.Truncate(offset + int64(len(data))); err != nil {
+			return n, true, fmt.Errorf("ошибка обрезки файла после прямой записи: %w", err)
+		}
+	}
+	return n, tail, nil
+}
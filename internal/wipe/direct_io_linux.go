@@ -0,0 +1,68 @@
+//go:build linux
+
+package wipe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// createDirect создает файл с O_DIRECT: запись идет мимо page cache
+func createDirect(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|syscall.O_DIRECT, 0666)
+	if errors.Is(err, syscall.EINVAL) {
+		// tmpfs и часть FUSE-систем не принимают O_DIRECT
+		return nil, errDirectIOUnsupported
+	}
+	return file, err
+}
+
+// openDirectWrite открывает существующий файл на запись с O_DIRECT
+func openDirectWrite(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|syscall.O_DIRECT, 0)
+	if errors.Is(err, syscall.EINVAL) {
+		return nil, errDirectIOUnsupported
+	}
+	return file, err
+}
+
+// openDirectRead открывает файл на чтение с O_DIRECT
+func openDirectRead(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_RDONLY|syscall.O_DIRECT, 0)
+	if errors.Is(err, syscall.EINVAL) {
+		return nil, errDirectIOUnsupported
+	}
+	return file, err
+}
+
+// openRawDevice открывает устройство или образ на запись, при direct - с
+// O_DIRECT. Блочное устройство открывается с O_EXCL: ядро откажет, если оно
+// смонтировано или занято md/device-mapper. Второе значение сообщает,
+// включен ли прямой ввод-вывод.
+func openRawDevice(path string, block, direct bool) (*os.File, bool, error) {
+	flags := os.O_WRONLY
+	if block {
+		flags |= syscall.O_EXCL
+	}
+	if direct {
+		file, err := os.OpenFile(path, flags|syscall.O_DIRECT, 0)
+		if err == nil {
+			return file, true, nil
+		}
+		if !errors.Is(err, syscall.EINVAL) {
+			return nil, false, rawDeviceError(err)
+		}
+	}
+	file, err := os.OpenFile(path, flags, 0)
+	return file, false, rawDeviceError(err)
+}
+
+// rawDeviceError поясняет EBUSY при монопольном открытии устройства
+func rawDeviceError(err error) error {
+	if errors.Is(err, syscall.EBUSY) {
+		return fmt.Errorf("устройство занято (смонтировано или входит в md/LVM): %w", err)
+	}
+	return err
+}
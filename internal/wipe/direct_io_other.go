@@ -0,0 +1,26 @@
+//go:build !linux && !windows
+
+package wipe
+
+import "os"
+
+// createDirect - прямой ввод-вывод поддерживается только в Linux и Windows
+func createDirect(filename string) (*os.File, error) {
+	return nil, errDirectIOUnsupported
+}
+
+// openDirectWrite - прямой ввод-вывод поддерживается только в Linux и Windows
+func openDirectWrite(filename string) (*os.File, error) {
+	return nil, errDirectIOUnsupported
+}
+
+// openDirectRead - прямой ввод-вывод поддерживается только в Linux и Windows
+func openDirectRead(filename string) (*os.File, error) {
+	return nil, errDirectIOUnsupported
+}
+
+// openRawDevice - прямой ввод-вывод поддерживается только в Linux и Windows
+func openRawDevice(path string, block, direct bool) (*os.File, bool, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	return file, false, err
+}
@@ -0,0 +1,89 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAlignUp(t *testing.T) {
+	tests := []struct {
+		n, want int
+	}{
+		{0, 0},
+		{1, DirectIOAlignment},
+		{DirectIOAlignment, DirectIOAlignment},
+		{DirectIOAlignment + 1, 2 * DirectIOAlignment},
+		{4*1024*1024 + 512, 4*1024*1024 + DirectIOAlignment},
+	}
+
+	for _, tt := range tests {
+		if got := alignUp(tt.n); got != tt.want {
+			t.Errorf("alignUp(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAlignedBlock(t *testing.T) {
+	for _, size := range []int{DirectIOAlignment, 3 * DirectIOAlignment, 1024 * 1024} {
+		b := alignedBlock(size)
+		if len(b) != size || !isAlignedBlock(b) {
+			t.Errorf("alignedBlock(%d): длина %d, выровнен %v", size, len(b), isAlignedBlock(b))
+		}
+	}
+	if isAlignedBlock(alignedBlock(2 * DirectIOAlignment)[1:]) {
+		t.Error("смещенный буфер признан выровненным")
+	}
+}
+
+func TestThrottledWriterDirect(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunks  []int
+		wantErr error
+		wantLen int64
+	}{
+		{"выровненные блоки", []int{DirectIOAlignment, 2 * DirectIOAlignment}, nil, 3 * DirectIOAlignment},
+		{"невыровненный хвост", []int{DirectIOAlignment, 100}, nil, DirectIOAlignment + 100},
+		{"один невыровненный блок", []int{5000}, nil, 5000},
+		{"запись после хвоста", []int{100, DirectIOAlignment}, errDirectIOTail, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "wipe_001.tmp")
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := NewLimitedWriter(context.Background(), file, nil)
+			w.SetDirectIO(true)
+
+			var want []byte
+			for i, size := range tt.chunks {
+				chunk := bytes.Repeat([]byte{byte(i + 1)}, size)
+				if _, err = w.Write(chunk); err != nil {
+					break
+				}
+				want = append(want, chunk...)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write: ошибка %v, want %v", err, tt.wantErr)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int64(len(got)) != tt.wantLen || !bytes.Equal(got, want) {
+				t.Errorf("файл %d байт, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
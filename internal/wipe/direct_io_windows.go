@@ -0,0 +1,69 @@
+//go:build windows
+
+package wipe
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// createDirect создает файл с FILE_FLAG_NO_BUFFERING и FILE_FLAG_WRITE_THROUGH:
+// запись идет мимо системного кэша
+func createDirect(filename string) (*os.File, error) {
+	return openUnbuffered(filename, windows.GENERIC_WRITE, windows.CREATE_ALWAYS,
+		windows.FILE_FLAG_NO_BUFFERING|windows.FILE_FLAG_WRITE_THROUGH)
+}
+
+// openDirectWrite открывает существующий файл на запись с FILE_FLAG_NO_BUFFERING
+func openDirectWrite(filename string) (*os.File, error) {
+	return openUnbuffered(filename, windows.GENERIC_WRITE, windows.OPEN_EXISTING,
+		windows.FILE_FLAG_NO_BUFFERING|windows.FILE_FLAG_WRITE_THROUGH)
+}
+
+// openDirectRead открывает файл на чтение с FILE_FLAG_NO_BUFFERING
+func openDirectRead(filename string) (*os.File, error) {
+	return openUnbuffered(filename, windows.GENERIC_READ, windows.OPEN_EXISTING, windows.FILE_FLAG_NO_BUFFERING)
+}
+
+// openRawDevice открывает физический диск или образ на запись, при direct -
+// с FILE_FLAG_NO_BUFFERING. Второе значение сообщает, включен ли прямой
+// ввод-вывод.
+func openRawDevice(path string, block, direct bool) (*os.File, bool, error) {
+	if direct {
+		file, err := openUnbuffered(path, windows.GENERIC_WRITE, windows.OPEN_EXISTING,
+			windows.FILE_FLAG_NO_BUFFERING|windows.FILE_FLAG_WRITE_THROUGH)
+		if err == nil {
+			return file, true, nil
+		}
+		if !errors.Is(err, errDirectIOUnsupported) {
+			return nil, false, err
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	return file, false, err
+}
+
+func openUnbuffered(filename string, access, disposition, flags uint32) (*os.File, error) {
+	name, err := windows.UTF16PtrFromString(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := windows.CreateFile(name,
+		access,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		disposition,
+		windows.FILE_ATTRIBUTE_NORMAL|flags,
+		0)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_INVALID_PARAMETER) {
+			return nil, errDirectIOUnsupported
+		}
+		return nil, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	return os.NewFile(uintptr(handle), filename), nil
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package wipe
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// punchHole освобождает блоки файла в диапазоне [0, size) без изменения
+// длины; при монтировании с discard ядро сразу передает их устройству
+func punchHole(file *os.File, size int64) error {
+	if size == 0 {
+		return nil
+	}
+	err := unix.Fallocate(int(file.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, 0, size)
+	if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOSYS) {
+		return errDiscardUnsupported
+	}
+	return err
+}
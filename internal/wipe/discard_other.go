@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package wipe
+
+import "os"
+
+// punchHole - освобождение блоков файла поддерживается только в Linux и Windows
+func punchHole(file *os.File, size int64) error {
+	return errDiscardUnsupported
+}
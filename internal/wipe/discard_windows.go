@@ -0,0 +1,44 @@
+//go:build windows
+
+package wipe
+
+import (
+	"errors"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileZeroDataInformation - FILE_ZERO_DATA_INFORMATION
+type fileZeroDataInformation struct {
+	FileOffset      int64
+	BeyondFinalZero int64
+}
+
+// punchHole помечает файл разреженным и освобождает диапазон [0, size);
+// NTFS передает освобожденные кластеры устройству как TRIM
+func punchHole(file *os.File, size int64) error {
+	if size == 0 {
+		return nil
+	}
+	handle := windows.Handle(file.Fd())
+
+	var returned uint32
+	if err := windows.DeviceIoControl(handle, windows.FSCTL_SET_SPARSE, nil, 0, nil, 0, &returned, nil); err != nil {
+		return discardError(err)
+	}
+
+	zero := fileZeroDataInformation{FileOffset: 0, BeyondFinalZero: size}
+	err := windows.DeviceIoControl(handle, windows.FSCTL_SET_ZERO_DATA,
+		(*byte)(unsafe.Pointer(&zero)), uint32(unsafe.Sizeof(zero)), nil, 0, &returned, nil)
+	return discardError(err)
+}
+
+// discardError переводит ошибки файловых систем без разреженных файлов (FAT, exFAT)
+func discardError(err error) error {
+	if errors.Is(err, windows.ERROR_INVALID_FUNCTION) || errors.Is(err, windows.ERROR_NOT_SUPPORTED) {
+		return errDiscardUnsupported
+	}
+	return err
+}
@@ -4,50 +4,141 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	"wipedisk_enterprise/internal/config"
 	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
 )
 
+// resolveWipeTarget разрешает цель затирания графического интерфейса
+// (подменяется в тестах)
+var resolveWipeTarget = system.ResolveWipeTarget
+
+// WipeEngine - точка входа графического интерфейса в конвейер затирания.
+// Настройки (SetConfig и др.) читаются один раз при запуске затирания, их
+// изменение не влияет на уже начатые затирания.
 type WipeEngine struct {
-	wiper  *PersistentFileWiper
-	logger *logging.EnterpriseLogger
+	mu       sync.Mutex
+	config   *config.Config
+	progress chan<- ProgressInfo
+	pause    *PauseController
+	logger   *logging.EnterpriseLogger
 }
 
 func NewWipeEngine(logger *logging.EnterpriseLogger) *WipeEngine {
 	return &WipeEngine{
-		wiper:  NewPersistentFileWiper(&PersistentFileConfig{Logger: logger}),
+		config: config.Default(),
 		logger: logger,
 	}
 }
 
+// ForRun возвращает отдельный движок с настройками одного запуска. Запуски
+// с разными каналами прогресса не мешают друг другу: закрытие канала
+// после своего запуска не затрагивает затирания других запусков.
+func (we *WipeEngine) ForRun(cfg *config.Config, progress chan<- ProgressInfo, pause *PauseController) *WipeEngine {
+	return &WipeEngine{
+		config:   cfg,
+		progress: progress,
+		pause:    pause,
+		logger:   we.logger,
+	}
+}
+
+// WipeVolume затирает свободное место тома или каталога с настройками из SetConfig
+func (we *WipeEngine) WipeVolume(ctx context.Context, drivePath string) *WipeOperation {
+	return we.run(ctx, drivePath, nil)
+}
+
+// WipeDrive затирает свободное место тома или каталога drivePath (буква
+// диска, точка монтирования, смонтированная папка, каталог внутри тома);
+// pattern задает собственный паттерн единственного прохода (nil - метод
+// из конфигурации)
 func (we *WipeEngine) WipeDrive(ctx context.Context, drivePath string, pattern []byte) (*WipeResult, error) {
-	// 1. Стерилизация пути
+	op := we.run(ctx, drivePath, pattern)
+	result := resultFromOperation(op)
+	if err := op.err(); err != nil {
+		return result, fmt.Errorf("ошибка при затирании %s: %v", op.Target(), err)
+	}
+	return result, nil
+}
+
+func (we *WipeEngine) run(ctx context.Context, drivePath string, pattern []byte) *WipeOperation {
+	// 1. Разрешение цели: "d", "D:", "D:\\" - том целиком, каталог - место внутри его тома
 	drivePath = strings.TrimSpace(drivePath)
-	drivePath = strings.TrimRight(drivePath, ".")
-	drivePath = strings.ToUpper(drivePath)
 
-	if !strings.HasSuffix(drivePath, "\\") {
-		if !strings.HasSuffix(drivePath, ":") {
-			drivePath += ":"
+	we.logger.Log("INFO", "Запуск затирания", "drive", drivePath)
+
+	disk, err := resolveWipeTarget(drivePath)
+	if err != nil {
+		now := time.Now()
+		return &WipeOperation{
+			ID:        fmt.Sprintf("wipe_%d", now.UnixNano()),
+			Disk:      drivePath,
+			Status:    "FAILED",
+			StartTime: now,
+			EndTime:   &now,
+			Error:     fmt.Sprintf("ошибка получения информации о диске: %v", err),
 		}
-		drivePath += "\\"
 	}
+	disk.Letter = system.VolumeRoot(disk.Letter)
 
-	we.logger.Log("INFO", "Запуск затирания", "drive", drivePath)
+	// 2. Запуск конвейера с настройками на момент запуска
+	we.mu.Lock()
+	cfg, progress, pause := we.config, we.progress, we.pause
+	we.mu.Unlock()
 
+	wipeConfig := NewWipeConfig(cfg, disk, ModeStandard, we.logger)
+	wipeConfig.Progress = progress
+	wipeConfig.Pause = pause
 	if pattern != nil {
-		we.wiper.config.Pattern = pattern
+		wipeConfig.scheme = &PatternScheme{Name: "pattern", Passes: []PassSpec{RepeatPass(pattern...)}, ChunkSize: defaultSchemeChunkSize}
+		wipeConfig.Passes = 1
 	}
+	return RunFreeSpaceWipe(ctx, disk, GetStrategy(ModeStandard), wipeConfig, we.logger)
+}
 
-	// 2. Запуск процесса
-	result, err := we.wiper.Wipe(ctx, drivePath)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка при затирании %s: %v", drivePath, err)
+// resultFromOperation переводит итоги операции в результат для интерфейса
+func resultFromOperation(op *WipeOperation) *WipeResult {
+	result := &WipeResult{
+		Success:      op.Status == "COMPLETED",
+		BytesWritten: op.BytesWiped,
+		SpeedMBps:    op.SpeedMBps,
+		Error:        op.err(),
+		Cancelled:    op.Status == "CANCELLED" || op.Status == "PARTIAL",
+		Verification: op.Verification,
+		Trim:         op.Trim,
+	}
+	if op.EndTime != nil {
+		result.Duration = op.EndTime.Sub(op.StartTime)
 	}
+	for _, r := range op.PassRecords {
+		if r.Phase == PhaseOverwrite {
+			result.FilesCreated += r.Files
+		}
+	}
+	return result
+}
 
-	return result, nil
+// SetConfig задает конфигурацию затирания: метод, проходы, скорость,
+// прямой ввод-вывод, проверку чтением и TRIM
+func (we *WipeEngine) SetConfig(cfg *config.Config) {
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	we.config = cfg
 }
 
+// SetProgressChannel задает канал прогресса для последующих затираний
 func (we *WipeEngine) SetProgressChannel(progress chan<- ProgressInfo) {
-	we.wiper.config.Progress = progress
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	we.progress = progress
+}
+
+// SetPauseController задает контроллер паузы для последующих затираний
+func (we *WipeEngine) SetPauseController(pause *PauseController) {
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	we.pause = pause
 }
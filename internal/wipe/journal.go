@@ -0,0 +1,356 @@
+package wipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// Journal - журнал контрольных точек одного запуска затирания.
+// Хранится в <state_dir>/journal/<run-id>.json и перезаписывается атомарно
+// после каждого файла и прохода, поэтому прерванный запуск (max_duration,
+// Ctrl-C, перезагрузка) можно продолжить командой wipe --resume <run-id>.
+type Journal struct {
+	RunID   string              `json:"run_id"`
+	Mode    string              `json:"mode"`
+	Profile string              `json:"profile,omitempty"`
+	Created time.Time           `json:"created"`
+	Updated time.Time           `json:"updated"`
+	Volumes []*VolumeCheckpoint `json:"volumes"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// VolumeCheckpoint контрольная точка затирания одного тома или каталога
+type VolumeCheckpoint struct {
+	Disk        string       `json:"disk"` // Цель затирания: том или каталог (DiskInfo.Target)
+	Method      string       `json:"method,omitempty"`
+	Passes      int          `json:"passes"`       // Всего проходов
+	Pass        int          `json:"pass"`         // Индекс текущего прохода, с 0
+	FileIndex   int          `json:"file_index"`   // Следующий файл текущего прохода
+	PassBytes   uint64       `json:"pass_bytes"`   // Байт текущего прохода в завершенных файлах
+	Files       []string     `json:"files"`        // Завершенные файлы прохода, оставленные на диске
+	CurrentFile string       `json:"current_file"` // Файл, запись которого не завершена
+	Status      string       `json:"status"`
+	PassRecords []PassRecord `json:"pass_records"`
+	Segments    []RunSegment `json:"segments"`
+
+	journal *Journal
+}
+
+// RunSegment - один запуск процесса, выполнявший часть работы над томом
+type RunSegment struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	FromPass     int       `json:"from_pass"` // Номер прохода (с 1), с которого начат запуск
+	BytesWritten uint64    `json:"bytes_written"`
+	Status       string    `json:"status"`
+}
+
+// passCursor позиция внутри прохода, с которой продолжается запись
+type passCursor struct {
+	FileIndex int
+	Bytes     uint64
+}
+
+// journalDir возвращает каталог журналов; пустой stateDir означает значение по умолчанию
+func journalDir(stateDir string) string {
+	if stateDir == "" {
+		stateDir = config.DefaultStateDir
+	}
+	return filepath.Join(stateDir, "journal")
+}
+
+// NewJournal создает журнал нового запуска
+func NewJournal(stateDir, runID, mode, profile string) (*Journal, error) {
+	dir := journalDir(stateDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога журналов %s: %w", dir, err)
+	}
+
+	j := &Journal{
+		RunID:   runID,
+		Mode:    mode,
+		Profile: profile,
+		Created: time.Now(),
+		path:    filepath.Join(dir, runID+".json"),
+	}
+	if err := j.Save(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LoadJournal загружает журнал прерванного запуска
+func LoadJournal(stateDir, runID string) (*Journal, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) || strings.Contains(runID, "..") {
+		return nil, fmt.Errorf("некорректный идентификатор запуска: %q", runID)
+	}
+
+	path := filepath.Join(journalDir(stateDir), runID+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("журнал запуска %s не найден в %s", runID, journalDir(stateDir))
+		}
+		return nil, fmt.Errorf("ошибка чтения журнала %s: %w", path, err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("поврежденный журнал %s: %w", path, err)
+	}
+	if j.RunID != runID {
+		return nil, fmt.Errorf("журнал %s содержит запуск %s", path, j.RunID)
+	}
+
+	j.path = path
+	for _, v := range j.Volumes {
+		v.journal = &j
+	}
+	return &j, nil
+}
+
+// Volume возвращает контрольную точку тома или каталога disk, создавая ее
+// при необходимости
+func (j *Journal) Volume(disk string) *VolumeCheckpoint {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, v := range j.Volumes {
+		if system.SameTarget(v.Disk, disk) {
+			return v
+		}
+	}
+
+	v := &VolumeCheckpoint{Disk: disk, Status: "PENDING", journal: j}
+	j.Volumes = append(j.Volumes, v)
+	return v
+}
+
+// Pending возвращает тома, работа над которыми не завершена
+func (j *Journal) Pending() []*VolumeCheckpoint {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var pending []*VolumeCheckpoint
+	for _, v := range j.Volumes {
+		if v.Status != "COMPLETED" {
+			pending = append(pending, v)
+		}
+	}
+	return pending
+}
+
+// Save атомарно записывает журнал на диск
+func (j *Journal) Save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.saveLocked()
+}
+
+func (j *Journal) saveLocked() error {
+	j.Updated = time.Now()
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации журнала: %w", err)
+	}
+
+	// Запись через временный файл: прерывание не оставит журнал наполовину записанным
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("ошибка записи журнала: %w", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("ошибка записи журнала: %w", err)
+	}
+	return nil
+}
+
+// Remove удаляет журнал (после успешного завершения всех томов)
+func (j *Journal) Remove() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Path возвращает путь к файлу журнала
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Методы VolumeCheckpoint допускают nil-получатель: движки вызывают их
+// безусловно, а без журнала (dry-run, обслуживание) они ничего не делают.
+
+// update изменяет контрольную точку и сохраняет журнал
+func (c *VolumeCheckpoint) update(logger *logging.EnterpriseLogger, fn func(c *VolumeCheckpoint)) {
+	if c == nil {
+		return
+	}
+	j := c.journal
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	fn(c)
+	if err := j.saveLocked(); err != nil && logger != nil {
+		logger.Log("WARN", "Ошибка сохранения контрольной точки", "disk", c.Disk, "journal", j.path, "error", err.Error())
+	}
+}
+
+// resumeState возвращает проход и позицию, с которых продолжается работа,
+// и журнал уже завершенных проходов
+func (c *VolumeCheckpoint) resumeState() (pass int, cursor passCursor, records []PassRecord) {
+	if c == nil {
+		return 0, passCursor{}, nil
+	}
+	c.journal.mu.Lock()
+	defer c.journal.mu.Unlock()
+
+	for _, r := range c.PassRecords {
+		if r.Status == "COMPLETED" {
+			records = append(records, r)
+		}
+	}
+	return c.Pass, passCursor{FileIndex: c.FileIndex, Bytes: c.PassBytes}, records
+}
+
+// keptFiles возвращает файлы прерванного прохода, оставленные на диске
+func (c *VolumeCheckpoint) keptFiles() []string {
+	if c == nil {
+		return nil
+	}
+	c.journal.mu.Lock()
+	defer c.journal.mu.Unlock()
+	return append([]string(nil), c.Files...)
+}
+
+// removeLeftovers удаляет файлы прерванного запуска. keepFiles сохраняет
+// завершенные файлы прохода (движок сессии держит их до конца прохода);
+// если хоть один из них пропал, проход начинается заново.
+func (c *VolumeCheckpoint) removeLeftovers(keepFiles bool, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		if c.CurrentFile != "" {
+			removeLeftover(c.CurrentFile, logger)
+			c.CurrentFile = ""
+		}
+
+		intact := keepFiles
+		for _, f := range c.Files {
+			if _, err := os.Stat(f); err != nil {
+				intact = false
+			}
+		}
+		if intact {
+			return
+		}
+
+		for _, f := range c.Files {
+			removeLeftover(f, logger)
+		}
+		c.Files = nil
+		if keepFiles {
+			c.FileIndex = 0
+			c.PassBytes = 0
+		}
+	})
+}
+
+// removeLeftover удаляет оставшийся от прерванного запуска файл
+func removeLeftover(name string, logger *logging.EnterpriseLogger) {
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		if logger != nil {
+			logger.Log("WARN", "Ошибка удаления файла прерванного запуска", "file", name, "error", err.Error())
+		}
+		return
+	}
+	if logger != nil {
+		logger.Log("INFO", "Удален файл прерванного запуска", "file", name)
+	}
+}
+
+// beginRun отмечает начало нового запуска над томом
+func (c *VolumeCheckpoint) beginRun(method string, passes int, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		c.Method = method
+		c.Passes = passes
+		c.Status = "RUNNING"
+		c.Segments = append(c.Segments, RunSegment{
+			Start:    time.Now(),
+			FromPass: c.Pass + 1,
+			Status:   "RUNNING",
+		})
+	})
+}
+
+// beginFile фиксирует начало записи файла
+func (c *VolumeCheckpoint) beginFile(name string, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		c.CurrentFile = name
+	})
+}
+
+// endFile фиксирует завершение файла; keep - файл остается на диске до конца прохода
+func (c *VolumeCheckpoint) endFile(next passCursor, keep bool, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		if keep && c.CurrentFile != "" {
+			c.Files = append(c.Files, c.CurrentFile)
+		}
+		c.CurrentFile = ""
+		c.FileIndex = next.FileIndex
+		c.PassBytes = next.Bytes
+	})
+}
+
+// passDone фиксирует завершение прохода
+func (c *VolumeCheckpoint) passDone(nextPass int, records []PassRecord, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		c.Pass = nextPass
+		c.FileIndex = 0
+		c.PassBytes = 0
+		c.Files = nil
+		c.CurrentFile = ""
+		c.PassRecords = append([]PassRecord(nil), records...)
+	})
+}
+
+// notStarted отмечает том, задание которого не запускалось: статус в журнале
+// совпадает со статусом операции в отчете, история прежних запусков
+// копируется в операцию
+func (c *VolumeCheckpoint) notStarted(op *WipeOperation, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		c.Status = op.Status
+		op.RunID = c.journal.RunID
+		op.PassRecords = append([]PassRecord(nil), c.PassRecords...)
+		op.Segments = append([]RunSegment(nil), c.Segments...)
+	})
+}
+
+// finishRun закрывает текущий запуск и копирует историю запусков в операцию
+func (c *VolumeCheckpoint) finishRun(op *WipeOperation, written uint64, logger *logging.EnterpriseLogger) {
+	c.update(logger, func(c *VolumeCheckpoint) {
+		c.Status = op.Status
+		c.PassRecords = append([]PassRecord(nil), op.PassRecords...)
+		if n := len(c.Segments); n > 0 {
+			seg := &c.Segments[n-1]
+			seg.End = time.Now()
+			seg.BytesWritten = written
+			seg.Status = op.Status
+		}
+
+		op.RunID = c.journal.RunID
+		op.Segments = append([]RunSegment(nil), c.Segments...)
+	})
+}
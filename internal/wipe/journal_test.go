@@ -0,0 +1,191 @@
+package wipe
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJournalSaveAndLoad(t *testing.T) {
+	stateDir := t.TempDir()
+	j, err := NewJournal(stateDir, "run_1", "standard", "balanced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(stateDir, "journal", "run_1.json"); j.Path() != want {
+		t.Errorf("Path() = %q, want %q", j.Path(), want)
+	}
+
+	v := j.Volume("/mnt/data")
+	if j.Volume("/mnt/data") != v {
+		t.Error("Volume вернул вторую контрольную точку для той же цели")
+	}
+	v.beginRun("dod5220", 3, nil)
+	v.beginFile("/mnt/data/wipe_0001.tmp", nil)
+	v.endFile(passCursor{FileIndex: 1, Bytes: 4096}, true, nil)
+	v.passDone(1, []PassRecord{{Number: 1, Status: "COMPLETED", BytesWritten: 8192}}, nil)
+	v.beginFile("/mnt/data/wipe_0002.tmp", nil)
+	v.endFile(passCursor{FileIndex: 1, Bytes: 1024}, true, nil)
+	v.beginFile("/mnt/data/wipe_0003.tmp", nil)
+
+	loaded, err := LoadJournal(stateDir, "run_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Mode != "standard" || loaded.Profile != "balanced" || len(loaded.Volumes) != 1 {
+		t.Fatalf("журнал: режим %q, профиль %q, томов %d", loaded.Mode, loaded.Profile, len(loaded.Volumes))
+	}
+
+	got := loaded.Volumes[0]
+	want := VolumeCheckpoint{
+		Disk: "/mnt/data", Method: "dod5220", Passes: 3, Pass: 1, FileIndex: 1, PassBytes: 1024,
+		Files:       []string{"/mnt/data/wipe_0002.tmp"},
+		CurrentFile: "/mnt/data/wipe_0003.tmp",
+		Status:      "RUNNING",
+		PassRecords: []PassRecord{{Number: 1, Status: "COMPLETED", BytesWritten: 8192}},
+	}
+	if got.Disk != want.Disk || got.Method != want.Method || got.Passes != want.Passes || got.Pass != want.Pass ||
+		got.FileIndex != want.FileIndex || got.PassBytes != want.PassBytes || got.CurrentFile != want.CurrentFile ||
+		got.Status != want.Status || !reflect.DeepEqual(got.Files, want.Files) || len(got.PassRecords) != 1 ||
+		got.PassRecords[0].BytesWritten != 8192 {
+		t.Errorf("контрольная точка после загрузки:\n got %+v\nwant %+v", *got, want)
+	}
+	if len(got.Segments) != 1 || got.Segments[0].FromPass != 1 {
+		t.Errorf("Segments = %+v", got.Segments)
+	}
+
+	if err := loaded.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJournal(stateDir, "run_1"); err == nil {
+		t.Error("LoadJournal после Remove: ожидалась ошибка")
+	}
+}
+
+func TestLoadJournalRunID(t *testing.T) {
+	stateDir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		runID string
+	}{
+		{"пустой", ""},
+		{"выход из каталога", "../run_1"},
+		{"разделитель", "a/run_1"},
+		{"обратный разделитель", `a\run_1`},
+		{"несуществующий", "run_missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadJournal(stateDir, tt.runID); err == nil {
+				t.Errorf("LoadJournal(%q): ожидалась ошибка", tt.runID)
+			}
+		})
+	}
+}
+
+func TestVolumeCheckpointResumeState(t *testing.T) {
+	j, err := NewJournal(t.TempDir(), "run_1", "standard", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := j.Volume("/mnt/data")
+	v.passDone(2, []PassRecord{
+		{Number: 1, Status: "COMPLETED"},
+		{Number: 2, Status: "COMPLETED"},
+		{Number: 3, Status: "CANCELLED"},
+	}, nil)
+	v.endFile(passCursor{FileIndex: 4, Bytes: 1 << 20}, false, nil)
+
+	pass, cursor, records := v.resumeState()
+	if pass != 2 || cursor != (passCursor{FileIndex: 4, Bytes: 1 << 20}) {
+		t.Errorf("resumeState: проход %d, позиция %+v", pass, cursor)
+	}
+	if len(records) != 2 || records[1].Number != 2 {
+		t.Errorf("записи завершенных проходов: %+v", records)
+	}
+
+	var none *VolumeCheckpoint
+	if pass, cursor, records := none.resumeState(); pass != 0 || cursor != (passCursor{}) || records != nil {
+		t.Error("resumeState без журнала должен начинать с нуля")
+	}
+}
+
+func TestJournalPending(t *testing.T) {
+	j, err := NewJournal(t.TempDir(), "run_1", "standard", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct{ disk, status string }{
+		{"/a", "COMPLETED"}, {"/b", "PARTIAL"}, {"/c", "PENDING"}, {"/d", "CANCELLED"}, {"/e", "COMPLETED"},
+	} {
+		j.Volume(v.disk).Status = v.status
+	}
+
+	var got []string
+	for _, v := range j.Pending() {
+		got = append(got, v.Disk)
+	}
+	if want := []string{"/b", "/c", "/d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending() = %v, want %v", got, want)
+	}
+}
+
+func TestVolumeCheckpointRemoveLeftovers(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepFiles  bool
+		missing    bool // Один из завершенных файлов пропал
+		wantKept   bool
+		wantCursor passCursor
+	}{
+		{"файлы целы", true, false, true, passCursor{FileIndex: 2, Bytes: 2048}},
+		{"файл пропал - проход заново", true, true, false, passCursor{}},
+		{"файлы не удерживаются", false, false, false, passCursor{FileIndex: 2, Bytes: 2048}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			j, err := NewJournal(t.TempDir(), "run_1", "standard", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			v := j.Volume(dir)
+
+			files := []string{filepath.Join(dir, "f1"), filepath.Join(dir, "f2")}
+			current := filepath.Join(dir, "f3")
+			for _, f := range append(files, current) {
+				if err := os.WriteFile(f, []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, f := range files {
+				v.beginFile(f, nil)
+				v.endFile(passCursor{FileIndex: 2, Bytes: 2048}, true, nil)
+			}
+			v.beginFile(current, nil)
+			if tt.missing {
+				os.Remove(files[0])
+			}
+
+			v.removeLeftovers(tt.keepFiles, nil)
+
+			if _, err := os.Stat(current); !os.IsNotExist(err) {
+				t.Error("недописанный файл не удален")
+			}
+			_, err = os.Stat(files[1])
+			if kept := err == nil; kept != tt.wantKept {
+				t.Errorf("завершенный файл сохранен: %v, want %v", kept, tt.wantKept)
+			}
+			if v.CurrentFile != "" || (len(v.Files) > 0) != tt.wantKept {
+				t.Errorf("CurrentFile %q, Files %v", v.CurrentFile, v.Files)
+			}
+			if cursor := (passCursor{FileIndex: v.FileIndex, Bytes: v.PassBytes}); cursor != tt.wantCursor {
+				t.Errorf("позиция %+v, want %+v", cursor, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package wipe
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+// Заголовок-маркер записывается в начало каждого файла затирания:
+//
+//	magic(8) | created unix(8) | pid(4) | len(name)(2) | name | HMAC-SHA256(32)
+//
+// Подпись вычисляется ключом из <state_dir>/marker.key, поэтому восстановление
+// удаляет только файлы, созданные этой установкой, и не трогает чужие файлы
+// с похожими именами.
+const (
+	markerMagic = "WDSKART1"
+
+	// MarkerSize - максимальный размер заголовка-маркера в начале файла
+	MarkerSize = 512
+
+	markerKeyFile = "marker.key"
+	markerKeySize = 32
+)
+
+// ArtifactMarker подписывает и проверяет заголовки файлов затирания
+type ArtifactMarker struct {
+	key []byte
+}
+
+// ArtifactHeader разобранный заголовок файла затирания
+type ArtifactHeader struct {
+	Name    string
+	Created time.Time
+	PID     int
+}
+
+// LoadArtifactMarker загружает ключ подписи из каталога состояния, создавая его при первом запуске
+func LoadArtifactMarker(stateDir string) (*ArtifactMarker, error) {
+	if stateDir == "" {
+		stateDir = config.DefaultStateDir
+	}
+	path := filepath.Join(stateDir, markerKeyFile)
+
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != markerKeySize {
+			return nil, fmt.Errorf("некорректный размер ключа маркеров %s: %d", path, len(key))
+		}
+		return &ArtifactMarker{key: key}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("ошибка чтения ключа маркеров %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога состояния %s: %w", stateDir, err)
+	}
+	key = make([]byte, markerKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("ошибка генерации ключа маркеров: %w", err)
+	}
+
+	// O_EXCL: при одновременном первом запуске побеждает один процесс
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return LoadArtifactMarker(stateDir)
+		}
+		return nil, fmt.Errorf("ошибка создания ключа маркеров %s: %w", path, err)
+	}
+	if _, err := f.Write(key); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ошибка записи ключа маркеров %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка записи ключа маркеров %s: %w", path, err)
+	}
+
+	return &ArtifactMarker{key: key}, nil
+}
+
+// Header возвращает подписанный заголовок для файла filename
+func (m *ArtifactMarker) Header(filename string) []byte {
+	name := filepath.Base(filename)
+
+	var buf bytes.Buffer
+	buf.WriteString(markerMagic)
+	binary.Write(&buf, binary.LittleEndian, time.Now().Unix())
+	binary.Write(&buf, binary.LittleEndian, uint32(os.Getpid()))
+	binary.Write(&buf, binary.LittleEndian, uint16(len(name)))
+	buf.WriteString(name)
+	buf.Write(m.sign(buf.Bytes()))
+	return buf.Bytes()
+}
+
+// Verify читает заголовок файла path и проверяет его подпись и имя файла
+func (m *ArtifactMarker) Verify(path string) (*ArtifactHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, MarkerSize)
+	n, err := io.ReadFull(f, data)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("нет заголовка-маркера")
+	}
+	data = data[:n]
+
+	const fixed = len(markerMagic) + 8 + 4 + 2
+	if len(data) < fixed+sha256.Size || string(data[:len(markerMagic)]) != markerMagic {
+		return nil, fmt.Errorf("нет заголовка-маркера")
+	}
+
+	created := int64(binary.LittleEndian.Uint64(data[8:16]))
+	pid := binary.LittleEndian.Uint32(data[16:20])
+	nameLen := int(binary.LittleEndian.Uint16(data[20:22]))
+	if fixed+nameLen+sha256.Size > len(data) {
+		return nil, fmt.Errorf("поврежденный заголовок-маркер")
+	}
+
+	signed := data[:fixed+nameLen]
+	signature := data[fixed+nameLen : fixed+nameLen+sha256.Size]
+	if !hmac.Equal(signature, m.sign(signed)) {
+		return nil, fmt.Errorf("подпись заголовка не совпадает")
+	}
+
+	name := string(data[fixed : fixed+nameLen])
+	if name != filepath.Base(path) {
+		return nil, fmt.Errorf("заголовок выписан для файла %s", name)
+	}
+
+	return &ArtifactHeader{
+		Name:    name,
+		Created: time.Unix(created, 0),
+		PID:     int(pid),
+	}, nil
+}
+
+func (m *ArtifactMarker) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// loadMarker загружает ключ подписи из каталога состояния stateDir. Если
+// ключ недоступен, файлы затирания создаются без маркера: восстановление их
+// не тронет.
+func loadMarker(stateDir string, logger *logging.EnterpriseLogger) *ArtifactMarker {
+	m, err := LoadArtifactMarker(stateDir)
+	if err != nil {
+		logger.Log("WARN", "Ключ маркеров недоступен, файлы затирания не подписываются", "error", err.Error())
+		return nil
+	}
+	return m
+}
+
+// mark записывает заголовок-маркер поверх начала первого чанка файла.
+// Без ключа (nil) файл остается без маркера.
+func (m *ArtifactMarker) mark(chunk []byte, filename string) int {
+	if m == nil {
+		return 0
+	}
+	return copy(chunk, m.Header(filename))
+}
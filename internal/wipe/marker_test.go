@@ -0,0 +1,152 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeArtifact создает файл артефакта с заголовком-маркером m (nil - без
+// маркера) и временем изменения age назад
+func writeArtifact(t *testing.T, path string, m *ArtifactMarker, age time.Duration) {
+	t.Helper()
+	data := bytes.Repeat([]byte{0xA5}, 4096)
+	m.mark(data, path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadArtifactMarker(t *testing.T) {
+	stateDir := t.TempDir()
+	m, err := LoadArtifactMarker(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadArtifactMarker(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.key, again.key) {
+		t.Error("повторная загрузка создала новый ключ")
+	}
+
+	bad := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bad, markerKeyFile), []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadArtifactMarker(bad); err == nil || !strings.Contains(err.Error(), "некорректный размер") {
+		t.Errorf("ключ неверного размера: ошибка %v", err)
+	}
+}
+
+func TestArtifactMarkerVerify(t *testing.T) {
+	m, err := LoadArtifactMarker(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := LoadArtifactMarker(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		write   func(path string) error
+		wantErr string
+	}{
+		{"свой заголовок", func(path string) error {
+			return os.WriteFile(path, m.Header(path), 0644)
+		}, ""},
+		{"чужой ключ", func(path string) error {
+			return os.WriteFile(path, other.Header(path), 0644)
+		}, "подпись заголовка не совпадает"},
+		{"заголовок другого файла", func(path string) error {
+			return os.WriteFile(path, m.Header("wipe_999.tmp"), 0644)
+		}, "заголовок выписан для файла wipe_999.tmp"},
+		{"без маркера", func(path string) error {
+			return os.WriteFile(path, bytes.Repeat([]byte{0}, 1024), 0644)
+		}, "нет заголовка-маркера"},
+		{"обрезанный заголовок", func(path string) error {
+			return os.WriteFile(path, m.Header(path)[:60], 0644)
+		}, "поврежденный заголовок-маркер"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "wipe_001.tmp")
+			if err := tt.write(path); err != nil {
+				t.Fatal(err)
+			}
+			h, err := m.Verify(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if h.Name != "wipe_001.tmp" || h.PID != os.Getpid() {
+					t.Errorf("заголовок %+v", h)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify: ошибка %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecoverArtifacts(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		signed      bool
+		age         time.Duration
+		dryRun      bool
+		wantRemoved bool
+	}{
+		{"подписанный файл", "wipe_001.tmp", true, time.Hour, false, true},
+		{"подписанный файл cipher", "cipher_001_1.tmp", true, time.Hour, false, true},
+		{"тестовый режим", "wipe_001.tmp", true, time.Hour, true, false},
+		{"без маркера", "wipe_001.tmp", false, time.Hour, false, false},
+		{"недавно изменялся", "wipe_001.tmp", true, 0, false, false},
+		{"чужое имя", "wipe_1.tmp", true, time.Hour, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateDir := t.TempDir()
+			m, err := LoadArtifactMarker(stateDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.signed {
+				m = nil
+			}
+			volume := t.TempDir()
+			path := filepath.Join(volume, tt.file)
+			writeArtifact(t, path, m, tt.age)
+
+			report, err := RecoverArtifacts(context.Background(), stateDir, []string{volume}, tt.dryRun, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, statErr := os.Stat(path)
+			if removed := os.IsNotExist(statErr); removed != tt.wantRemoved {
+				t.Errorf("файл удален: %v, want %v", removed, tt.wantRemoved)
+			}
+			listed := len(report.Removed) == 1 && report.Removed[0].Path == path
+			if wantListed := tt.wantRemoved || (tt.dryRun && tt.signed); listed != wantListed {
+				t.Errorf("в отчете удаленных: %+v", report.Removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+const (
+	// artifactMetaDir - каталог мелких файлов очистки метаданных
+	artifactMetaDir = ".wipedisk_meta"
+	// metaOwnerFile - подписанный файл-маркер, подтверждающий, что каталог создан WipeDisk
+	metaOwnerFile = ".owner"
+
+	// Длина случайных имен: разные длины занимают записи каталога разного размера
+	metaMinNameLen = 8
+	metaMaxNameLen = 64
+)
+
+// MetadataScrubResult итог очистки метаданных
+type MetadataScrubResult struct {
+	Files      int
+	Bytes      uint64
+	Exhausted  bool   // Место под метаданные (или сам том) закончилось
+	StopReason string // exhausted, max_files, max_duration, cancelled, error
+	Duration   time.Duration
+}
+
+// ScrubMetadata создает в служебном каталоге тома мелкие файлы со случайными
+// именами и случайными данными, пока не закончится место под метаданные или
+// не будет достигнут предел числа файлов или времени, затем удаляет их.
+// Каждый файл синхронизируется на диск: иначе файловая система может так и
+// не записать его до удаления. marker подписывает служебный каталог для
+// wipedisk recover (nil - без подписи).
+func ScrubMetadata(ctx context.Context, root string, settings config.MetadataScrubConfig, marker *ArtifactMarker, logger *logging.EnterpriseLogger) (*MetadataScrubResult, error) {
+	start := time.Now()
+	result := &MetadataScrubResult{}
+
+	maxDuration, err := time.ParseDuration(settings.MaxDuration)
+	if err != nil || maxDuration <= 0 {
+		maxDuration = 10 * time.Minute
+	}
+	maxSize := settings.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = 700
+	}
+
+	dir := filepath.Join(root, artifactMetaDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return result, fmt.Errorf("ошибка создания каталога %s: %w", dir, err)
+	}
+	defer func() {
+		removeMetaDir(dir, logger)
+		result.Duration = time.Since(start)
+	}()
+	writeMetaOwner(dir, marker)
+
+	logger.Log("INFO", "Очистка метаданных", "dir", dir, "max_files", settings.MaxFiles,
+		"max_duration", maxDuration, "max_file_size", maxSize)
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	buf := make([]byte, maxSize)
+	deadline := start.Add(maxDuration)
+
+	for result.Files < settings.MaxFiles {
+		if ctx.Err() != nil {
+			result.StopReason = "cancelled"
+			return result, nil
+		}
+		if time.Now().After(deadline) {
+			result.StopReason = "max_duration"
+			return result, nil
+		}
+
+		name, err := randomName(metaMinNameLen + rng.Intn(metaMaxNameLen-metaMinNameLen+1))
+		if err != nil {
+			result.StopReason = "error"
+			return result, err
+		}
+		data := buf[:1+rng.Intn(maxSize)]
+		if err := FillRandom(data); err != nil {
+			result.StopReason = "error"
+			return result, err
+		}
+
+		err = writeMetaFile(filepath.Join(dir, name), data)
+		if os.IsExist(err) {
+			continue // Совпадение случайного имени
+		}
+		if err != nil {
+			if isDiskFullError(err) {
+				result.Exhausted = true
+				result.StopReason = "exhausted"
+				return result, nil
+			}
+			result.StopReason = "error"
+			return result, err
+		}
+
+		result.Files++
+		result.Bytes += uint64(len(data))
+	}
+
+	result.StopReason = "max_files"
+	return result, nil
+}
+
+// writeMetaFile создает файл с данными и синхронизирует его на диск
+func writeMetaFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// writeMetaOwner подписывает каталог: восстановление после сбоя удаляет
+// содержимое только каталогов с верным маркером
+func writeMetaOwner(dir string, m *ArtifactMarker) {
+	if m == nil {
+		return
+	}
+	path := filepath.Join(dir, metaOwnerFile)
+	os.WriteFile(path, m.Header(path), 0600)
+}
+
+// removeMetaDir удаляет каталог очистки метаданных со всем содержимым
+func removeMetaDir(dir string, logger *logging.EnterpriseLogger) {
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Log("WARN", "Ошибка удаления каталога очистки метаданных", "dir", dir, "error", err.Error())
+	}
+}
+
+// scrubMetadataPhase выполняет очистку метаданных как отдельный шаг операции.
+// Сбой фазы не отменяет уже выполненное затирание и отмечается предупреждением.
+func (op *WipeOperation) scrubMetadataPhase(ctx context.Context, root string, settings config.MetadataScrubConfig, marker *ArtifactMarker, logger *logging.EnterpriseLogger) {
+	if !settings.Enabled {
+		return
+	}
+
+	record := op.beginPass(PhaseMetadataScrub, "random small files")
+	result, err := ScrubMetadata(ctx, root, settings, marker, logger)
+	record.Files = result.Files
+
+	status := "COMPLETED"
+	switch {
+	case err != nil:
+		status = "FAILED"
+	case result.StopReason == "cancelled":
+		status = "CANCELLED"
+	}
+	record.finish(result.Bytes, status, err)
+
+	logger.Log("INFO", "Очистка метаданных завершена", "disk", op.Disk, "files", result.Files, "bytes", result.Bytes,
+		"stop_reason", result.StopReason, "exhausted", result.Exhausted, "duration", result.Duration, "status", status)
+
+	if status != "COMPLETED" && op.Warning == "" {
+		op.Warning = fmt.Sprintf("Очистка метаданных не завершена (%s)", status)
+	}
+}
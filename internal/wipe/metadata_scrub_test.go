@@ -0,0 +1,101 @@
+package wipe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+)
+
+func TestScrubMetadata(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   config.MetadataScrubConfig
+		cancelled  bool
+		wantFiles  int
+		wantReason string
+	}{
+		{"предел числа файлов", config.MetadataScrubConfig{MaxFiles: 20, MaxDuration: "1m", MaxFileSize: 100}, false, 20, "max_files"},
+		{"размер по умолчанию", config.MetadataScrubConfig{MaxFiles: 5}, false, 5, "max_files"},
+		{"отмена", config.MetadataScrubConfig{MaxFiles: 20, MaxDuration: "1m"}, true, 0, "cancelled"},
+	}
+
+	m, err := LoadArtifactMarker(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+			root := t.TempDir()
+
+			result, err := ScrubMetadata(ctx, root, tt.settings, m, newTestLogger(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Files != tt.wantFiles || result.StopReason != tt.wantReason {
+				t.Errorf("файлов %d, причина %s, want %d и %s", result.Files, result.StopReason, tt.wantFiles, tt.wantReason)
+			}
+			maxSize := uint64(tt.settings.MaxFileSize)
+			if maxSize == 0 {
+				maxSize = 700
+			}
+			if result.Bytes < uint64(result.Files) || result.Bytes > uint64(result.Files)*maxSize {
+				t.Errorf("%d байт в %d файлах", result.Bytes, result.Files)
+			}
+			if _, err := os.Stat(filepath.Join(root, artifactMetaDir)); !os.IsNotExist(err) {
+				t.Errorf("служебный каталог не удален: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecoverMetaDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		signed      bool
+		age         time.Duration
+		wantRemoved bool
+	}{
+		{"подписанный каталог", true, time.Hour, true},
+		{"без маркера", false, time.Hour, false},
+		{"недавно создан", true, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateDir := t.TempDir()
+			m, err := LoadArtifactMarker(stateDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.signed {
+				m = nil
+			}
+			volume := t.TempDir()
+			dir := filepath.Join(volume, artifactMetaDir)
+			if err := os.Mkdir(dir, 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "abcdefgh"), make([]byte, 100), 0600); err != nil {
+				t.Fatal(err)
+			}
+			writeArtifact(t, filepath.Join(dir, metaOwnerFile), m, tt.age)
+
+			if _, err := RecoverArtifacts(context.Background(), stateDir, []string{volume}, false, nil); err != nil {
+				t.Fatal(err)
+			}
+			_, statErr := os.Stat(dir)
+			if removed := os.IsNotExist(statErr); removed != tt.wantRemoved {
+				t.Errorf("каталог удален: %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
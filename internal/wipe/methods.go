@@ -1,154 +1,154 @@
 package wipe
 
 import (
-	"crypto/rand"
 	"fmt"
-	"os"
+	"slices"
+	"strings"
 
-	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/config"
 )
 
-// WipeMethod определяет метод заполнения данных
+// WipeMethod определяет метод заполнения данных (имя схемы в реестре PatternScheme)
 type WipeMethod string
 
 const (
-	MethodRandom        WipeMethod = "random"
-	MethodZero          WipeMethod = "zero"
-	MethodDOD5220       WipeMethod = "dod5220"
-	MethodSDeleteCompat WipeMethod = "sdelete-compatible"
+	MethodRandom         WipeMethod = "random"
+	MethodZero           WipeMethod = "zero"
+	MethodDOD5220        WipeMethod = "dod5220"
+	MethodSDeleteCompat  WipeMethod = "sdelete-compatible"
+	MethodCipher         WipeMethod = "cipher"
+	MethodGutmann        WipeMethod = "gutmann"
+	MethodSchneier       WipeMethod = "schneier"
+	MethodVSITR          WipeMethod = "vsitr"
+	MethodHMGIS5         WipeMethod = "hmg-is5"
+	MethodHMGIS5Enhanced WipeMethod = "hmg-is5-enhanced"
+	MethodGOST           WipeMethod = "gost-r50739-95"
 )
 
 // FillPattern генерирует паттерн для заполнения в зависимости от метода
 func FillPattern(method WipeMethod, pass int, size int) ([]byte, error) {
-	switch method {
-	case MethodRandom:
-		data := make([]byte, size)
-		if _, err := rand.Read(data); err != nil {
-			return nil, fmt.Errorf("ошибка генерации случайных данных: %w", err)
-		}
-		return data, nil
-
-	case MethodZero:
-		return make([]byte, size), nil
+	scheme, ok := LookupScheme(string(method))
+	if !ok {
+		return nil, fmt.Errorf("неизвестный метод затирания: %s", method)
+	}
 
-	case MethodDOD5220:
-		// DOD 5220.22-M: 3 прохода - случайные, нули, случайные
-		switch pass % 3 {
-		case 0, 2: // 1-й и 3-й проходы - случайные
-			data := make([]byte, size)
-			if _, err := rand.Read(data); err != nil {
-				return nil, fmt.Errorf("ошибка генерации случайных данных: %w", err)
-			}
-			return data, nil
-		case 1: // 2-й проход - нули
-			return make([]byte, size), nil
-		}
-		// Этот return никогда не будет достигнут, но нужен для компилятора
-		return nil, fmt.Errorf("неверный проход для метода DOD5220: %d", pass)
+	data := make([]byte, size)
+	if err := scheme.Pass(pass).Fill(data, 0); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
 
-	case MethodSDeleteCompat:
-		// SDelete совместимость: 1 проход случайными данными
-		data := make([]byte, size)
-		if _, err := rand.Read(data); err != nil {
-			return nil, fmt.Errorf("ошибка генерации случайных данных: %w", err)
-		}
-		return data, nil
+// IsSDeleteCompatible проверяет, совместим ли метод с SDelete
+func IsSDeleteCompatible(method WipeMethod) bool {
+	return method == MethodSDeleteCompat || method == MethodRandom
+}
 
-	default:
-		return nil, fmt.Errorf("неизвестный метод затирания: %s", method)
+// GetMethodPasses возвращает количество проходов для метода
+func GetMethodPasses(method WipeMethod) int {
+	if scheme, ok := LookupScheme(string(method)); ok {
+		return len(scheme.Passes)
 	}
+	return 1
 }
 
-// CreateWipeFileWithMethod создает файл с использованием указанного метода
-func CreateWipeFileWithMethod(filename string, fileSize uint64, method WipeMethod, pass int, maxSpeedMBps float64, logger *logging.EnterpriseLogger) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+// ValidateMethod проверяет корректность метода и возвращает его каноническое имя
+func ValidateMethod(method string) (WipeMethod, error) {
+	scheme, ok := LookupScheme(method)
+	if !ok {
+		return "", fmt.Errorf("неподдерживаемый метод затирания: %s (доступные: %v)", method, SchemeNames())
 	}
-	defer file.Close()
-
-	throttledWriter := NewThrottledWriter(file, maxSpeedMBps)
+	return WipeMethod(scheme.Name), nil
+}
 
-	// Определяем размер чанка в зависимости от метода
-	chunkSize := getChunkSizeForMethod(method)
+// MethodForDisk выбирает метод из конфигурации по типу диска
+func MethodForDisk(ssdMethod, hddMethod, diskType string) WipeMethod {
+	if diskType == "SSD" {
+		return WipeMethod(ssdMethod)
+	}
+	return WipeMethod(hddMethod)
+}
 
-	var written uint64
-	for written < fileSize {
-		remaining := fileSize - written
-		toWrite := uint64(chunkSize)
-		if remaining < toWrite {
-			toWrite = remaining
-		}
+// RegisterConfigMethods заменяет в реестре схемы из секции methods
+// конфигурации. Имена методов не должны совпадать со встроенными схемами.
+func RegisterConfigMethods(methods map[string]config.MethodConfig) error {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	slices.Sort(names)
 
-		// Генерируем паттерн для заполнения
-		pattern, err := FillPattern(method, pass, int(toWrite))
+	list := make([]PatternScheme, 0, len(names))
+	for _, name := range names {
+		s, err := schemeFromConfig(name, methods[name])
 		if err != nil {
-			return fmt.Errorf("ошибка генерации паттерна: %w", err)
+			return fmt.Errorf("метод %s: %w", name, err)
 		}
-
-		// Записываем данные с throttling
-		off := 0
-		for off < int(toWrite) {
-			n, err := throttledWriter.Write(pattern[off:])
-			if n > 0 {
-				off += n
-				written += uint64(n)
-			}
-			if err != nil {
-				return fmt.Errorf("ошибка записи в файл: %w", err)
-			}
-			if n == 0 {
-				return fmt.Errorf("запись вернула 0 байт без ошибки")
-			}
+		if err := prepareScheme(&s); err != nil {
+			return err
 		}
+		if s.Description == "" {
+			s.Description = "Из конфигурации: " + strings.Join(s.PassDescriptions(), ", ")
+		}
+		list = append(list, s)
 	}
-
-	// Синхронизация данных с диском
-	if err := throttledWriter.Sync(); err != nil {
-		return err
-	}
-
-	return nil
+	return replaceConfigSchemes(list)
 }
 
-// getChunkSizeForMethod возвращает оптимальный размер чанка для метода
-func getChunkSizeForMethod(method WipeMethod) int {
-	switch method {
-	case MethodRandom, MethodSDeleteCompat:
-		return 16 * 1024 * 1024 // 16MB для случайных данных
-	case MethodZero:
-		return 32 * 1024 * 1024 // 32MB для нулей (быстрее)
-	case MethodDOD5220:
-		return 8 * 1024 * 1024 // 8MB для DOD (больше проходов)
-	default:
-		return 16 * 1024 * 1024
+// schemeFromConfig строит схему по методу из конфигурации
+func schemeFromConfig(name string, m config.MethodConfig) (PatternScheme, error) {
+	s := PatternScheme{Name: name, Description: m.Description, ChunkSize: m.ChunkSize}
+	for i, p := range m.Passes {
+		var spec PassSpec
+		switch p.Type {
+		case config.PassTypeFixed:
+			if p.Byte == nil {
+				return s, fmt.Errorf("проход %d: не задан байт", i+1)
+			}
+			spec = FixedPass(byte(*p.Byte))
+		case config.PassTypeSequence:
+			spec = RepeatPass(methodBytes(p.Bytes)...)
+		case config.PassTypeComplement:
+			spec = ComplementPass(methodBytes(p.Bytes)...)
+		case config.PassTypeRandom:
+			spec = RandomPass()
+		default:
+			return s, fmt.Errorf("проход %d: неизвестный тип %q", i+1, p.Type)
+		}
+		spec.Verify = p.Verify
+		s.Passes = append(s.Passes, spec)
 	}
+	return s, nil
 }
 
-// IsSDeleteCompatible проверяет, совместим ли метод с SDelete
-func IsSDeleteCompatible(method WipeMethod) bool {
-	return method == MethodSDeleteCompat || method == MethodRandom
-}
-
-// GetMethodPasses возвращает количество проходов для метода
-func GetMethodPasses(method WipeMethod) int {
-	switch method {
-	case MethodRandom, MethodZero, MethodSDeleteCompat:
-		return 1
-	case MethodDOD5220:
-		return 3
-	default:
-		return 1
+// methodBytes преобразует байты прохода из конфигурации
+func methodBytes(values []int) []byte {
+	out := make([]byte, len(values))
+	for i, v := range values {
+		out[i] = byte(v)
 	}
+	return out
 }
 
-// ValidateMethod проверяет корректность метода
-func ValidateMethod(method string) (WipeMethod, error) {
-	m := WipeMethod(method)
-	switch m {
-	case MethodRandom, MethodZero, MethodDOD5220, MethodSDeleteCompat:
-		return m, nil
-	default:
-		return "", fmt.Errorf("неподдерживаемый метод затирания: %s", method)
+// ValidateConfig проверяет конфигурацию (config.Validate) и то, что известно
+// только реестру схем: регистрирует методы секции methods и проверяет
+// ssd_method/hdd_method. Вызывается после загрузки конфигурации перед
+// затиранием.
+func ValidateConfig(cfg *config.Config) error {
+	if err := config.Validate(cfg); err != nil {
+		return err
 	}
+	if err := RegisterConfigMethods(cfg.Methods); err != nil {
+		return err
+	}
+	if !cfg.Wipe.Enabled {
+		return nil
+	}
+	if _, err := ValidateMethod(cfg.Wipe.SSDMethod); err != nil {
+		return fmt.Errorf("invalid SSD method: %w", err)
+	}
+	if _, err := ValidateMethod(cfg.Wipe.HDDMethod); err != nil {
+		return fmt.Errorf("invalid HDD method: %w", err)
+	}
+	return nil
 }
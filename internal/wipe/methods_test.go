@@ -0,0 +1,165 @@
+package wipe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"wipedisk_enterprise/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseMethods разбирает секцию methods конфигурации
+func parseMethods(t *testing.T, src string) map[string]config.MethodConfig {
+	t.Helper()
+	var methods map[string]config.MethodConfig
+	if err := yaml.Unmarshal([]byte(src), &methods); err != nil {
+		t.Fatal(err)
+	}
+	return methods
+}
+
+func TestRegisterConfigMethods(t *testing.T) {
+	t.Cleanup(func() { RegisterConfigMethods(nil) })
+
+	tests := []struct {
+		name      string
+		yaml      string
+		lookup    string
+		wantPass  []string
+		wantDesc  string
+		wantChunk int
+		wantErr   string
+	}{
+		{"все типы проходов", `
+Corp:
+  passes:
+    - {type: fixed, byte: 0x00}
+    - {type: complement}
+    - {type: sequence, bytes: [0x92, 0x49]}
+    - {type: complement, bytes: [0x0F]}
+    - {type: random, verify: true}`, "corp",
+			[]string{"0x00", "0xFF", "0x92 0x49", "0xF0", "random (verify)"},
+			"Из конфигурации: 0x00, 0xFF, 0x92 0x49, 0xF0, random (verify)", defaultSchemeChunkSize, ""},
+		{"описание и размер блока", `
+corp:
+  description: Корпоративный
+  chunk_size: 1048576
+  passes: [{type: random}]`, "CORP", []string{"random"}, "Корпоративный", 1 << 20, ""},
+		{"имя встроенной схемы", `
+dod5220:
+  passes: [{type: random}]`, "", nil, "", 0, "метод dod5220 из секции methods: схема затирания dod5220 уже зарегистрирована"},
+		{"имя псевдонима", `
+zeros:
+  passes: [{type: random}]`, "", nil, "", 0, "уже используется как псевдоним"},
+		{"неизвестный тип прохода", `
+corp:
+  passes: [{type: zero}]`, "", nil, "", 0, `метод corp: проход 1: неизвестный тип "zero"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterConfigMethods(parseMethods(t, tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RegisterConfigMethods: ошибка %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, ok := LookupScheme(tt.lookup)
+			if !ok {
+				t.Fatalf("метод %q не зарегистрирован", tt.lookup)
+			}
+			if got := s.PassDescriptions(); !reflect.DeepEqual(got, tt.wantPass) {
+				t.Errorf("проходы %v, want %v", got, tt.wantPass)
+			}
+			if s.Description != tt.wantDesc || s.ChunkSize != tt.wantChunk {
+				t.Errorf("Description %q, ChunkSize %d; want %q, %d", s.Description, s.ChunkSize, tt.wantDesc, tt.wantChunk)
+			}
+		})
+	}
+}
+
+func TestRegisterConfigMethodsReplace(t *testing.T) {
+	t.Cleanup(func() { RegisterConfigMethods(nil) })
+
+	if err := RegisterConfigMethods(parseMethods(t, `
+old:
+  passes: [{type: random}]
+corp:
+  passes: [{type: random}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterConfigMethods(parseMethods(t, `
+corp:
+  passes: [{type: fixed, byte: 0xFF}, {type: random}]`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := LookupScheme("old"); ok {
+		t.Error("метод, удаленный из конфигурации, остался в реестре")
+	}
+	if got := GetMethodPasses("corp"); got != 2 {
+		t.Errorf("GetMethodPasses(corp) = %d, want 2", got)
+	}
+
+	if err := RegisterConfigMethods(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LookupScheme("corp"); ok {
+		t.Error("RegisterConfigMethods(nil) не удалил методы конфигурации")
+	}
+	if _, ok := LookupScheme("dod5220"); !ok {
+		t.Error("RegisterConfigMethods удалил встроенную схему")
+	}
+}
+
+func TestValidateConfigMethods(t *testing.T) {
+	t.Cleanup(func() { RegisterConfigMethods(nil) })
+
+	tests := []struct {
+		name      string
+		ssdMethod string
+		hddMethod string
+		methods   string
+		wantErr   string
+	}{
+		{"встроенные методы", "random", "dod5220", "", ""},
+		{"псевдоним", "zeros", "GOST", "", ""},
+		{"метод из конфигурации", "corp", "dod5220", `
+corp:
+  passes: [{type: fixed, byte: 0x00}, {type: random}]`, ""},
+		{"неизвестный метод SSD", "corp", "dod5220", "", "invalid SSD method: неподдерживаемый метод затирания: corp"},
+		{"неизвестный метод HDD", "random", "turbo", "", "invalid HDD method: неподдерживаемый метод затирания: turbo"},
+		{"ошибка в секции methods", "random", "random", `
+corp:
+  passes: [{type: complement}]`, "method corp, pass 1: complement pass without bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Wipe.SSDMethod = tt.ssdMethod
+			cfg.Wipe.HDDMethod = tt.hddMethod
+			if tt.methods != "" {
+				cfg.Methods = parseMethods(t, tt.methods)
+			}
+
+			err := ValidateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig: ошибка %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
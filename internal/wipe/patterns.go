@@ -0,0 +1,469 @@
+package wipe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PassKind определяет генератор данных для прохода
+type PassKind int
+
+const (
+	PassFixed      PassKind = iota // Один и тот же байт
+	PassComplement                 // Инверсия паттерна (по умолчанию - предыдущего прохода)
+	PassRandom                     // Криптографически стойкие случайные данные
+	PassRepeat                     // Повторяющаяся многобайтовая последовательность
+)
+
+// PassSpec описывает один проход схемы затирания
+type PassSpec struct {
+	Kind    PassKind
+	Pattern []byte // Fixed: 1 байт, Repeat: последовательность, Complement: исходный паттерн
+	Verify  bool   // Проверить проход чтением целиком независимо от wipe.verify
+}
+
+// FixedPass проход одним байтом
+func FixedPass(b byte) PassSpec {
+	return PassSpec{Kind: PassFixed, Pattern: []byte{b}}
+}
+
+// RepeatPass проход повторяющейся последовательностью байт
+func RepeatPass(pattern ...byte) PassSpec {
+	return PassSpec{Kind: PassRepeat, Pattern: pattern}
+}
+
+// ComplementPass проход инверсией паттерна. Без аргументов инвертируется
+// паттерн предыдущего прохода схемы (разрешается при регистрации).
+func ComplementPass(pattern ...byte) PassSpec {
+	return PassSpec{Kind: PassComplement, Pattern: pattern}
+}
+
+// RandomPass проход случайными данными
+func RandomPass() PassSpec {
+	return PassSpec{Kind: PassRandom}
+}
+
+// Fill заполняет buf данными прохода. offset - смещение buf от начала файла,
+// чтобы многобайтовые паттерны не сбивались на границах чанков.
+func (p PassSpec) Fill(buf []byte, offset uint64) error {
+	switch p.Kind {
+	case PassRandom:
+		if err := sharedRandom.Read(buf); err != nil {
+			return fmt.Errorf("ошибка генерации случайных данных: %w", err)
+		}
+		return nil
+
+	case PassFixed, PassRepeat, PassComplement:
+		pattern := p.bytes()
+		if len(pattern) == 0 {
+			return fmt.Errorf("пустой паттерн прохода %s", p)
+		}
+		fillRepeating(buf, pattern, offset)
+		return nil
+
+	default:
+		return fmt.Errorf("неизвестный тип прохода: %d", p.Kind)
+	}
+}
+
+// PassFiller источник данных одного прохода, адресуемый смещением
+type PassFiller interface {
+	FillAt(buf []byte, offset uint64) error
+}
+
+// NewFiller создает источник данных для прохода. Для случайного прохода
+// создается отдельный поток AES-CTR, так что данные не повторяются
+// в пределах всего прохода. Источник прохода с Verify отмечен для полной
+// проверки чтением.
+func (p PassSpec) NewFiller() (PassFiller, error) {
+	var filler PassFiller
+	if p.Kind == PassRandom {
+		stream, err := NewRandomStream()
+		if err != nil {
+			return nil, err
+		}
+		filler = stream
+	} else {
+		pattern := p.bytes()
+		if len(pattern) == 0 {
+			return nil, fmt.Errorf("пустой паттерн прохода %s", p)
+		}
+		filler = patternFiller(pattern)
+	}
+
+	if p.Verify {
+		return fullVerifyFiller{filler}, nil
+	}
+	return filler, nil
+}
+
+// patternFiller заполняет буферы повторяющимся паттерном
+type patternFiller []byte
+
+func (f patternFiller) FillAt(buf []byte, offset uint64) error {
+	fillRepeating(buf, f, offset)
+	return nil
+}
+
+// bytes возвращает фактически записываемую последовательность
+func (p PassSpec) bytes() []byte {
+	if p.Kind != PassComplement {
+		return p.Pattern
+	}
+	out := make([]byte, len(p.Pattern))
+	for i, b := range p.Pattern {
+		out[i] = ^b
+	}
+	return out
+}
+
+// String возвращает описание прохода для логов и отчетов
+func (p PassSpec) String() string {
+	var s string
+	if p.Kind == PassRandom {
+		s = "random"
+	} else {
+		parts := make([]string, 0, len(p.Pattern))
+		for _, b := range p.bytes() {
+			parts = append(parts, fmt.Sprintf("0x%02X", b))
+		}
+		s = strings.Join(parts, " ")
+	}
+	if p.Verify {
+		s += " (verify)"
+	}
+	return s
+}
+
+// fillRepeating заполняет buf циклическим паттерном начиная с фазы offset
+func fillRepeating(buf, pattern []byte, offset uint64) {
+	if len(buf) == 0 {
+		return
+	}
+
+	// Первый период учитывает фазу паттерна, дальше удваиваем уже заполненное
+	phase := int(offset % uint64(len(pattern)))
+	n := 0
+	for n < len(buf) && n < len(pattern) {
+		buf[n] = pattern[(phase+n)%len(pattern)]
+		n++
+	}
+	for n < len(buf) {
+		n += copy(buf[n:], buf[:n])
+	}
+}
+
+// PatternScheme именованная схема затирания - последовательность проходов
+type PatternScheme struct {
+	Name        string
+	Description string
+	Passes      []PassSpec
+	ChunkSize   int      // Рекомендуемый размер чанка записи
+	Aliases     []string // Альтернативные имена (например, "zeros")
+}
+
+// Pass возвращает спецификацию прохода (проходы повторяются по кругу)
+func (s *PatternScheme) Pass(pass int) PassSpec {
+	return s.Passes[pass%len(s.Passes)]
+}
+
+// TotalPasses возвращает число проходов затирания при заданном в
+// конфигурации числе passes (ssd_passes, hdd_passes, --passes): проходы
+// схемы повторяются по кругу, а многопроходная схема выполняется не меньше
+// чем целиком
+func (s *PatternScheme) TotalPasses(passes int) int {
+	return max(passes, len(s.Passes))
+}
+
+// PassDescriptions возвращает описания всех проходов схемы
+func (s *PatternScheme) PassDescriptions() []string {
+	descr := make([]string, len(s.Passes))
+	for i, p := range s.Passes {
+		descr[i] = p.String()
+	}
+	return descr
+}
+
+// hasVerifyPass сообщает, есть ли в схеме проходы с проверкой чтением
+func (s *PatternScheme) hasVerifyPass() bool {
+	for _, p := range s.Passes {
+		if p.Verify {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultSchemeChunkSize размер чанка для схем, не задавших свой
+const defaultSchemeChunkSize = 16 * 1024 * 1024
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = make(map[string]*PatternScheme)
+	aliases   = make(map[string]string)
+
+	// configSchemes - схемы из секции methods конфигурации; при повторной
+	// загрузке конфигурации они заменяются целиком
+	configSchemes = make(map[string]bool)
+)
+
+// RegisterScheme добавляет схему в реестр. Проходы-инверсии без явного
+// паттерна разрешаются относительно предыдущего прохода.
+func RegisterScheme(s PatternScheme) error {
+	if err := prepareScheme(&s); err != nil {
+		return err
+	}
+
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	return addSchemeLocked(&s)
+}
+
+// prepareScheme проверяет схему, приводит имя к нижнему регистру и
+// разрешает проходы-инверсии
+func prepareScheme(s *PatternScheme) error {
+	name := strings.ToLower(strings.TrimSpace(s.Name))
+	if name == "" {
+		return fmt.Errorf("пустое имя схемы затирания")
+	}
+	if len(s.Passes) == 0 {
+		return fmt.Errorf("схема %s не содержит проходов", name)
+	}
+
+	passes := make([]PassSpec, len(s.Passes))
+	for i, p := range s.Passes {
+		switch p.Kind {
+		case PassRandom:
+		case PassFixed:
+			if len(p.Pattern) != 1 {
+				return fmt.Errorf("схема %s, проход %d: фиксированный проход требует ровно 1 байт", name, i+1)
+			}
+		case PassRepeat:
+			if len(p.Pattern) == 0 {
+				return fmt.Errorf("схема %s, проход %d: пустая последовательность", name, i+1)
+			}
+		case PassComplement:
+			if len(p.Pattern) == 0 {
+				if i == 0 || passes[i-1].Kind == PassRandom {
+					return fmt.Errorf("схема %s, проход %d: инверсия требует фиксированного предыдущего прохода", name, i+1)
+				}
+				p.Pattern = passes[i-1].bytes()
+			}
+		default:
+			return fmt.Errorf("схема %s, проход %d: неизвестный тип прохода %d", name, i+1, p.Kind)
+		}
+		p.Pattern = append([]byte(nil), p.Pattern...)
+		passes[i] = p
+	}
+
+	s.Name = name
+	s.Passes = passes
+	if s.ChunkSize <= 0 {
+		s.ChunkSize = defaultSchemeChunkSize
+	}
+	return nil
+}
+
+// addSchemeLocked добавляет подготовленную схему; вызывается под schemesMu
+func addSchemeLocked(s *PatternScheme) error {
+	name := s.Name
+	if _, exists := schemes[name]; exists {
+		return fmt.Errorf("схема затирания %s уже зарегистрирована", name)
+	}
+	if _, exists := aliases[name]; exists {
+		return fmt.Errorf("имя %s уже используется как псевдоним", name)
+	}
+	for _, alias := range s.Aliases {
+		alias = strings.ToLower(alias)
+		if _, exists := schemes[alias]; exists {
+			return fmt.Errorf("псевдоним %s совпадает с именем схемы", alias)
+		}
+		if _, exists := aliases[alias]; exists {
+			return fmt.Errorf("псевдоним %s уже зарегистрирован", alias)
+		}
+	}
+
+	schemes[name] = s
+	for _, alias := range s.Aliases {
+		aliases[strings.ToLower(alias)] = name
+	}
+
+	return nil
+}
+
+// replaceConfigSchemes заменяет схемы из конфигурации подготовленными
+// схемами list
+func replaceConfigSchemes(list []PatternScheme) error {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+
+	for name := range configSchemes {
+		delete(schemes, name)
+	}
+	configSchemes = make(map[string]bool)
+	for i := range list {
+		if err := addSchemeLocked(&list[i]); err != nil {
+			return fmt.Errorf("метод %s из секции methods: %w", list[i].Name, err)
+		}
+		configSchemes[list[i].Name] = true
+	}
+	return nil
+}
+
+// mustRegisterScheme регистрирует встроенную схему
+func mustRegisterScheme(s PatternScheme) {
+	if err := RegisterScheme(s); err != nil {
+		panic(err)
+	}
+}
+
+// LookupScheme находит схему по имени или псевдониму
+func LookupScheme(name string) (*PatternScheme, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	s, ok := schemes[name]
+	return s, ok
+}
+
+// Schemes возвращает все зарегистрированные схемы, отсортированные по имени
+func Schemes() []*PatternScheme {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+
+	list := make([]*PatternScheme, 0, len(schemes))
+	for _, s := range schemes {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// SchemeNames возвращает имена зарегистрированных схем
+func SchemeNames() []string {
+	list := Schemes()
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func init() {
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodRandom),
+		Description: "Один проход случайными данными",
+		Passes:      []PassSpec{RandomPass()},
+		ChunkSize:   16 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodZero),
+		Description: "Один проход нулями",
+		Passes:      []PassSpec{FixedPass(0x00)},
+		ChunkSize:   32 * 1024 * 1024, // Нули генерируются быстрее
+		Aliases:     []string{"zeros"},
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodDOD5220),
+		Description: "DoD 5220.22-M (вариант): случайные, нули, случайные",
+		Passes:      []PassSpec{RandomPass(), FixedPass(0x00), RandomPass()},
+		ChunkSize:   8 * 1024 * 1024, // Больше проходов - меньше чанк
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodSDeleteCompat),
+		Description: "Совместимо с SDelete: один проход случайными данными",
+		Passes:      []PassSpec{RandomPass()},
+		ChunkSize:   16 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodCipher),
+		Description: "Как cipher /w: нули, 0xFF, случайные",
+		Passes:      []PassSpec{FixedPass(0x00), ComplementPass(), RandomPass()},
+		ChunkSize:   16 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodGutmann),
+		Description: "Gutmann: 35 проходов (4 случайных, 27 паттернов MFM/RLL, 4 случайных)",
+		Passes:      gutmannPasses(),
+		ChunkSize:   8 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodSchneier),
+		Description: "Bruce Schneier: 0xFF, 0x00, 5 проходов случайными данными",
+		Passes: []PassSpec{
+			FixedPass(0xFF), FixedPass(0x00),
+			RandomPass(), RandomPass(), RandomPass(), RandomPass(), RandomPass(),
+		},
+		ChunkSize: 8 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodVSITR),
+		Description: "BSI VSITR: 6 чередующихся проходов 0x00/0xFF, финальный 0xAA",
+		Passes: []PassSpec{
+			FixedPass(0x00), ComplementPass(),
+			FixedPass(0x00), ComplementPass(),
+			FixedPass(0x00), ComplementPass(),
+			FixedPass(0xAA),
+		},
+		ChunkSize: 8 * 1024 * 1024,
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodHMGIS5),
+		Description: "HMG IS5 Baseline: один проход нулями",
+		Passes:      []PassSpec{FixedPass(0x00)},
+		ChunkSize:   32 * 1024 * 1024,
+		Aliases:     []string{"hmg_is5"},
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodHMGIS5Enhanced),
+		Description: "HMG IS5 Enhanced: 0x00, 0xFF, случайные",
+		Passes:      []PassSpec{FixedPass(0x00), ComplementPass(), RandomPass()},
+		ChunkSize:   16 * 1024 * 1024,
+		Aliases:     []string{"hmg_is5_enhanced"},
+	})
+	mustRegisterScheme(PatternScheme{
+		Name:        string(MethodGOST),
+		Description: "ГОСТ Р 50739-95: нули, случайные",
+		Passes:      []PassSpec{FixedPass(0x00), RandomPass()},
+		ChunkSize:   16 * 1024 * 1024,
+		Aliases:     []string{"gost", "gost_r50739_95"},
+	})
+}
+
+// gutmannPasses возвращает 35 проходов метода Гутмана в порядке из оригинальной статьи
+func gutmannPasses() []PassSpec {
+	passes := []PassSpec{RandomPass(), RandomPass(), RandomPass(), RandomPass()}
+
+	passes = append(passes,
+		FixedPass(0x55),
+		FixedPass(0xAA),
+		RepeatPass(0x92, 0x49, 0x24),
+		RepeatPass(0x49, 0x24, 0x92),
+		RepeatPass(0x24, 0x92, 0x49),
+	)
+
+	// Проходы 10-25: 0x00, 0x11, ..., 0xFF
+	for b := 0x00; b <= 0xFF; b += 0x11 {
+		passes = append(passes, FixedPass(byte(b)))
+	}
+
+	passes = append(passes,
+		RepeatPass(0x92, 0x49, 0x24),
+		RepeatPass(0x49, 0x24, 0x92),
+		RepeatPass(0x24, 0x92, 0x49),
+		RepeatPass(0x6D, 0xB6, 0xDB),
+		RepeatPass(0xB6, 0xDB, 0x6D),
+		RepeatPass(0xDB, 0x6D, 0xB6),
+	)
+
+	return append(passes, RandomPass(), RandomPass(), RandomPass(), RandomPass())
+}
@@ -0,0 +1,198 @@
+package wipe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		lookup   string
+		wantName string
+		wantOK   bool
+	}{
+		{"имя", "random", "random", true},
+		{"регистр и пробелы", "  DOD5220 ", "dod5220", true},
+		{"псевдоним", "zeros", "zero", true},
+		{"псевдоним в верхнем регистре", "GOST", "gost-r50739-95", true},
+		{"неизвестный метод", "shred", "", false},
+		{"пустое имя", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := LookupScheme(tt.lookup)
+			if ok != tt.wantOK {
+				t.Fatalf("LookupScheme(%q): ok = %v, want %v", tt.lookup, ok, tt.wantOK)
+			}
+			if ok && s.Name != tt.wantName {
+				t.Errorf("LookupScheme(%q) = %q, want %q", tt.lookup, s.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestRegisterSchemeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme PatternScheme
+		want   string
+	}{
+		{"пустое имя", PatternScheme{Name: " ", Passes: []PassSpec{RandomPass()}}, "пустое имя"},
+		{"без проходов", PatternScheme{Name: "test-empty"}, "не содержит проходов"},
+		{"фиксированный проход из двух байт", PatternScheme{Name: "test-fixed", Passes: []PassSpec{RepeatPass(1, 2), {Kind: PassFixed, Pattern: []byte{1, 2}}}}, "ровно 1 байт"},
+		{"пустая последовательность", PatternScheme{Name: "test-repeat", Passes: []PassSpec{RepeatPass()}}, "пустая последовательность"},
+		{"инверсия первым проходом", PatternScheme{Name: "test-compl", Passes: []PassSpec{ComplementPass()}}, "инверсия требует"},
+		{"инверсия после случайного", PatternScheme{Name: "test-compl", Passes: []PassSpec{RandomPass(), ComplementPass()}}, "инверсия требует"},
+		{"занятое имя", PatternScheme{Name: "Random", Passes: []PassSpec{RandomPass()}}, "уже зарегистрирована"},
+		{"имя совпадает с псевдонимом", PatternScheme{Name: "zeros", Passes: []PassSpec{FixedPass(0)}}, "псевдоним"},
+		{"занятый псевдоним", PatternScheme{Name: "test-alias", Passes: []PassSpec{FixedPass(0)}, Aliases: []string{"Zeros"}}, "уже зарегистрирован"},
+	}
+
+	registered := SchemeNames()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterScheme(tt.scheme)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RegisterScheme: ошибка %v, want %q", err, tt.want)
+			}
+			if got := SchemeNames(); !reflect.DeepEqual(got, registered) {
+				t.Errorf("схема с ошибкой попала в реестр: %v", got)
+			}
+		})
+	}
+}
+
+func TestPrepareSchemeComplement(t *testing.T) {
+	tests := []struct {
+		name   string
+		passes []PassSpec
+		want   []string
+	}{
+		{"инверсия предыдущего байта", []PassSpec{FixedPass(0x00), ComplementPass()}, []string{"0x00", "0xFF"}},
+		{"инверсия последовательности", []PassSpec{RepeatPass(0x92, 0x49), ComplementPass()}, []string{"0x92 0x49", "0x6D 0xB6"}},
+		{"явный паттерн инверсии", []PassSpec{RandomPass(), ComplementPass(0x0F)}, []string{"random", "0xF0"}},
+		{"цепочка инверсий", []PassSpec{FixedPass(0x55), ComplementPass(), ComplementPass()}, []string{"0x55", "0xAA", "0x55"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := PatternScheme{Name: "Test", Passes: tt.passes}
+			if err := prepareScheme(&s); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.PassDescriptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PassDescriptions() = %v, want %v", got, tt.want)
+			}
+			if s.Name != "test" || s.ChunkSize != defaultSchemeChunkSize {
+				t.Errorf("Name %q, ChunkSize %d", s.Name, s.ChunkSize)
+			}
+		})
+	}
+}
+
+func TestSchemePassCycle(t *testing.T) {
+	tests := []struct {
+		method      WipeMethod
+		passes      int
+		wantTotal   int
+		wantPattern []string
+	}{
+		{MethodDOD5220, 0, 3, []string{"random", "0x00", "random"}},
+		{MethodDOD5220, 1, 3, []string{"random", "0x00", "random"}},
+		{MethodDOD5220, 3, 3, []string{"random", "0x00", "random"}},
+		{MethodDOD5220, 5, 5, []string{"random", "0x00", "random", "random", "0x00"}},
+		{MethodCipher, 1, 3, []string{"0x00", "0xFF", "random"}},
+		{MethodZero, 2, 2, []string{"0x00", "0x00"}},
+	}
+
+	for _, tt := range tests {
+		s, _ := LookupScheme(string(tt.method))
+		total := s.TotalPasses(tt.passes)
+		if total != tt.wantTotal {
+			t.Errorf("%s: TotalPasses(%d) = %d, want %d", tt.method, tt.passes, total, tt.wantTotal)
+			continue
+		}
+		got := make([]string, total)
+		for i := range got {
+			got[i] = s.Pass(i).String()
+		}
+		if !reflect.DeepEqual(got, tt.wantPattern) {
+			t.Errorf("%s: проходы при passes=%d: %v, want %v", tt.method, tt.passes, got, tt.wantPattern)
+		}
+	}
+}
+
+func TestBuiltinSchemePasses(t *testing.T) {
+	gutmann := []string{"random", "random", "random", "random", "0x55", "0xAA", "0x92 0x49 0x24", "0x49 0x24 0x92", "0x24 0x92 0x49"}
+	for b := 0x00; b <= 0xFF; b += 0x11 {
+		gutmann = append(gutmann, RepeatPass(byte(b)).String())
+	}
+	gutmann = append(gutmann, "0x92 0x49 0x24", "0x49 0x24 0x92", "0x24 0x92 0x49", "0x6D 0xB6 0xDB", "0xB6 0xDB 0x6D", "0xDB 0x6D 0xB6",
+		"random", "random", "random", "random")
+
+	tests := []struct {
+		method WipeMethod
+		want   []string
+	}{
+		{MethodRandom, []string{"random"}},
+		{MethodZero, []string{"0x00"}},
+		{MethodDOD5220, []string{"random", "0x00", "random"}},
+		{MethodCipher, []string{"0x00", "0xFF", "random"}},
+		{MethodGutmann, gutmann},
+		{MethodSchneier, []string{"0xFF", "0x00", "random", "random", "random", "random", "random"}},
+		{MethodVSITR, []string{"0x00", "0xFF", "0x00", "0xFF", "0x00", "0xFF", "0xAA"}},
+		{MethodHMGIS5, []string{"0x00"}},
+		{MethodHMGIS5Enhanced, []string{"0x00", "0xFF", "random"}},
+		{MethodGOST, []string{"0x00", "random"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			s, ok := LookupScheme(string(tt.method))
+			if !ok {
+				t.Fatalf("схема %s не зарегистрирована", tt.method)
+			}
+			if got := s.PassDescriptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("проходы %v\nwant %v", got, tt.want)
+			}
+			if got := GetMethodPasses(tt.method); got != len(tt.want) {
+				t.Errorf("GetMethodPasses = %d, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestPassSpecFillAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		pass   PassSpec
+		offset uint64
+		size   int
+		want   []byte
+	}{
+		{"байт", FixedPass(0xAA), 7, 4, []byte{0xAA, 0xAA, 0xAA, 0xAA}},
+		{"последовательность с начала", RepeatPass(1, 2, 3), 0, 7, []byte{1, 2, 3, 1, 2, 3, 1}},
+		{"фаза последовательности по смещению", RepeatPass(1, 2, 3), 4, 5, []byte{2, 3, 1, 2, 3}},
+		{"инверсия последовательности", ComplementPass(0x00, 0xF0), 1, 3, []byte{0x0F, 0xFF, 0x0F}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filler, err := tt.pass.NewFiller()
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := make([]byte, tt.size)
+			if err := filler.FillAt(buf, tt.offset); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, tt.want) {
+				t.Errorf("FillAt(%d) = % x, want % x", tt.offset, buf, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package wipe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PauseController приостанавливает и возобновляет запущенное затирание.
+// Писатель замирает на границе блока, сбрасывает и закрывает файл и ждет
+// возобновления, сохраняя буферы из пула; после возобновления файл
+// открывается снова и запись продолжается с того же места. Время паузы не
+// входит в max_duration (см. WithDuration). Методы безопасно вызывать у nil.
+//
+// Пауза действует, пока ее удерживает хотя бы одна причина: оператор
+// (Pause/Resume) или расписание окон обслуживания. Возобновление оператором
+// вне окна не запускает запись.
+type PauseController struct {
+	mu      sync.Mutex
+	reasons pauseReason   // Действующие причины паузы
+	resumed chan struct{} // Закрывается при возобновлении
+	since   time.Time     // Начало текущей паузы
+	total   time.Duration // Длительность завершенных пауз
+	spans   []pauseSpan   // Завершенные паузы
+}
+
+// pauseReason причина паузы (битовая маска)
+type pauseReason uint8
+
+const (
+	pauseManual   pauseReason = 1 << iota // Пауза оператором
+	pauseSchedule                         // Вне окна обслуживания
+)
+
+// pauseSpan завершенная пауза
+type pauseSpan struct {
+	from, to time.Time
+}
+
+// ActiveInterval интервал, в течение которого затирание выполнялось
+type ActiveInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// NewPauseController создает контроллер паузы
+func NewPauseController() *PauseController {
+	return &PauseController{}
+}
+
+// Pause приостанавливает затирание; false - оператор уже приостановил его
+func (c *PauseController) Pause() bool {
+	return c.hold(pauseManual)
+}
+
+// Resume снимает паузу оператора; false - оператор не приостанавливал
+// затирание. Вне окна обслуживания запись продолжится при открытии окна.
+func (c *PauseController) Resume() bool {
+	return c.release(pauseManual)
+}
+
+// hold устанавливает причину паузы; false - причина уже действовала
+func (c *PauseController) hold(reason pauseReason) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reasons&reason != 0 {
+		return false
+	}
+	if c.reasons == 0 {
+		c.resumed = make(chan struct{})
+		c.since = time.Now()
+	}
+	c.reasons |= reason
+	return true
+}
+
+// release снимает причину паузы; запись возобновляется, когда причин не
+// осталось. false - причина не действовала.
+func (c *PauseController) release(reason pauseReason) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reasons&reason == 0 {
+		return false
+	}
+	c.reasons &^= reason
+	if c.reasons == 0 {
+		now := time.Now()
+		c.total += now.Sub(c.since)
+		c.spans = append(c.spans, pauseSpan{from: c.since, to: now})
+		close(c.resumed)
+	}
+	return true
+}
+
+// Paused сообщает, действует ли пауза
+func (c *PauseController) Paused() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reasons != 0
+}
+
+// PausedTime возвращает суммарную длительность пауз, включая текущую
+func (c *PauseController) PausedTime() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reasons != 0 {
+		return c.total + time.Since(c.since)
+	}
+	return c.total
+}
+
+// ActiveIntervals возвращает интервалы работы внутри [start, end] за
+// вычетом пауз; у nil-контроллера это весь интервал
+func (c *PauseController) ActiveIntervals(start, end time.Time) []ActiveInterval {
+	if c == nil {
+		return []ActiveInterval{{Start: start, End: end}}
+	}
+	c.mu.Lock()
+	spans := append([]pauseSpan(nil), c.spans...)
+	if c.reasons != 0 {
+		spans = append(spans, pauseSpan{from: c.since, to: end})
+	}
+	c.mu.Unlock()
+
+	var intervals []ActiveInterval
+	from := start
+	for _, span := range spans {
+		if !span.to.After(from) || !span.from.Before(end) {
+			continue
+		}
+		if span.from.After(from) {
+			intervals = append(intervals, ActiveInterval{Start: from, End: span.from})
+		}
+		from = span.to
+	}
+	if end.After(from) {
+		intervals = append(intervals, ActiveInterval{Start: from, End: end})
+	}
+	return intervals
+}
+
+// activeDuration возвращает суммарную длительность интервалов
+func activeDuration(intervals []ActiveInterval) time.Duration {
+	var total time.Duration
+	for _, i := range intervals {
+		total += i.End.Sub(i.Start)
+	}
+	return total
+}
+
+// wait блокируется, пока действует пауза; отмена ctx прерывает ожидание
+func (c *PauseController) wait(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	resumed := c.resumed
+	paused := c.reasons != 0
+	c.mu.Unlock()
+	if !paused {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// holdPause ждет окончания паузы, отмечая ее в прогрессе
+func holdPause(ctx context.Context, c *PauseController, progress *progressTracker) error {
+	if !c.Paused() {
+		return nil
+	}
+	progress.pause()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	progress.resume()
+	return nil
+}
+
+// WithDuration возвращает контекст, который отменяется с причиной
+// context.DeadlineExceeded, когда время работы без учета пауз превысит d.
+// У nil-контроллера это обычный context.WithTimeout.
+func (c *PauseController) WithDuration(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if c == nil {
+		return context.WithTimeout(parent, d)
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	start, base := time.Now(), c.PausedTime()
+
+	go func() {
+		for {
+			c.mu.Lock()
+			resumed, paused := c.resumed, c.reasons != 0
+			c.mu.Unlock()
+			if paused {
+				select {
+				case <-resumed:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			left := d - (time.Since(start) - (c.PausedTime() - base))
+			if left <= 0 {
+				cancel(context.DeadlineExceeded)
+				return
+			}
+			timer := time.NewTimer(left)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return ctx, func() { cancel(context.Canceled) }
+}
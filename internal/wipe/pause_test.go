@@ -0,0 +1,288 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+)
+
+func TestPauseController(t *testing.T) {
+	tests := []struct {
+		name       string
+		controller *PauseController
+		steps      []string // pause / resume
+		want       []bool   // Результат каждого шага
+		wantPaused bool
+	}{
+		{"пауза", NewPauseController(), []string{"pause"}, []bool{true}, true},
+		{"повторная пауза", NewPauseController(), []string{"pause", "pause"}, []bool{true, false}, true},
+		{"возобновление", NewPauseController(), []string{"pause", "resume"}, []bool{true, true}, false},
+		{"возобновление без паузы", NewPauseController(), []string{"resume"}, []bool{false}, false},
+		{"повторный цикл", NewPauseController(), []string{"pause", "resume", "pause"}, []bool{true, true, true}, true},
+		{"nil", nil, []string{"pause", "resume"}, []bool{false, false}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.controller
+			for i, step := range tt.steps {
+				var got bool
+				if step == "pause" {
+					got = c.Pause()
+				} else {
+					got = c.Resume()
+				}
+				if got != tt.want[i] {
+					t.Errorf("шаг %d (%s): %v, want %v", i, step, got, tt.want[i])
+				}
+			}
+			if c.Paused() != tt.wantPaused {
+				t.Errorf("Paused() = %v, want %v", c.Paused(), tt.wantPaused)
+			}
+
+			// wait возвращается сразу без паузы и по отмене ctx во время паузы
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := c.wait(ctx)
+			if tt.wantPaused != (err != nil) {
+				t.Errorf("wait: %v при паузе %v", err, tt.wantPaused)
+			}
+		})
+	}
+}
+
+func TestPauseWithDuration(t *testing.T) {
+	const limit = 100 * time.Millisecond
+	tests := []struct {
+		name      string
+		pause     time.Duration // Пауза сразу после запуска (0 - без паузы)
+		wantAfter time.Duration // Не раньше этого времени контекст истекает
+	}{
+		{"без паузы", 0, limit},
+		{"пауза не входит в лимит", 150 * time.Millisecond, limit + 150*time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewPauseController()
+			start := time.Now()
+			ctx, cancel := c.WithDuration(context.Background(), limit)
+			defer cancel()
+			if tt.pause > 0 {
+				c.Pause()
+				time.AfterFunc(tt.pause, func() { c.Resume() })
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("контекст не истек")
+			}
+			if elapsed := time.Since(start); elapsed < tt.wantAfter {
+				t.Errorf("истек через %v, want не раньше %v", elapsed, tt.wantAfter)
+			}
+			if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+				t.Errorf("причина %v, want DeadlineExceeded", context.Cause(ctx))
+			}
+		})
+	}
+}
+
+func TestHoldPauseProgress(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		name       string
+		pause      time.Duration
+		wantPaused bool // Отправлен прогресс с отметкой паузы
+	}{
+		{"без паузы", 0, false},
+		{"пауза", 100 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan ProgressInfo, 16)
+			p := newProgressTracker(ch, "D:\\", time.Now())
+			p.plan(1, 0, 100*mb)
+			c := NewPauseController()
+			if tt.pause > 0 {
+				c.Pause()
+				time.AfterFunc(tt.pause, func() { c.Resume() })
+			}
+
+			before := time.Now()
+			if err := holdPause(context.Background(), c, p); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantPaused && p.last.Before(before.Add(tt.pause)) {
+				t.Error("время паузы осталось в замере скорости")
+			}
+
+			// Скорость и ETA считаются только по времени записи
+			p.add(10 * mb)
+			p.sample(p.last.Add(time.Second))
+			info := p.info()
+			if info.SpeedMBps != 10 || info.EstimatedTime != 9*time.Second {
+				t.Errorf("скорость %.2f МБ/с, ETA %v; want 10 МБ/с и 9s", info.SpeedMBps, info.EstimatedTime)
+			}
+
+			var gotPaused bool
+			for len(ch) > 0 {
+				gotPaused = gotPaused || (<-ch).Paused
+			}
+			if gotPaused != tt.wantPaused {
+				t.Errorf("отметка паузы %v, want %v", gotPaused, tt.wantPaused)
+			}
+		})
+	}
+}
+
+func TestWipeDevicePause(t *testing.T) {
+	const imageSize = 64 * DirectIOAlignment
+	tests := []struct {
+		name   string
+		pause  time.Duration
+		cancel bool // Отмена во время паузы
+	}{
+		{"возобновление", 100 * time.Millisecond, false},
+		{"отмена во время паузы", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "disk.img")
+			if err := os.WriteFile(path, bytes.Repeat([]byte{0xA5}, imageSize), 0644); err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := NewPauseController()
+			c.Pause()
+			if tt.cancel {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			} else {
+				time.AfterFunc(tt.pause, func() { c.Resume() })
+			}
+
+			progress := make(chan ProgressInfo, 64)
+			op := WipeDevice(ctx, path, DeviceWipeOptions{
+				Method:   MethodZero,
+				Pause:    c,
+				Progress: progress,
+				Verifier: NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 1}, nil, newTestLogger(t)),
+			}, newTestLogger(t))
+			close(progress)
+
+			var gotPaused, gotDone bool
+			for info := range progress {
+				gotPaused = gotPaused || info.Paused
+				gotDone = gotDone || info.Done
+			}
+			if !gotPaused || !gotDone {
+				t.Errorf("прогресс: пауза %v, итог %v", gotPaused, gotDone)
+			}
+
+			if tt.cancel {
+				if op.Status != "CANCELLED" {
+					t.Errorf("статус %s, want CANCELLED", op.Status)
+				}
+				return
+			}
+			if op.Status != "COMPLETED" || op.BytesWiped != imageSize {
+				t.Fatalf("статус %s, записано %d; ошибка %q", op.Status, op.BytesWiped, op.Error)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, make([]byte, imageSize)) {
+				t.Error("образ не затерт после возобновления")
+			}
+			if active := op.EndTime.Sub(op.StartTime) - tt.pause; op.SpeedMBps < float64(imageSize)/(1024*1024)/active.Seconds()*0.99 {
+				t.Errorf("скорость %.2f МБ/с учитывает паузу", op.SpeedMBps)
+			}
+		})
+	}
+}
+
+func TestPauseReasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		steps      []string // pause/resume - оператор, close/open - окно обслуживания
+		wantPaused bool
+	}{
+		{"вне окна", []string{"close"}, true},
+		{"окно открылось", []string{"close", "open"}, false},
+		{"возобновление оператором вне окна", []string{"close", "pause", "resume"}, true},
+		{"окно открылось во время паузы оператора", []string{"pause", "close", "open"}, true},
+		{"все причины сняты", []string{"pause", "close", "resume", "open"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewPauseController()
+			for _, step := range tt.steps {
+				switch step {
+				case "pause":
+					c.Pause()
+				case "resume":
+					c.Resume()
+				case "close":
+					c.hold(pauseSchedule)
+				case "open":
+					c.release(pauseSchedule)
+				}
+			}
+			if c.Paused() != tt.wantPaused {
+				t.Errorf("Paused() = %v, want %v", c.Paused(), tt.wantPaused)
+			}
+		})
+	}
+}
+
+func TestActiveIntervals(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	tests := []struct {
+		name       string
+		controller *PauseController
+		start, end time.Time
+		want       []ActiveInterval
+	}{
+		{"nil-контроллер", nil, at(0), at(60), []ActiveInterval{{at(0), at(60)}}},
+		{"без пауз", &PauseController{}, at(0), at(60), []ActiveInterval{{at(0), at(60)}}},
+		{"пауза внутри", &PauseController{spans: []pauseSpan{{at(10), at(20)}}}, at(0), at(60),
+			[]ActiveInterval{{at(0), at(10)}, {at(20), at(60)}}},
+		{"пауза до запуска", &PauseController{spans: []pauseSpan{{at(-20), at(-10)}, {at(30), at(40)}}}, at(0), at(60),
+			[]ActiveInterval{{at(0), at(30)}, {at(40), at(60)}}},
+		{"запуск во время паузы", &PauseController{spans: []pauseSpan{{at(-10), at(15)}}}, at(0), at(60),
+			[]ActiveInterval{{at(15), at(60)}}},
+		{"пауза действует при завершении", &PauseController{reasons: pauseSchedule, since: at(50)}, at(0), at(60),
+			[]ActiveInterval{{at(0), at(50)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.controller.ActiveIntervals(tt.start, tt.end)
+			if len(got) != len(tt.want) {
+				t.Fatalf("интервалы %v, want %v", got, tt.want)
+			}
+			var wantTotal time.Duration
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("интервал %d: %v, want %v", i, got[i], tt.want[i])
+				}
+				wantTotal += tt.want[i].End.Sub(tt.want[i].Start)
+			}
+			if total := activeDuration(got); total != wantTotal {
+				t.Errorf("activeDuration = %v, want %v", total, wantTotal)
+			}
+		})
+	}
+}
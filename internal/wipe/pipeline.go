@@ -0,0 +1,379 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// minWipeFileSize - наименьший файл, который создается в конце прохода
+const minWipeFileSize = 64 * 1024 * 1024
+
+// ErrDiskFull - том заполнен. Для конвейера это штатное окончание прохода.
+var ErrDiskFull = errors.New("недостаточно места на диске")
+
+// FileWriter создает один файл затирания и записывает в него данные прохода
+type FileWriter interface {
+	// WriteFile записывает size байт потока filler, начиная со смещения
+	// baseOffset, в новый файл filename и возвращает число записанных байт.
+	// При заполнении тома возвращается ErrDiskFull и уже записанный объем.
+	WriteFile(ctx context.Context, filename string, size uint64, filler PassFiller, baseOffset uint64) (uint64, error)
+}
+
+// RunFreeSpaceWipe - единый конвейер затирания свободного места тома или
+// каталога disk.Path внутри него: файлы затирания создаются только в нем.
+// Через него работают CLI и обслуживание (WipeWithStrategy), WipeFreeSpace
+// и WipeEngine графического интерфейса. Размер файлов, интервал синхронизации
+// и запас места задает strategy, проходы - метод cfg.Method, запись файлов -
+// cfg.Writer.
+//
+// Файлы прохода держатся до его конца, чтобы данные заняли все свободное
+// место, и удаляются после прохода или при прерывании. Заполнение тома
+// завершает проход штатно; отмена дает CANCELLED, истечение времени - PARTIAL.
+func RunFreeSpaceWipe(ctx context.Context, disk system.DiskInfo, strategy WipeStrategy, cfg *WipeConfig, logger *logging.EnterpriseLogger) *WipeOperation {
+	op := &WipeOperation{
+		ID:        fmt.Sprintf("wipe_%d", time.Now().UnixNano()),
+		Disk:      disk.Letter,
+		Path:      disk.Path,
+		Method:    string(cfg.Method),
+		ChunkSize: int64(strategy.GetFileSize(disk.Type, cfg.Profile)),
+		Status:    "RUNNING",
+		StartTime: time.Now(),
+	}
+	var runWritten uint64
+	parent := ctx // Итог прогресса доставляется и после истечения MaxDuration
+	cfg.progress = newProgressTracker(cfg.Progress, disk.Target(), op.StartTime)
+	defer func() {
+		now := time.Now()
+		op.EndTime = &now
+		op.ActiveIntervals = cfg.Pause.ActiveIntervals(op.StartTime, now)
+		if elapsed := activeDuration(op.ActiveIntervals).Seconds(); elapsed > 0 && !cfg.DryRun {
+			op.SpeedMBps = float64(runWritten) / (1024 * 1024) / elapsed
+		}
+		op.recordVerification(cfg.Verifier)
+		cfg.Checkpoint.finishRun(op, runWritten, logger)
+		cfg.progress.finish(parent, op)
+	}()
+
+	fail := func(err error) *WipeOperation {
+		op.Status = "FAILED"
+		op.Error = err.Error()
+		logger.Log("ERROR", "Затирание не выполнено", "disk", disk.Letter, "error", op.Error)
+		return op
+	}
+
+	scheme := cfg.scheme
+	if scheme == nil {
+		s, ok := LookupScheme(string(cfg.Method))
+		if !ok {
+			return fail(fmt.Errorf("неизвестный метод затирания: %s", cfg.Method))
+		}
+		scheme = s
+	}
+	op.Method = scheme.Name
+	cfg.Verifier = passVerifier(cfg.Verifier, scheme, cfg.Marker, logger)
+
+	passes := scheme.TotalPasses(cfg.Passes)
+	if cfg.Checkpoint != nil && cfg.Checkpoint.Passes > 0 {
+		passes = cfg.Checkpoint.Passes // Продолжение: число проходов из журнала
+	}
+	op.Passes = passes
+
+	logger.Log("INFO", "Запуск затирания", "disk", disk.Letter, "path", disk.Path, "method", scheme.Name, "passes", passes,
+		"profile", cfg.Profile, "strategy", fmt.Sprintf("%T", strategy))
+
+	if cfg.DryRun {
+		op.Status = "COMPLETED"
+		op.BytesWiped = disk.FreeSize
+		logger.Log("INFO", "DRY RUN: затирание завершено", "disk", disk.Letter, "bytes", op.BytesWiped)
+		return op
+	}
+
+	if cfg.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = cfg.Pause.WithDuration(ctx, cfg.MaxDuration)
+		defer cancel()
+	}
+
+	root := disk.WipeRoot()
+	if err := checkWritable(root); err != nil {
+		return fail(fmt.Errorf("диск недоступен для записи: %w", err))
+	}
+
+	// Один лимитер на всю операцию: бюджет скорости не сбрасывается с каждым файлом
+	if cfg.Limiter == nil {
+		cfg.Limiter = NewRateLimiter(cfg.MaxSpeedMBps, nil)
+	}
+	stopAdaptive := startAdaptiveThrottle(ctx, cfg.Adaptive, cfg.MaxSpeedMBps, cfg.Limiter, disk.Letter, logger)
+	defer stopAdaptive()
+
+	// Продолжение с контрольной точки. Файлы держатся до конца прохода,
+	// поэтому уцелевшие после перезагрузки файлы засчитываются, а свободное
+	// место (disk.FreeSize) уже измерено с их учетом.
+	cfg.Checkpoint.removeLeftovers(true, logger)
+	startPass, cursor, records := cfg.Checkpoint.resumeState()
+	op.PassRecords = records
+	if startPass > 0 || cursor.FileIndex > 0 {
+		logger.Log("INFO", "Продолжение затирания с контрольной точки", "disk", disk.Letter,
+			"pass", startPass+1, "total", passes, "file_index", cursor.FileIndex, "pass_bytes", cursor.Bytes)
+	}
+	cfg.Checkpoint.beginRun(scheme.Name, passes, logger)
+	cfg.discard = newDiscarder(cfg.EnableTrim, logger)
+
+	p := &freeSpacePass{
+		disk:     disk,
+		root:     root,
+		strategy: strategy,
+		scheme:   scheme,
+		cfg:      cfg,
+		writer:   cfg.Writer,
+		capacity: disk.FreeSize + cursor.Bytes,
+		logger:   logger,
+	}
+	if p.writer == nil {
+		p.writer = newFileWriter(cfg, strategy, scheme, logger)
+	}
+	cfg.progress.plan(passes, startPass, p.target())
+
+	for pass := startPass; pass < passes; pass++ {
+		if ctx.Err() != nil {
+			op.interrupt(context.Cause(ctx))
+			break
+		}
+
+		spec := scheme.Pass(pass)
+		record := op.beginPass(PhaseOverwrite, spec.String())
+
+		// Позиция внутри прохода восстанавливается только для первого прохода запуска
+		start := passCursor{}
+		var kept []string
+		if pass == startPass {
+			start = cursor
+			kept = cfg.Checkpoint.keptFiles()
+		}
+
+		cfg.progress.beginPass(pass, start.Bytes)
+		written, files, err := p.run(ctx, pass, spec, start, kept)
+		runWritten += written
+		record.Files = files
+		logger.Log("INFO", "Проход завершен", "disk", disk.Letter, "pass", pass+1, "total", passes,
+			"pattern", spec.String(), "bytes", start.Bytes+written, "error", err)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				op.interrupt(context.Cause(ctx))
+			} else {
+				op.Status = "FAILED"
+				op.Error = err.Error()
+			}
+			record.finish(start.Bytes+written, op.Status, err)
+			break
+		}
+		record.finish(start.Bytes+written, "COMPLETED", nil)
+		cfg.progress.endPass(start.Bytes + written)
+		cfg.Checkpoint.passDone(pass+1, op.PassRecords, logger)
+	}
+
+	for _, r := range op.PassRecords {
+		if r.Phase == PhaseOverwrite {
+			op.BytesWiped += r.BytesWritten
+		}
+	}
+
+	if op.Status != "RUNNING" {
+		return op
+	}
+
+	// Очистка метаданных и TRIM тоже пишут на диск: вне окна обслуживания ждем его открытия
+	if err := holdPause(ctx, cfg.Pause, cfg.progress); err != nil {
+		op.interrupt(context.Cause(ctx))
+		return op
+	}
+
+	op.scrubMetadataPhase(ctx, root, cfg.MetadataScrub, cfg.Marker, logger)
+	if cfg.EnableTrim {
+		op.Trim = performTrim(ctx, disk.Letter, cfg.discard, logger)
+	}
+
+	op.Status = "COMPLETED"
+	logger.Log("INFO", "Затирание завершено", "disk", disk.Letter, "bytes", op.BytesWiped,
+		"limit_mbps", cfg.Limiter.Rate(), "effective_mbps", cfg.Limiter.Throughput())
+	return op
+}
+
+// interrupt отмечает операцию, прерванную отменой или истечением времени
+func (op *WipeOperation) interrupt(err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		op.Status = "PARTIAL"
+		op.Warning = "Операция прервана по таймауту"
+	} else {
+		op.Status = "CANCELLED"
+		op.Warning = "Операция отменена пользователем"
+	}
+}
+
+// err возвращает ошибку незавершенной операции (nil - операция завершена)
+func (op *WipeOperation) err() error {
+	switch op.Status {
+	case "COMPLETED", "RUNNING":
+		return nil
+	case "FAILED":
+		return errors.New(op.Error)
+	default:
+		return errors.New(op.Warning)
+	}
+}
+
+// checkWritable проверяет, что в каталоге root можно создавать файлы
+func checkWritable(root string) error {
+	f, err := os.CreateTemp(root, ".wipedisk_test_*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// freeSpacePass выполняет проходы конвейера по одному тому
+type freeSpacePass struct {
+	disk     system.DiskInfo
+	root     string // Каталог файлов затирания
+	strategy WipeStrategy
+	scheme   *PatternScheme
+	cfg      *WipeConfig
+	writer   FileWriter
+	capacity uint64 // Свободное место тома (каталога) без файлов затирания
+	logger   *logging.EnterpriseLogger
+}
+
+// target оценивает объем записи одного прохода
+func (p *freeSpacePass) target() uint64 {
+	reserve := p.strategy.GetMinFreeSpace()
+	if p.capacity <= reserve {
+		return 0
+	}
+	fileSize := p.strategy.GetFileSize(p.disk.Type, p.cfg.Profile)
+	return min(p.capacity-reserve, fileSize*uint64(p.strategy.GetMaxFiles()))
+}
+
+// run выполняет один проход: создает файлы до заполнения свободного места
+// и удаляет их по окончании. start - позиция продолжения прерванного
+// прохода, kept - его уцелевшие файлы. Возвращает байты, записанные в этом
+// запуске, и число файлов прохода.
+func (p *freeSpacePass) run(ctx context.Context, pass int, spec PassSpec, start passCursor, kept []string) (uint64, int, error) {
+	files := append([]string(nil), kept...)
+	defer func() {
+		for _, f := range files {
+			if err := p.cfg.discard.remove(f); err != nil && !os.IsNotExist(err) {
+				p.logger.Log("WARN", "Ошибка удаления файла", "file", f, "error", err.Error())
+			}
+		}
+	}()
+
+	// Один источник данных на весь проход: случайный поток не повторяется между файлами
+	filler, err := spec.NewFiller()
+	if err != nil {
+		return 0, len(files), fmt.Errorf("ошибка генерации паттерна: %w", err)
+	}
+
+	reserve := p.strategy.GetMinFreeSpace()
+	fileSize := p.strategy.GetFileSize(p.disk.Type, p.cfg.Profile)
+	fileIndex := start.FileIndex
+	passBytes := start.Bytes
+	remaining := p.capacity - min(passBytes, p.capacity)
+	var passWritten uint64
+
+	for remaining > reserve && fileIndex < p.strategy.GetMaxFiles() {
+		if err := holdPause(ctx, p.cfg.Pause, p.cfg.progress); err != nil {
+			return passWritten, len(files), err
+		}
+		if err := ctx.Err(); err != nil {
+			return passWritten, len(files), err
+		}
+
+		size := min(fileSize, remaining-reserve)
+		if size < fileSize && size < minWipeFileSize {
+			break
+		}
+
+		filename := p.fileName(fileIndex, pass)
+		p.cfg.Checkpoint.beginFile(filename, p.logger)
+		p.cfg.progress.beginFile(filename)
+		files = append(files, filename)
+		written, err := p.writer.WriteFile(ctx, filename, size, filler, passBytes)
+		passWritten += written
+		passBytes += written
+		fileIndex++
+		p.cfg.progress.endFile(passBytes)
+
+		if err != nil && (errors.Is(err, ErrDiskFull) || isDiskFullError(err)) {
+			p.cfg.Checkpoint.endFile(passCursor{FileIndex: fileIndex, Bytes: passBytes}, true, p.logger)
+			p.logger.Log("INFO", "Свободное место исчерпано", "disk", p.disk.Letter, "pass", pass+1, "file", filename)
+			break
+		}
+		if err != nil {
+			return passWritten, len(files), fmt.Errorf("ошибка создания файла %s: %w", filename, err)
+		}
+		p.cfg.Checkpoint.endFile(passCursor{FileIndex: fileIndex, Bytes: passBytes}, true, p.logger)
+		remaining -= min(written, remaining)
+
+		// Пауза между файлами для снижения нагрузки
+		if p.cfg.FileDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return passWritten, len(files), ctx.Err()
+			case <-time.After(p.cfg.FileDelay):
+			}
+		}
+	}
+
+	return passWritten, len(files), nil
+}
+
+// fileName возвращает имя файла затирания; у схемы cipher в имени указан проход
+func (p *freeSpacePass) fileName(index, pass int) string {
+	if p.scheme.Name == string(MethodCipher) {
+		cipherPass := CipherPass(pass % len(p.scheme.Passes))
+		return filepath.Join(p.root, fmt.Sprintf("cipher_%03d_%s.tmp", index, cipherPass.String()))
+	}
+	return filepath.Join(p.root, fmt.Sprintf("wipe_%03d.tmp", index))
+}
+
+// newFileWriter создает запись файлов для конвейера без cfg.Writer. Тесты
+// подменяют ее, чтобы сравнить точки входа без записи на диск.
+var newFileWriter = func(cfg *WipeConfig, strategy WipeStrategy, scheme *PatternScheme, logger *logging.EnterpriseLogger) FileWriter {
+	w := &patternWriter{cfg: cfg, syncInterval: strategy.GetSyncInterval(), chunkSize: scheme.ChunkSize, logger: logger}
+	if cfg.ChunkSize > 0 {
+		w.chunkSize = cfg.ChunkSize
+	}
+	if cfg.SyncInterval > 0 {
+		w.syncInterval = cfg.SyncInterval
+	}
+	return w
+}
+
+// patternWriter - запись файлов по умолчанию: последовательная запись через
+// лимитер с периодической синхронизацией и проверкой чтением
+type patternWriter struct {
+	cfg          *WipeConfig
+	syncInterval uint64
+	chunkSize    int
+	logger       *logging.EnterpriseLogger
+}
+
+func (w *patternWriter) WriteFile(ctx context.Context, filename string, size uint64, filler PassFiller, baseOffset uint64) (uint64, error) {
+	return createPatternFile(ctx, filename, size, w.cfg, w.syncInterval, w.chunkSize, filler, baseOffset, w.logger)
+}
+
+// isDiskFullError проверяет, является ли ошибка ошибкой заполнения тома или
+// исчерпания квоты. Остальные ошибки, в том числе отказ в доступе, проход
+// штатно не завершают.
+func isDiskFullError(err error) bool {
+	return system.IsDiskFullError(err)
+}
@@ -0,0 +1,320 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// fakeWriter создает пустые файлы затирания и сообщает о фиксированном
+// объеме записи: два файла по fakeFileBytes, затем заполнение тома
+type fakeWriter struct {
+	calls []fakeCall
+}
+
+type fakeCall struct {
+	Name       string
+	BaseOffset uint64
+}
+
+const fakeFileBytes = 64 * 1024 * 1024
+
+func (w *fakeWriter) WriteFile(ctx context.Context, filename string, size uint64, filler PassFiller, baseOffset uint64) (uint64, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	f.Close()
+	w.calls = append(w.calls, fakeCall{Name: filepath.Base(filename), BaseOffset: baseOffset})
+
+	if len(w.calls)%3 == 0 {
+		return 1024 * 1024, ErrDiskFull
+	}
+	return fakeFileBytes, nil
+}
+
+// parityResult - часть WipeOperation, которая не зависит от времени запуска
+type parityResult struct {
+	Disk, Path, Method, Status string
+	Passes                     int
+	ChunkSize                  int64
+	BytesWiped                 uint64
+	Records                    []parityRecord
+	Calls                      []fakeCall
+}
+
+type parityRecord struct {
+	Number       int
+	Phase        string
+	Pattern      string
+	BytesWritten uint64
+	Status       string
+	Files        int
+}
+
+func newParityResult(op *WipeOperation, w *fakeWriter) parityResult {
+	r := parityResult{
+		Disk: op.Disk, Path: op.Path, Method: op.Method, Status: op.Status,
+		Passes: op.Passes, ChunkSize: op.ChunkSize, BytesWiped: op.BytesWiped,
+		Calls: w.calls,
+	}
+	for _, rec := range op.PassRecords {
+		r.Records = append(r.Records, parityRecord{rec.Number, rec.Phase, rec.Pattern, rec.BytesWritten, rec.Status, rec.Files})
+	}
+	return r
+}
+
+// TestEntryPointParity проверяет, что CLI (WipeWithStrategy), WipeFreeSpace
+// и WipeEngine графического интерфейса дают одинаковую операцию на одном
+// каталоге
+func TestEntryPointParity(t *testing.T) {
+	dir := t.TempDir()
+	stateDir := t.TempDir()
+	// Свободное место задано явно: fakeWriter на диск не пишет
+	disk := system.DiskInfo{Letter: dir, Type: "HDD", TotalSize: 32 << 30, FreeSize: 16 << 30, IsWritable: true}
+
+	origResolve := resolveWipeTarget
+	t.Cleanup(func() { resolveWipeTarget = origResolve })
+	resolveWipeTarget = func(target string) (system.DiskInfo, error) {
+		if target != dir {
+			t.Errorf("WipeEngine: цель %q, want %q", target, dir)
+		}
+		return disk, nil
+	}
+
+	cfg := config.Default()
+	cfg.Wipe.StateDir = stateDir // Ключ маркеров - во временном каталоге, а не в каталоге по умолчанию
+	cfg.Logging.Level = "ERROR"
+	cfg.Wipe.EnableTrim = false
+	cfg.Wipe.FileDelayMs = 0
+	cfg.Wipe.SSDPasses = 2
+	cfg.Wipe.HDDPasses = 2
+	logger, err := logging.NewEnterpriseLogger(cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orig := newFileWriter
+	t.Cleanup(func() { newFileWriter = orig })
+	var writer *fakeWriter
+	newFileWriter = func(*WipeConfig, WipeStrategy, *PatternScheme, *logging.EnterpriseLogger) FileWriter {
+		writer = &fakeWriter{}
+		return writer
+	}
+
+	ctx := context.Background()
+	entries := []struct {
+		name string
+		run  func() *WipeOperation
+	}{
+		{"WipeWithStrategy", func() *WipeOperation {
+			return WipeWithStrategy(ctx, disk, cfg, logger, false, 0, ModeStandard, "", nil, nil, nil)
+		}},
+		{"WipeFreeSpace", func() *WipeOperation {
+			return WipeFreeSpace(ctx, disk, cfg, logger, false, 0, nil)
+		}},
+		{"WipeEngine", func() *WipeOperation {
+			engine := NewWipeEngine(logger)
+			engine.SetConfig(cfg)
+			return engine.WipeVolume(ctx, dir)
+		}},
+	}
+
+	var want parityResult
+	for i, e := range entries {
+		op := e.run()
+		if op.Status != "COMPLETED" {
+			t.Fatalf("%s: статус %s, ошибка %q", e.name, op.Status, op.Error)
+		}
+		got := newParityResult(op, writer)
+		if left, _ := os.ReadDir(dir); len(left) != 0 {
+			t.Errorf("%s: файлы затирания не удалены: %d", e.name, len(left))
+		}
+		if i == 0 {
+			want = got
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s отличается от %s:\n got %+v\nwant %+v", e.name, entries[0].name, got, want)
+		}
+	}
+
+	if want.Disk != dir {
+		t.Errorf("Disk = %q, want %q", want.Disk, dir)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, markerKeyFile)); err != nil {
+		t.Errorf("ключ маркеров не создан в wipe.state_dir: %v", err)
+	}
+	passes := max(GetMethodPasses(WipeMethod(want.Method)), 2)
+	if want.Passes != passes || len(want.Records) != passes {
+		t.Errorf("проходов %d, записей %d, want %d", want.Passes, len(want.Records), passes)
+	}
+	if len(want.Calls) != 3*passes {
+		t.Errorf("файлов записано %d, want %d", len(want.Calls), 3*passes)
+	}
+	perPass := uint64(2*fakeFileBytes + 1024*1024)
+	for _, r := range want.Records {
+		if r.BytesWritten != perPass || r.Files != 3 || r.Status != "COMPLETED" {
+			t.Errorf("проход %d: %+v", r.Number, r)
+		}
+	}
+	if want.BytesWiped != perPass*uint64(passes) {
+		t.Errorf("BytesWiped = %d, want %d", want.BytesWiped, perPass*uint64(passes))
+	}
+}
+
+// TestWipeEngineForRun проверяет, что параллельные запуски графического
+// интерфейса получают прогресс только в свой канал и с настройками своего
+// запуска, а закрытие канала после запуска не мешает другим запускам
+func TestWipeEngineForRun(t *testing.T) {
+	dir := t.TempDir()
+	disk := system.DiskInfo{Letter: dir, Type: "HDD", TotalSize: 32 << 30, FreeSize: 16 << 30, IsWritable: true}
+
+	origResolve := resolveWipeTarget
+	t.Cleanup(func() { resolveWipeTarget = origResolve })
+	resolveWipeTarget = func(string) (system.DiskInfo, error) { return disk, nil }
+	orig := newFileWriter
+	t.Cleanup(func() { newFileWriter = orig })
+	newFileWriter = func(*WipeConfig, WipeStrategy, *PatternScheme, *logging.EnterpriseLogger) FileWriter {
+		return &fakeWriter{}
+	}
+
+	tests := []struct {
+		name   string
+		passes []int // Проходы каждого параллельного запуска
+	}{
+		{"один запуск", []int{2}},
+		{"параллельные запуски", []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewWipeEngine(newTestLogger(t))
+			results := make(chan error, len(tt.passes))
+			for _, passes := range tt.passes {
+				cfg := config.Default()
+				cfg.Wipe.StateDir = t.TempDir()
+				cfg.Wipe.HDDMethod = string(MethodZero)
+				cfg.Wipe.EnableTrim = false
+				cfg.Wipe.FileDelayMs = 0
+				cfg.Wipe.HDDPasses = passes
+
+				go func() {
+					progress := make(chan ProgressInfo, 64)
+					op := base.ForRun(cfg, progress, nil).WipeVolume(context.Background(), dir)
+					close(progress)
+
+					var last ProgressInfo
+					for info := range progress {
+						last = info
+					}
+					switch {
+					case op.Status != "COMPLETED":
+						results <- fmt.Errorf("статус %s, ошибка %q", op.Status, op.Error)
+					case op.Passes != passes || !last.Done || last.Passes != passes:
+						results <- fmt.Errorf("проходов %d, итог %+v; want %d", op.Passes, last, passes)
+					default:
+						results <- nil
+					}
+				}()
+			}
+			for range tt.passes {
+				if err := <-results; err != nil {
+					t.Error(err)
+				}
+			}
+			if base.config.Wipe.StateDir == "" || base.progress != nil {
+				t.Error("ForRun изменил настройки исходного движка")
+			}
+		})
+	}
+}
+
+func TestOperationInterrupt(t *testing.T) {
+	tests := []struct {
+		name        string
+		cause       error
+		wantStatus  string
+		wantWarning string
+		wantNotRun  string // Предупреждение задания, не успевшего запуститься
+	}{
+		{"отмена", context.Canceled, "CANCELLED", "Операция отменена пользователем", "Операция отменена до запуска"},
+		{"таймаут", context.DeadlineExceeded, "PARTIAL", "Операция прервана по таймауту", "Время работы истекло до запуска"},
+		{"обернутый таймаут", fmt.Errorf("операция отменена: %w", context.DeadlineExceeded), "PARTIAL", "Операция прервана по таймауту", "Время работы истекло до запуска"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &WipeOperation{Status: "RUNNING"}
+			op.interrupt(tt.cause)
+			if op.Status != tt.wantStatus || op.Warning != tt.wantWarning {
+				t.Errorf("interrupt: %s %q, want %s %q", op.Status, op.Warning, tt.wantStatus, tt.wantWarning)
+			}
+			if op.err() == nil {
+				t.Error("прерванная операция без ошибки")
+			}
+
+			notRun := notStartedOperation(system.DiskInfo{Letter: "D:\\", Path: `D:\data`}, tt.cause)
+			if notRun.Status != tt.wantStatus || notRun.Warning != tt.wantNotRun {
+				t.Errorf("notStartedOperation: %s %q, want %s %q", notRun.Status, notRun.Warning, tt.wantStatus, tt.wantNotRun)
+			}
+			if notRun.Disk != "D:\\" || notRun.Path != `D:\data` {
+				t.Errorf("notStartedOperation: цель %q %q, want %q %q", notRun.Disk, notRun.Path, "D:\\", `D:\data`)
+			}
+		})
+	}
+}
+
+func TestCreatePatternFileCancelled(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   func() (context.Context, context.CancelFunc)
+		pause bool // Отмена приходит во время паузы
+		want  error
+	}{
+		{"отмена", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx, cancel
+		}, false, context.Canceled},
+		{"таймаут", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), -time.Second)
+		}, false, context.DeadlineExceeded},
+		{"отмена во время паузы", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			return ctx, cancel
+		}, true, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			filler, err := FixedPass(0).NewFiller()
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "wipe_001.tmp")
+			cfg := &WipeConfig{}
+			if tt.pause {
+				cfg.Pause = NewPauseController()
+				cfg.Pause.Pause()
+			}
+
+			written, err := createPatternFile(ctx, path, 1024*1024, cfg, 0, 64*1024, filler, 0, newTestLogger(t))
+			if written != 0 || !errors.Is(err, tt.want) {
+				t.Errorf("записано %d, ошибка %v, want %v", written, err, tt.want)
+			}
+		})
+	}
+}
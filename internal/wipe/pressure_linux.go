@@ -0,0 +1,42 @@
+//go:build linux
+
+package wipe
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ioPressurePath - PSI (pressure stall information) для ввода-вывода
+const ioPressurePath = "/proc/pressure/io"
+
+// readIOPressure возвращает долю времени (%), в которую хотя бы одна задача
+// ждала ввода-вывода за последние 10 секунд (строка "some avg10=")
+func readIOPressure() (float64, bool) {
+	data, err := os.ReadFile(ioPressurePath)
+	if err != nil {
+		return 0, false // Ядро без PSI
+	}
+	return parseIOPressure(string(data))
+}
+
+// parseIOPressure разбирает содержимое /proc/pressure/io
+func parseIOPressure(data string) (float64, bool) {
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if value, ok := strings.CutPrefix(f, "avg10="); ok {
+				v, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return 0, false
+				}
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package wipe
+
+// readIOPressure - давление ввода-вывода доступно только в Linux (PSI);
+// на других платформах регулятор опирается на задержки записи
+func readIOPressure() (float64, bool) {
+	return 0, false
+}
@@ -1,4 +1,217 @@
 package wipe
 
-// This file is intentionally left empty
-// Progress tracking functionality is handled in session.go
+import (
+	"context"
+	"time"
+)
+
+const (
+	// progressInterval - период отправки прогресса
+	progressInterval = time.Second
+
+	// speedSmoothing - вес нового замера в сглаженной скорости
+	speedSmoothing = 0.3
+)
+
+// progressTracker считает прогресс операции по проходам и отправляет
+// ProgressInfo не чаще раза в progressInterval. Скорость сглаживается
+// экспоненциально, оставшееся время оценивается по сглаженной скорости.
+// Промежуточные замеры не блокируют запись: если читатель занят, замер
+// пропускается. Итог операции (finish) доставляется всегда. Методы безопасно
+// вызывать у nil.
+type progressTracker struct {
+	ch    chan<- ProgressInfo
+	disk  string
+	start time.Time
+
+	passes     int
+	pass       int    // Текущий проход, с 0
+	passTarget uint64 // Ожидаемый объем прохода
+	passDone   uint64 // Записано в текущем проходе
+	doneBefore uint64 // Записано в завершенных проходах
+	file       string
+
+	last     time.Time
+	lastDone uint64
+	speed    float64 // Сглаженная скорость, байт/с
+}
+
+func newProgressTracker(ch chan<- ProgressInfo, disk string, start time.Time) *progressTracker {
+	if ch == nil {
+		return nil
+	}
+	return &progressTracker{ch: ch, disk: disk, start: start, last: start}
+}
+
+// plan задает число проходов и ожидаемый объем прохода. Проходы до
+// firstPass уже выполнены в прошлых запусках.
+func (t *progressTracker) plan(passes, firstPass int, passTarget uint64) {
+	if t == nil {
+		return
+	}
+	t.passes = passes
+	t.pass = firstPass
+	t.passTarget = passTarget
+	t.doneBefore = uint64(firstPass) * passTarget
+	t.lastDone = t.doneBefore
+}
+
+// beginPass начинает проход; done - объем, записанный до прерывания
+func (t *progressTracker) beginPass(pass int, done uint64) {
+	if t == nil {
+		return
+	}
+	t.pass = pass
+	t.passDone = done
+	t.lastDone = t.done()
+}
+
+// endPass завершает проход, записавший written байт
+func (t *progressTracker) endPass(written uint64) {
+	if t == nil {
+		return
+	}
+	t.doneBefore += written
+	t.passDone = 0
+	t.lastDone = t.done()
+}
+
+// beginFile отмечает начало записи файла
+func (t *progressTracker) beginFile(name string) {
+	if t == nil {
+		return
+	}
+	t.file = name
+}
+
+// add учитывает записанные байты и при необходимости отправляет прогресс
+func (t *progressTracker) add(n uint64) {
+	if t == nil {
+		return
+	}
+	t.passDone += n
+	if now := time.Now(); now.Sub(t.last) >= progressInterval {
+		t.sample(now)
+		sendProgress(t.ch, t.info())
+	}
+}
+
+// endFile сверяет объем прохода после файла: запись без add учитывается здесь
+func (t *progressTracker) endFile(passDone uint64) {
+	if t == nil || passDone <= t.passDone {
+		return
+	}
+	t.add(passDone - t.passDone)
+}
+
+// finish отправляет итоговый прогресс операции. Читатель ждет Done, чтобы
+// показать 100% или ошибку, поэтому итог не пропускается: отправка ждет
+// читателя, пока не отменен ctx вызывающего.
+func (t *progressTracker) finish(ctx context.Context, op *WipeOperation) {
+	if t == nil {
+		return
+	}
+	info := t.info()
+	info.Done = true
+	info.Error = op.err()
+	info.SpeedMBps = op.SpeedMBps
+	info.EstimatedTime = 0
+	if op.Status == "COMPLETED" {
+		info.Percentage = 100
+	}
+
+	select {
+	case t.ch <- info:
+		return
+	default:
+	}
+	select {
+	case t.ch <- info:
+	case <-ctx.Done():
+	}
+}
+
+// pause отправляет прогресс с отметкой паузы
+func (t *progressTracker) pause() {
+	if t == nil {
+		return
+	}
+	info := t.info()
+	info.Paused = true
+	info.EstimatedTime = 0
+	sendProgress(t.ch, info)
+}
+
+// resume исключает время паузы из замера скорости
+func (t *progressTracker) resume() {
+	if t == nil {
+		return
+	}
+	t.last = time.Now()
+	t.lastDone = t.done()
+}
+
+// sample обновляет сглаженную скорость по объему с прошлого замера
+func (t *progressTracker) sample(now time.Time) {
+	dt := now.Sub(t.last).Seconds()
+	done := t.done()
+	if dt > 0 {
+		current := float64(done-t.lastDone) / dt
+		if t.speed == 0 {
+			t.speed = current
+		} else {
+			t.speed = speedSmoothing*current + (1-speedSmoothing)*t.speed
+		}
+	}
+	t.last = now
+	t.lastDone = done
+}
+
+func (t *progressTracker) done() uint64 {
+	return t.doneBefore + t.passDone
+}
+
+// total оценивает объем всей операции: проход может записать больше или
+// меньше ожидаемого, если свободное место изменилось во время работы
+func (t *progressTracker) total() uint64 {
+	total := t.doneBefore + max(t.passTarget, t.passDone)
+	if rest := t.passes - t.pass - 1; rest > 0 {
+		total += uint64(rest) * t.passTarget
+	}
+	return total
+}
+
+func (t *progressTracker) info() ProgressInfo {
+	done, total := t.done(), t.total()
+	info := ProgressInfo{
+		BytesWritten: done,
+		SpeedMBps:    t.speed / (1024 * 1024),
+		CurrentFile:  t.file,
+		StartTime:    t.start,
+
+		Disk:       t.disk,
+		Pass:       t.pass + 1,
+		Passes:     t.passes,
+		PassBytes:  t.passDone,
+		PassTarget: t.passTarget,
+		TotalBytes: total,
+	}
+	if total > 0 {
+		info.Percentage = min(float64(done)/float64(total)*100, 100)
+	}
+	if t.speed > 0 && total > done {
+		info.EstimatedTime = time.Duration(float64(total-done) / t.speed * float64(time.Second))
+	}
+	return info
+}
+
+// sendProgress передает прогресс, не блокируя запись, если канал никто не читает
+func sendProgress(ch chan<- ProgressInfo, info ProgressInfo) {
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- info:
+	default:
+	}
+}
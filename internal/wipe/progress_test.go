@@ -0,0 +1,129 @@
+package wipe
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProgressTrackerInfo(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		name        string
+		passes      int
+		firstPass   int
+		passTarget  uint64
+		steps       func(p *progressTracker)
+		wantPass    int
+		wantDone    uint64
+		wantTotal   uint64
+		wantPercent float64
+	}{
+		{"начало", 3, 0, 100 * mb, func(p *progressTracker) {}, 1, 0, 300 * mb, 0},
+		{"середина первого прохода", 2, 0, 100 * mb, func(p *progressTracker) {
+			p.beginPass(0, 0)
+			p.add(50 * mb)
+		}, 1, 50 * mb, 200 * mb, 25},
+		{"второй проход", 2, 0, 100 * mb, func(p *progressTracker) {
+			p.beginPass(0, 0)
+			p.add(100 * mb)
+			p.endPass(100 * mb)
+			p.beginPass(1, 0)
+			p.add(50 * mb)
+		}, 2, 150 * mb, 200 * mb, 75},
+		{"проход больше ожидаемого", 2, 0, 100 * mb, func(p *progressTracker) {
+			p.beginPass(0, 0)
+			p.add(150 * mb)
+		}, 1, 150 * mb, 250 * mb, 60},
+		{"продолжение с контрольной точки", 4, 2, 100 * mb, func(p *progressTracker) {
+			p.beginPass(2, 40*mb)
+		}, 3, 240 * mb, 400 * mb, 60},
+		{"запись без add", 1, 0, 100 * mb, func(p *progressTracker) {
+			p.beginPass(0, 0)
+			p.add(10 * mb)
+			p.endFile(30 * mb)
+		}, 1, 30 * mb, 100 * mb, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProgressTracker(make(chan ProgressInfo, 16), "D:\\", time.Now())
+			p.plan(tt.passes, tt.firstPass, tt.passTarget)
+			tt.steps(p)
+
+			info := p.info()
+			if info.Pass != tt.wantPass || info.Passes != tt.passes || info.BytesWritten != tt.wantDone ||
+				info.TotalBytes != tt.wantTotal || info.Percentage != tt.wantPercent {
+				t.Errorf("проход %d/%d, записано %d из %d (%.1f%%), want %d/%d, %d из %d (%.1f%%)",
+					info.Pass, info.Passes, info.BytesWritten, info.TotalBytes, info.Percentage,
+					tt.wantPass, tt.passes, tt.wantDone, tt.wantTotal, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestProgressTrackerFinish(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		reader      bool // Читатель забирает итог после задержки
+		cancelled   bool // ctx вызывающего отменен
+		wantDone    bool
+		wantPercent float64
+	}{
+		{"завершено", "COMPLETED", true, false, true, 100},
+		{"ошибка", "FAILED", true, false, true, 50},
+		{"нет читателя, ctx отменен", "COMPLETED", false, true, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan ProgressInfo) // Без буфера: итог нельзя положить заранее
+			p := newProgressTracker(ch, "D:\\", time.Now())
+			p.plan(1, 0, 100)
+			p.add(50)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			got := make(chan ProgressInfo, 1)
+			if tt.reader {
+				go func() {
+					time.Sleep(20 * time.Millisecond) // Читатель занят: итог должен дождаться его
+					got <- <-ch
+				}()
+			}
+
+			op := &WipeOperation{Status: tt.status, Error: "ошибка записи"}
+			p.finish(ctx, op)
+
+			var info ProgressInfo
+			select {
+			case info = <-got:
+			case <-time.After(time.Second):
+			}
+			if info.Done != tt.wantDone || info.Percentage != tt.wantPercent {
+				t.Errorf("итог: Done %v, %.1f%%, want %v, %.1f%%", info.Done, info.Percentage, tt.wantDone, tt.wantPercent)
+			}
+			if tt.wantDone && (tt.status == "COMPLETED") != (info.Error == nil) {
+				t.Errorf("итог: ошибка %v при статусе %s", info.Error, tt.status)
+			}
+		})
+	}
+}
+
+func TestProgressTrackerNil(t *testing.T) {
+	p := newProgressTracker(nil, "D:\\", time.Now())
+	if p != nil {
+		t.Fatal("без канала трекер должен быть nil")
+	}
+	p.plan(1, 0, 100)
+	p.beginPass(0, 0)
+	p.add(10)
+	p.endFile(20)
+	p.endPass(20)
+	p.finish(context.Background(), &WipeOperation{Status: "COMPLETED"})
+}
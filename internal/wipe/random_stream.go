@@ -0,0 +1,121 @@
+package wipe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// RandomStream - поток псевдослучайных данных на AES-256-CTR.
+// Ключ и IV берутся из crypto/rand один раз, дальше данные генерируются
+// шифрованием счетчика: это на порядки быстрее crypto/rand.Read и не требует
+// аллокаций на каждый чанк. Поток адресуется смещением, поэтому любой его
+// участок можно воспроизвести повторно (например, для проверки чтением).
+type RandomStream struct {
+	block cipher.Block
+	key   [32]byte
+	iv    [aes.BlockSize]byte
+}
+
+// NewRandomStream создает поток со случайным ключом и IV
+func NewRandomStream() (*RandomStream, error) {
+	var seed [32 + aes.BlockSize]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, fmt.Errorf("ошибка генерации ключа потока: %w", err)
+	}
+	return NewRandomStreamFromSeed(seed[:32], seed[32:])
+}
+
+// NewRandomStreamFromSeed создает поток с заданными ключом (32 байта) и IV (16 байт)
+func NewRandomStreamFromSeed(key, iv []byte) (*RandomStream, error) {
+	if len(key) != 32 || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("некорректный размер ключа (%d) или IV (%d)", len(key), len(iv))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации AES: %w", err)
+	}
+
+	s := &RandomStream{block: block}
+	copy(s.key[:], key)
+	copy(s.iv[:], iv)
+	return s, nil
+}
+
+// Seed возвращает ключ и IV потока
+func (s *RandomStream) Seed() (key, iv []byte) {
+	return append([]byte(nil), s.key[:]...), append([]byte(nil), s.iv[:]...)
+}
+
+// FillAt заполняет buf данными потока начиная со смещения offset.
+// Безопасен для параллельного вызова с разными буферами.
+func (s *RandomStream) FillAt(buf []byte, offset uint64) error {
+	if len(buf) == 0 {
+		return nil
+	}
+
+	iv := s.counterAt(offset / aes.BlockSize)
+	ctr := cipher.NewCTR(s.block, iv[:])
+
+	// Смещение внутри блока: пропускаем начало первого блока ключевого потока
+	if skip := int(offset % aes.BlockSize); skip > 0 {
+		var scratch [aes.BlockSize]byte
+		ctr.XORKeyStream(scratch[:skip], scratch[:skip])
+	}
+
+	// Ключевой поток CTR = шифрование нулевого открытого текста
+	clear(buf)
+	ctr.XORKeyStream(buf, buf)
+	return nil
+}
+
+// counterAt возвращает значение счетчика CTR для блока с номером blockIndex
+func (s *RandomStream) counterAt(blockIndex uint64) [aes.BlockSize]byte {
+	var ctr [aes.BlockSize]byte
+	hi := binary.BigEndian.Uint64(s.iv[:8])
+	lo := binary.BigEndian.Uint64(s.iv[8:])
+
+	newLo := lo + blockIndex
+	if newLo < lo {
+		hi++ // перенос в старшую половину 128-битного счетчика
+	}
+
+	binary.BigEndian.PutUint64(ctr[:8], hi)
+	binary.BigEndian.PutUint64(ctr[8:], newLo)
+	return ctr
+}
+
+// randomSource - общий для процесса поток для вызовов без собственного
+// смещения (FillRandom, FillPattern). Каждый вызов резервирует следующий
+// участок потока, поэтому данные не повторяются.
+type randomSource struct {
+	once   sync.Once
+	stream *RandomStream
+	err    error
+
+	mu     sync.Mutex
+	offset uint64
+}
+
+var sharedRandom randomSource
+
+// Read заполняет buf следующим участком общего потока
+func (r *randomSource) Read(buf []byte) error {
+	r.once.Do(func() {
+		r.stream, r.err = NewRandomStream()
+	})
+	if r.err != nil {
+		return r.err
+	}
+
+	r.mu.Lock()
+	offset := r.offset
+	r.offset += uint64(len(buf))
+	r.mu.Unlock()
+
+	return r.stream.FillAt(buf, offset)
+}
@@ -0,0 +1,119 @@
+package wipe
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+)
+
+// ctrKeystream возвращает n байт ключевого потока AES-CTR стандартной
+// библиотеки - эталон для RandomStream
+func ctrKeystream(t *testing.T, key, iv []byte, n int) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, n)
+	cipher.NewCTR(block, iv).XORKeyStream(out, out)
+	return out
+}
+
+func testKey() []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i * 7)
+	}
+	return key
+}
+
+func TestRandomStreamFillAtSeek(t *testing.T) {
+	key := testKey()
+	iv := bytes.Repeat([]byte{0x01}, aes.BlockSize)
+	s, err := NewRandomStreamFromSeed(key, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ctrKeystream(t, key, iv, 4096)
+
+	tests := []struct {
+		name   string
+		offset uint64
+		size   int
+	}{
+		{"с начала потока", 0, 4096},
+		{"один байт", 5, 1},
+		{"внутри блока", 3, 10},
+		{"граница блока", 16, 32},
+		{"через границу блока", 15, 2},
+		{"невыровненный хвост", 1000, 3096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := make([]byte, tt.size)
+			if err := s.FillAt(buf, tt.offset); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, want[tt.offset:tt.offset+uint64(tt.size)]) {
+				t.Errorf("FillAt(%d, %d) расходится с AES-CTR", tt.offset, tt.size)
+			}
+		})
+	}
+}
+
+func TestRandomStreamCounterCarry(t *testing.T) {
+	key := testKey()
+
+	tests := []struct {
+		name string
+		iv   []byte
+	}{
+		{"перенос в старшую половину", append(bytes.Repeat([]byte{0x00}, 7), append([]byte{0x01}, bytes.Repeat([]byte{0xFF}, 8)...)...)},
+		{"перенос за два блока до переполнения", append(bytes.Repeat([]byte{0x00}, 8), append(bytes.Repeat([]byte{0xFF}, 7), 0xFE)...)},
+		{"переполнение 128-битного счетчика", bytes.Repeat([]byte{0xFF}, aes.BlockSize)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewRandomStreamFromSeed(key, tt.iv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ctrKeystream(t, key, tt.iv, 8*aes.BlockSize)
+
+			// Каждый блок отдельно: счетчик вычисляется по смещению, а не продолжается
+			for off := uint64(0); off < uint64(len(want)); off += aes.BlockSize {
+				buf := make([]byte, aes.BlockSize)
+				if err := s.FillAt(buf, off); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(buf, want[off:off+aes.BlockSize]) {
+					t.Errorf("блок %d расходится с AES-CTR", off/aes.BlockSize)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRandomStreamFromSeedSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		key, iv int
+		wantErr bool
+	}{
+		{"AES-256", 32, aes.BlockSize, false},
+		{"короткий ключ", 16, aes.BlockSize, true},
+		{"короткий IV", 32, 8, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRandomStreamFromSeed(make([]byte, tt.key), make([]byte, tt.iv))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ошибка %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
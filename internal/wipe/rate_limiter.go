@@ -0,0 +1,266 @@
+package wipe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"wipedisk_enterprise/internal/system"
+)
+
+// RateLimiter - ограничитель скорости записи по алгоритму token bucket.
+// Токены (байты) накапливаются со скоростью rate до объема burst, поэтому
+// после паузы запись может кратковременно идти быстрее лимита. Запись
+// большего объема, чем есть токенов, уходит "в долг" и ждет его погашения.
+// Лимитер может иметь родителя: запись ждет оба лимита, что позволяет
+// ограничивать скорость одновременно на диск и на весь процесс.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // байт/с, 0 - без ограничения
+	burst  float64
+	tokens float64
+	last   time.Time
+	parent *RateLimiter
+
+	observer LatencyObserver // Регулятор, получающий замеры задержек
+
+	// Статистика фактической скорости
+	total       uint64
+	started     time.Time
+	windowStart time.Time
+	windowBytes uint64
+	throughput  float64
+}
+
+// throughputWindow - окно усреднения фактической скорости
+const throughputWindow = 2 * time.Second
+
+// NewRateLimiter создает лимитер на maxSpeedMBps (0 - без ограничения)
+func NewRateLimiter(maxSpeedMBps float64, parent *RateLimiter) *RateLimiter {
+	now := time.Now()
+	l := &RateLimiter{
+		last:        now,
+		started:     now,
+		windowStart: now,
+		parent:      parent,
+	}
+	l.setRateLocked(maxSpeedMBps)
+	l.tokens = l.burst
+	return l
+}
+
+// SetRate изменяет лимит во время работы; 0 снимает ограничение
+func (l *RateLimiter) SetRate(maxSpeedMBps float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refillLocked(time.Now())
+	l.setRateLocked(maxSpeedMBps)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+func (l *RateLimiter) setRateLocked(maxSpeedMBps float64) {
+	if maxSpeedMBps < 0 {
+		maxSpeedMBps = 0
+	}
+	l.rate = maxSpeedMBps * 1024 * 1024
+	// Запас на одну секунду записи
+	l.burst = l.rate
+}
+
+// Rate возвращает текущий лимит в МБ/с (0 - без ограничения)
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate / (1024 * 1024)
+}
+
+// SetObserver подключает получателя замеров задержек (nil - отключить)
+func (l *RateLimiter) SetObserver(o LatencyObserver) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.observer = o
+}
+
+// ObserveWrite передает регулятору задержку записи n байт
+func (l *RateLimiter) ObserveWrite(n int, d time.Duration) {
+	if o := l.getObserver(); o != nil {
+		o.ObserveWrite(n, d)
+	}
+}
+
+// ObserveSync передает регулятору задержку fsync после записи n байт
+func (l *RateLimiter) ObserveSync(n uint64, d time.Duration) {
+	if o := l.getObserver(); o != nil {
+		o.ObserveSync(n, d)
+	}
+}
+
+func (l *RateLimiter) getObserver() LatencyObserver {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.observer
+}
+
+// WaitN ждет разрешения на запись n байт
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	var delay time.Duration
+	for lim := l; lim != nil; lim = lim.parent {
+		if d := lim.reserve(n); d > delay {
+			delay = d
+		}
+	}
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve забирает n токенов и возвращает время ожидания погашения долга
+func (l *RateLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.account(now, n)
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.refillLocked(now)
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refillLocked начисляет токены за время с последнего обращения
+func (l *RateLimiter) refillLocked(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens += elapsed * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// account учитывает записанные байты в статистике
+func (l *RateLimiter) account(now time.Time, n int) {
+	l.total += uint64(n)
+	l.windowBytes += uint64(n)
+	if elapsed := now.Sub(l.windowStart); elapsed >= throughputWindow {
+		l.throughput = float64(l.windowBytes) / elapsed.Seconds()
+		l.windowStart = now
+		l.windowBytes = 0
+	}
+}
+
+// Throughput возвращает фактическую скорость записи через лимитер в МБ/с
+// (за последнее окно усреднения, а до его заполнения - среднюю с начала работы)
+func (l *RateLimiter) Throughput() float64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.throughput > 0 {
+		return l.throughput / (1024 * 1024)
+	}
+	if elapsed := time.Since(l.started).Seconds(); elapsed > 0 {
+		return float64(l.total) / elapsed / (1024 * 1024)
+	}
+	return 0
+}
+
+// BytesTotal возвращает общий объем записи через лимитер
+func (l *RateLimiter) BytesTotal() uint64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
+
+// LimiterSet - глобальный лимитер процесса и дочерние лимитеры дисков
+type LimiterSet struct {
+	mu          sync.Mutex
+	global      *RateLimiter
+	disks       map[string]*RateLimiter
+	diskMaxMBps float64
+}
+
+// NewLimiterSet создает набор лимитеров: globalMBps - суммарный лимит,
+// diskMBps - лимит на один диск (0 - без ограничения)
+func NewLimiterSet(globalMBps, diskMBps float64) *LimiterSet {
+	return &LimiterSet{
+		global:      NewRateLimiter(globalMBps, nil),
+		disks:       make(map[string]*RateLimiter),
+		diskMaxMBps: diskMBps,
+	}
+}
+
+// sharedLimiters - лимитеры процесса: параллельно затираемые тома делят общий лимит
+var sharedLimiters = NewLimiterSet(0, 0)
+
+// Limiters возвращает лимитеры процесса
+func Limiters() *LimiterSet {
+	return sharedLimiters
+}
+
+// Configure изменяет лимиты; уже работающие записи подхватывают их сразу.
+// Лимиты дисков меняются только при изменении значения, чтобы не сбрасывать
+// скорость, выставленную адаптивным регулятором.
+func (s *LimiterSet) Configure(globalMBps, diskMBps float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.global.Rate() != globalMBps {
+		s.global.SetRate(globalMBps)
+	}
+	if s.diskMaxMBps != diskMBps {
+		s.diskMaxMBps = diskMBps
+		for _, l := range s.disks {
+			l.SetRate(diskMBps)
+		}
+	}
+}
+
+// Global возвращает общий лимитер процесса
+func (s *LimiterSet) Global() *RateLimiter {
+	return s.global
+}
+
+// ForDisk возвращает лимитер диска (все файлы тома делят один лимит)
+func (s *LimiterSet) ForDisk(disk string) *RateLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := system.NormalizePath(disk)
+	l, ok := s.disks[key]
+	if !ok {
+		l = NewRateLimiter(s.diskMaxMBps, s.global)
+		s.disks[key] = l
+	}
+	return l
+}
@@ -0,0 +1,120 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// approx сравнивает задержки с допуском на время выполнения теста
+func approx(got, want time.Duration) bool {
+	d := got - want
+	return d > -20*time.Millisecond && d < 20*time.Millisecond
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		mbps      float64
+		sizes     []int // Последовательные запросы
+		wantDelay []time.Duration
+	}{
+		{"без ограничения", 0, []int{64 << 20, 64 << 20}, []time.Duration{0, 0}},
+		{"в пределах запаса", 1, []int{256 << 10, 512 << 10}, []time.Duration{0, 0}},
+		{"долг после запаса", 1, []int{512 << 10, 1 << 20}, []time.Duration{0, 500 * time.Millisecond}},
+		{"долг накапливается", 4, []int{4 << 20, 2 << 20, 2 << 20}, []time.Duration{0, 500 * time.Millisecond, time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.mbps, nil)
+			var total uint64
+			for i, n := range tt.sizes {
+				if got := l.reserve(n); !approx(got, tt.wantDelay[i]) {
+					t.Errorf("запрос %d (%d байт): ожидание %v, want %v", i+1, n, got, tt.wantDelay[i])
+				}
+				total += uint64(n)
+			}
+			if l.BytesTotal() != total {
+				t.Errorf("BytesTotal() = %d, want %d", l.BytesTotal(), total)
+			}
+		})
+	}
+}
+
+func TestRateLimiterSetRate(t *testing.T) {
+	l := NewRateLimiter(8, nil)
+	l.SetRate(1)
+	if l.Rate() != 1 {
+		t.Errorf("Rate() = %v, want 1", l.Rate())
+	}
+	// Запас прежнего лимита срезается до нового: 2 МБ при 1 МБ/с - секунда долга
+	if got := l.reserve(2 << 20); !approx(got, time.Second) {
+		t.Errorf("ожидание после снижения лимита %v, want 1s", got)
+	}
+
+	l.SetRate(-5)
+	if l.Rate() != 0 || l.reserve(1<<30) != 0 {
+		t.Error("отрицательный лимит должен снимать ограничение")
+	}
+}
+
+func TestRateLimiterParentChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		child       float64
+		parent      float64
+		n           int
+		wantBlocked bool
+	}{
+		{"без ограничений", 0, 0, 8 << 20, false},
+		{"в пределах обоих лимитов", 4, 4, 1 << 20, false},
+		{"ограничивает диск", 1, 0, 2 << 20, true},
+		{"ограничивает общий лимит", 0, 1, 2 << 20, true},
+		{"ограничивает общий лимит при большем лимите диска", 100, 1, 2 << 20, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := NewRateLimiter(tt.parent, nil)
+			child := NewRateLimiter(tt.child, parent)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := child.WaitN(ctx, tt.n)
+			if blocked := errors.Is(err, context.DeadlineExceeded); blocked != tt.wantBlocked {
+				t.Errorf("WaitN: ошибка %v, ожидание ограничено: %v", err, tt.wantBlocked)
+			}
+			// Запись учитывается во всей цепочке, даже если лимит не задан
+			if child.BytesTotal() != uint64(tt.n) || parent.BytesTotal() != uint64(tt.n) {
+				t.Errorf("BytesTotal: диск %d, общий %d, want %d", child.BytesTotal(), parent.BytesTotal(), tt.n)
+			}
+		})
+	}
+}
+
+func TestLimiterSetForDisk(t *testing.T) {
+	s := NewLimiterSet(100, 20)
+
+	a := s.ForDisk("/mnt/a")
+	if s.ForDisk("/mnt/a") != a {
+		t.Error("ForDisk вернул второй лимитер для того же тома")
+	}
+	if b := s.ForDisk("/mnt/b"); b == a || b.parent != s.Global() {
+		t.Error("лимитер другого тома должен быть отдельным и подчиняться общему")
+	}
+	if a.parent != s.Global() || a.Rate() != 20 || s.Global().Rate() != 100 {
+		t.Errorf("лимиты: диск %v, общий %v", a.Rate(), s.Global().Rate())
+	}
+
+	a.SetRate(5) // Скорость, выставленная адаптивным регулятором
+	s.Configure(50, 20)
+	if a.Rate() != 5 || s.Global().Rate() != 50 {
+		t.Errorf("Configure без изменения лимита диска: диск %v, общий %v", a.Rate(), s.Global().Rate())
+	}
+	s.Configure(50, 10)
+	if a.Rate() != 10 {
+		t.Errorf("Configure с новым лимитом диска: %v, want 10", a.Rate())
+	}
+}
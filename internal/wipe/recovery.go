@@ -0,0 +1,300 @@
+package wipe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// Имена артефактов, которые оставляют движки затирания при аварийном завершении
+var (
+	rootArtifactPattern = regexp.MustCompile(`^(wipe_\d{3,}|wipe_bench_\d{3,}|cipher_\d{3,}_[A-Za-z0-9]+)\.tmp$`)
+	tempArtifactPattern = regexp.MustCompile(`^wipe_data_\d+\.bin$`)
+)
+
+const (
+	artifactTempDir = ".wipedisk_tmp"
+	artifactTestDir = ".wipedisk_test"
+
+	// artifactMinAge - файлы, изменявшиеся недавно, могут принадлежать
+	// работающему процессу затирания и не удаляются
+	artifactMinAge = 2 * time.Minute
+)
+
+// RecoveredArtifact удаленный артефакт
+type RecoveredArtifact struct {
+	Path string
+	Size uint64
+}
+
+// SkippedArtifact артефакт, который восстановление не тронуло
+type SkippedArtifact struct {
+	Path   string
+	Reason string
+}
+
+// RecoveryReport результат восстановления после аварийного завершения
+type RecoveryReport struct {
+	Removed        []RecoveredArtifact
+	Skipped        []SkippedArtifact
+	Errors         []string
+	BytesReclaimed uint64
+	DryRun         bool
+}
+
+// RecoverAllVolumes ищет артефакты затирания на всех томах и в каталогах,
+// затирание которых записано в незавершенных журналах
+func RecoverAllVolumes(ctx context.Context, stateDir string, dryRun bool, logger *logging.EnterpriseLogger) (*RecoveryReport, error) {
+	disks, err := system.GetDiskInfo(false)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения дисков: %w", err)
+	}
+
+	volumes := make([]string, 0, len(disks))
+	for _, d := range disks {
+		volumes = append(volumes, d.Letter)
+	}
+	for _, target := range journalTargets(stateDir) {
+		if !slices.ContainsFunc(volumes, func(v string) bool { return system.SameTarget(v, target) }) {
+			volumes = append(volumes, target)
+		}
+	}
+	return RecoverArtifacts(ctx, stateDir, volumes, dryRun, logger)
+}
+
+// RecoverArtifacts находит на томах и в каталогах затирания файлы, оставшиеся
+// от прерванных запусков, и удаляет те, чей заголовок подписан ключом этой
+// установки.
+// Файлы без маркера или с чужой подписью только попадают в список пропущенных.
+// Содержимое артефактов - данные затирания, поэтому после проверки маркера
+// их достаточно удалить.
+func RecoverArtifacts(ctx context.Context, stateDir string, volumes []string, dryRun bool, logger *logging.EnterpriseLogger) (*RecoveryReport, error) {
+	report := &RecoveryReport{DryRun: dryRun}
+
+	marker, err := LoadArtifactMarker(stateDir)
+	if err != nil {
+		return report, fmt.Errorf("ключ маркеров недоступен, восстановление невозможно: %w", err)
+	}
+
+	// Файлы, которые журнал незавершенного запуска держит для продолжения
+	kept := journalKeptFiles(stateDir)
+
+	for _, volume := range volumes {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		root := system.VolumeRoot(volume)
+		report.scanDir(root, rootArtifactPattern, marker, kept, logger)
+
+		tempDir := filepath.Join(root, artifactTempDir)
+		if _, err := os.Stat(tempDir); err == nil {
+			report.scanDir(tempDir, tempArtifactPattern, marker, kept, logger)
+			report.removeEmptyDir(tempDir, logger)
+		}
+
+		metaDir := filepath.Join(root, artifactMetaDir)
+		if _, err := os.Stat(metaDir); err == nil {
+			report.recoverMetaDir(metaDir, marker, logger)
+		}
+
+		testDir := filepath.Join(root, artifactTestDir)
+		if _, err := os.Stat(testDir); err == nil {
+			report.removeEmptyDir(testDir, logger)
+		}
+	}
+
+	if logger != nil {
+		logger.Log("INFO", "Восстановление артефактов затирания завершено",
+			"removed", len(report.Removed), "skipped", len(report.Skipped),
+			"errors", len(report.Errors), "bytes_reclaimed", report.BytesReclaimed, "dry_run", dryRun)
+	}
+
+	return report, nil
+}
+
+// scanDir проверяет файлы каталога, подходящие под шаблон имени артефакта
+func (r *RecoveryReport) scanDir(dir string, pattern *regexp.Regexp, marker *ArtifactMarker, kept map[string]bool, logger *logging.EnterpriseLogger) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", dir, err))
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+
+		if kept[filepath.Clean(path)] {
+			r.skip(path, "файл нужен для продолжения незавершенного запуска (wipe --resume)", logger)
+			continue
+		}
+		if time.Since(info.ModTime()) < artifactMinAge {
+			r.skip(path, "файл изменялся недавно, возможно идет затирание", logger)
+			continue
+		}
+		if _, err := marker.Verify(path); err != nil {
+			r.skip(path, "не является артефактом WipeDisk: "+err.Error(), logger)
+			continue
+		}
+
+		if !r.DryRun {
+			if err := os.Remove(path); err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", path, err))
+				if logger != nil {
+					logger.Log("WARN", "Ошибка удаления артефакта", "file", path, "error", err.Error())
+				}
+				continue
+			}
+		}
+
+		size := uint64(info.Size())
+		r.Removed = append(r.Removed, RecoveredArtifact{Path: path, Size: size})
+		r.BytesReclaimed += size
+		if logger != nil {
+			logger.Log("INFO", "Удален артефакт затирания", "file", path, "bytes", size, "dry_run", r.DryRun)
+		}
+	}
+}
+
+// recoverMetaDir удаляет каталог очистки метаданных, если его файл-маркер
+// подписан ключом этой установки. Файлы в нем - мелкие случайные данные.
+func (r *RecoveryReport) recoverMetaDir(dir string, marker *ArtifactMarker, logger *logging.EnterpriseLogger) {
+	owner := filepath.Join(dir, metaOwnerFile)
+	info, err := os.Stat(owner)
+	if err != nil {
+		r.skip(dir, "нет файла-маркера каталога", logger)
+		return
+	}
+	if time.Since(info.ModTime()) < artifactMinAge {
+		r.skip(dir, "каталог создан недавно, возможно идет очистка метаданных", logger)
+		return
+	}
+	if _, err := marker.Verify(owner); err != nil {
+		r.skip(dir, "не является артефактом WipeDisk: "+err.Error(), logger)
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", dir, err))
+		return
+	}
+	var size uint64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil {
+			size += uint64(info.Size())
+		}
+	}
+
+	if !r.DryRun {
+		if err := os.RemoveAll(dir); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", dir, err))
+			return
+		}
+	}
+	r.Removed = append(r.Removed, RecoveredArtifact{Path: dir, Size: size})
+	r.BytesReclaimed += size
+	if logger != nil {
+		logger.Log("INFO", "Удален каталог очистки метаданных", "dir", dir, "files", len(entries), "bytes", size, "dry_run", r.DryRun)
+	}
+}
+
+// removeEmptyDir удаляет служебный каталог, если в нем ничего не осталось
+func (r *RecoveryReport) removeEmptyDir(dir string, logger *logging.EnterpriseLogger) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", dir, err))
+		return
+	}
+	if len(entries) > 0 {
+		r.skip(dir, fmt.Sprintf("каталог не пуст (%d элементов)", len(entries)), logger)
+		return
+	}
+
+	if !r.DryRun {
+		if err := os.Remove(dir); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", dir, err))
+			return
+		}
+	}
+	r.Removed = append(r.Removed, RecoveredArtifact{Path: dir})
+	if logger != nil {
+		logger.Log("INFO", "Удален служебный каталог", "dir", dir, "dry_run", r.DryRun)
+	}
+}
+
+func (r *RecoveryReport) skip(path, reason string, logger *logging.EnterpriseLogger) {
+	r.Skipped = append(r.Skipped, SkippedArtifact{Path: path, Reason: reason})
+	if logger != nil {
+		logger.Log("WARN", "Файл пропущен при восстановлении", "file", path, "reason", reason)
+	}
+}
+
+// journalKeptFiles возвращает файлы, которые незавершенные журналы оставили
+// на диске для продолжения прохода
+func journalKeptFiles(stateDir string) map[string]bool {
+	kept := make(map[string]bool)
+	for _, v := range pendingCheckpoints(stateDir) {
+		for _, f := range v.Files {
+			kept[filepath.Clean(f)] = true
+		}
+	}
+	return kept
+}
+
+// journalTargets возвращает существующие цели незавершенных журналов.
+// Каталогов среди них нет в списке томов, а прерванный запуск мог оставить
+// в них файлы затирания.
+func journalTargets(stateDir string) []string {
+	var targets []string
+	for _, v := range pendingCheckpoints(stateDir) {
+		if _, err := os.Stat(v.Disk); err != nil {
+			continue
+		}
+		if !slices.Contains(targets, v.Disk) {
+			targets = append(targets, v.Disk)
+		}
+	}
+	return targets
+}
+
+// pendingCheckpoints читает контрольные точки незавершенных томов из всех журналов
+func pendingCheckpoints(stateDir string) []*VolumeCheckpoint {
+	var pending []*VolumeCheckpoint
+
+	paths, _ := filepath.Glob(filepath.Join(journalDir(stateDir), "*.json"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var j Journal
+		if err := json.Unmarshal(data, &j); err != nil {
+			continue
+		}
+		for _, v := range j.Volumes {
+			if v.Status != "COMPLETED" {
+				pending = append(pending, v)
+			}
+		}
+	}
+
+	return pending
+}
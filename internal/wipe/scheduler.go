@@ -0,0 +1,169 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// WipeJob задание планировщика: затирание одного тома или каталога
+type WipeJob struct {
+	Disk       system.DiskInfo
+	Run        func(ctx context.Context) *WipeOperation
+	Checkpoint *VolumeCheckpoint // Контрольная точка тома (nil - без журнала)
+}
+
+// Scheduler запускает до MaxConcurrent заданий одновременно. Два задания,
+// тома которых лежат на одном физическом устройстве, никогда не выполняются
+// параллельно: это только замедлило бы оба из-за конкуренции за диск.
+type Scheduler struct {
+	MaxConcurrent int
+	logger        *logging.EnterpriseLogger
+}
+
+// NewScheduler создает планировщик; maxConcurrent < 1 означает последовательное выполнение
+func NewScheduler(maxConcurrent int, logger *logging.EnterpriseLogger) *Scheduler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Scheduler{
+		MaxConcurrent: maxConcurrent,
+		logger:        logger,
+	}
+}
+
+// jobResult результат выполнения задания
+type jobResult struct {
+	index int
+	op    *WipeOperation
+}
+
+// Run выполняет задания и возвращает операции в порядке заданий.
+// Отмена ctx прерывает выполняющиеся задания, а не начатые получают тот же
+// статус, что и прерванные (PARTIAL по max_duration, иначе CANCELLED), чтобы
+// каждая операция попала в отчет и журнал.
+func (s *Scheduler) Run(ctx context.Context, jobs []WipeJob) []*WipeOperation {
+	ops := make([]*WipeOperation, len(jobs))
+	if len(jobs) == 0 {
+		return ops
+	}
+
+	pending := make([]int, len(jobs))
+	for i := range jobs {
+		pending[i] = i
+	}
+
+	busy := make(map[string]bool)
+	results := make(chan jobResult)
+	running := 0
+
+	for len(pending) > 0 || running > 0 {
+		// Запускаем все задания, для которых свободны слот и физические устройства
+		if ctx.Err() == nil {
+			for i := 0; i < len(pending) && running < s.MaxConcurrent; {
+				idx := pending[i]
+				devices := physicalDevices(jobs[idx].Disk)
+				if anyBusy(busy, devices) {
+					i++
+					continue
+				}
+
+				for _, d := range devices {
+					busy[d] = true
+				}
+				pending = append(pending[:i], pending[i+1:]...)
+				running++
+
+				s.logger.Log("INFO", "Запуск задания затирания", "disk", jobs[idx].Disk.Target(),
+					"devices", strings.Join(devices, ","), "running", running, "max_concurrent", s.MaxConcurrent)
+
+				go func(idx int) {
+					op := jobs[idx].Run(ctx)
+					if op == nil {
+						op = failedOperation(jobs[idx].Disk, fmt.Errorf("задание не вернуло результат"))
+					}
+					results <- jobResult{index: idx, op: op}
+				}(idx)
+			}
+		}
+
+		if running == 0 {
+			// Контекст отменен - оставшиеся задания не запускаются
+			for _, idx := range pending {
+				ops[idx] = notStartedOperation(jobs[idx].Disk, context.Cause(ctx))
+				jobs[idx].Checkpoint.notStarted(ops[idx], s.logger)
+			}
+			break
+		}
+
+		res := <-results
+		running--
+		ops[res.index] = res.op
+		for _, d := range physicalDevices(jobs[res.index].Disk) {
+			delete(busy, d)
+		}
+	}
+
+	return ops
+}
+
+// unresolvedDevice - общее устройство томов с неизвестными физическими дисками
+const unresolvedDevice = "unresolved"
+
+// physicalDevices возвращает идентификаторы физических устройств тома.
+// Тома с неизвестными устройствами могут лежать на одном диске, поэтому
+// затираются по одному в общей группе unresolvedDevice.
+func physicalDevices(disk system.DiskInfo) []string {
+	if len(disk.BackingDevices) > 0 {
+		return disk.BackingDevices
+	}
+	return []string{unresolvedDevice}
+}
+
+// anyBusy проверяет, занято ли хотя бы одно из устройств
+func anyBusy(busy map[string]bool, devices []string) bool {
+	for _, d := range devices {
+		if busy[d] {
+			return true
+		}
+	}
+	return false
+}
+
+// notStartedOperation операция для задания, которое не было запущено из-за
+// отмены или истечения времени работы (причина cause)
+func notStartedOperation(disk system.DiskInfo, cause error) *WipeOperation {
+	now := time.Now()
+	op := &WipeOperation{
+		ID:        fmt.Sprintf("wipe_%d", now.UnixNano()),
+		Disk:      disk.Letter,
+		Path:      disk.Path,
+		StartTime: now,
+		EndTime:   &now,
+	}
+	op.interrupt(cause)
+	if op.Status == "PARTIAL" {
+		op.Warning = "Время работы истекло до запуска"
+	} else {
+		op.Warning = "Операция отменена до запуска"
+	}
+	return op
+}
+
+// failedOperation операция для задания, завершившегося без результата
+func failedOperation(disk system.DiskInfo, err error) *WipeOperation {
+	now := time.Now()
+	return &WipeOperation{
+		ID:        fmt.Sprintf("wipe_%d", now.UnixNano()),
+		Disk:      disk.Letter,
+		Path:      disk.Path,
+		Status:    "FAILED",
+		StartTime: now,
+		EndTime:   &now,
+		Error:     err.Error(),
+	}
+}
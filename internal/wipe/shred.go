@@ -0,0 +1,335 @@
+package wipe
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"wipedisk_enterprise/internal/logging"
+)
+
+// ShredOptions параметры уничтожения файлов
+type ShredOptions struct {
+	Method    WipeMethod
+	Passes    int  // Число проходов (см. PatternScheme.TotalPasses)
+	Recursive bool // Обрабатывать каталоги со всем содержимым
+	Renames   int  // Сколько раз переименовать файл перед удалением
+	DryRun    bool
+
+	Guard    func(path string) error // Проверка защищенных путей (nil - без проверки)
+	Limiter  *RateLimiter            // Ограничение скорости записи (nil - без ограничения)
+	Verifier *Verifier               // Проверка последнего прохода чтением (nil - только проходы с Verify)
+}
+
+// ShreddedFile описывает уничтоженный файл
+type ShreddedFile struct {
+	Path   string
+	Size   int64
+	Passes int
+}
+
+// ShredReport итоги уничтожения файлов
+type ShredReport struct {
+	Files            []ShreddedFile
+	Dirs             []string // Удаленные каталоги
+	BytesOverwritten uint64
+	Errors           []string
+	DryRun           bool
+}
+
+// shredNameChars - алфавит случайных имен при переименовании
+const shredNameChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Shred перезаписывает содержимое файлов по схеме метода, обрезает их,
+// несколько раз переименовывает и удаляет. patterns могут содержать маски
+// (filepath.Glob); каталоги обрабатываются только с Recursive. Ошибки по
+// отдельным путям собираются в отчет, обработка остальных продолжается.
+//
+// На SSD и файловых системах с копированием при записи (btrfs, ZFS, ReFS)
+// перезапись на месте не гарантирует затирание старых блоков: для них
+// нужно затирание свободного места.
+func Shred(ctx context.Context, patterns []string, opts ShredOptions, logger *logging.EnterpriseLogger) (*ShredReport, error) {
+	scheme, ok := LookupScheme(string(opts.Method))
+	if !ok {
+		return nil, fmt.Errorf("неизвестный метод затирания: %s", opts.Method)
+	}
+	if opts.Passes < 1 {
+		opts.Passes = 1
+	}
+	opts.Verifier = passVerifier(opts.Verifier, scheme, nil, logger)
+
+	report := &ShredReport{DryRun: opts.DryRun}
+	targets, errs := expandShredTargets(patterns)
+	report.Errors = append(report.Errors, errs...)
+
+	for _, target := range targets {
+		select {
+		case <-ctx.Done():
+			return report, fmt.Errorf("операция отменена")
+		default:
+		}
+
+		if err := shredPath(ctx, target, scheme, opts, report, logger); err != nil {
+			if ctx.Err() != nil {
+				return report, fmt.Errorf("операция отменена")
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", target, err))
+		}
+	}
+
+	logger.Log("INFO", "Уничтожение файлов завершено", "files", len(report.Files), "dirs", len(report.Dirs),
+		"bytes", report.BytesOverwritten, "errors", len(report.Errors), "method", scheme.Name, "dry_run", opts.DryRun)
+	return report, nil
+}
+
+// expandShredTargets раскрывает маски; путь без масок передается как есть
+func expandShredTargets(patterns []string) ([]string, []string) {
+	var targets, errs []string
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			matches, err = filepath.Glob(pattern)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: неверная маска: %v", pattern, err))
+				continue
+			}
+			if len(matches) == 0 {
+				errs = append(errs, fmt.Sprintf("%s: нет совпадений", pattern))
+				continue
+			}
+		}
+
+		for _, m := range matches {
+			m = filepath.Clean(m)
+			if !seen[m] {
+				seen[m] = true
+				targets = append(targets, m)
+			}
+		}
+	}
+	return targets, errs
+}
+
+// shredPath уничтожает файл или (с Recursive) каталог с содержимым
+func shredPath(ctx context.Context, path string, scheme *PatternScheme, opts ShredOptions, report *ShredReport, logger *logging.EnterpriseLogger) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if err := guardShredPath(path, opts); err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return shredEntry(ctx, path, info, scheme, opts, report, logger)
+	}
+	if !opts.Recursive {
+		return fmt.Errorf("это каталог (используйте --recursive)")
+	}
+
+	var dirs []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", p, walkErr))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			dirs = append(dirs, p)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err == nil {
+			err = guardShredPath(p, opts)
+		}
+		if err == nil {
+			err = shredEntry(ctx, p, info, scheme, opts, report, logger)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", p, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Каталоги удаляются от самых глубоких к корню
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		if opts.DryRun {
+			report.Dirs = append(report.Dirs, dir)
+			continue
+		}
+		if err := removeRenamed(dir, 1); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		report.Dirs = append(report.Dirs, dir)
+	}
+	return nil
+}
+
+func guardShredPath(path string, opts ShredOptions) error {
+	if opts.Guard == nil {
+		return nil
+	}
+	return opts.Guard(path)
+}
+
+// shredEntry уничтожает один элемент каталога. Символьные ссылки удаляются
+// без перезаписи цели, специальные файлы не обрабатываются.
+func shredEntry(ctx context.Context, path string, info os.FileInfo, scheme *PatternScheme, opts ShredOptions, report *ShredReport, logger *logging.EnterpriseLogger) error {
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeSymlink != 0:
+		if !opts.DryRun {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		logger.Log("INFO", "Символьная ссылка удалена без перезаписи цели", "path", path)
+		report.Files = append(report.Files, ShreddedFile{Path: path})
+		return nil
+	case !mode.IsRegular():
+		return fmt.Errorf("не обычный файл (%s)", mode.Type())
+	}
+
+	passes := scheme.TotalPasses(opts.Passes)
+	if opts.DryRun {
+		report.Files = append(report.Files, ShreddedFile{Path: path, Size: info.Size(), Passes: passes})
+		return nil
+	}
+
+	written, err := overwriteFile(ctx, path, info, scheme, opts)
+	report.BytesOverwritten += written
+	if err != nil {
+		return err
+	}
+
+	if err := removeRenamed(path, opts.Renames); err != nil {
+		return err
+	}
+
+	logger.Log("INFO", "Файл уничтожен", "path", path, "size", info.Size(), "method", scheme.Name, "passes", passes)
+	report.Files = append(report.Files, ShreddedFile{Path: path, Size: info.Size(), Passes: passes})
+	return nil
+}
+
+// overwriteFile перезаписывает содержимое файла на месте всеми проходами
+// схемы с fsync после каждого прохода и обрезает файл до нуля
+func overwriteFile(ctx context.Context, path string, info os.FileInfo, scheme *PatternScheme, opts ShredOptions) (uint64, error) {
+	// Файл только для чтения нужно сделать записываемым
+	if info.Mode().Perm()&0200 == 0 {
+		if err := os.Chmod(path, info.Mode().Perm()|0200); err != nil {
+			return 0, err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	size := uint64(info.Size())
+	writer := NewLimitedWriter(ctx, file, opts.Limiter)
+	buf := GetBuffer(scheme.ChunkSize)
+	defer PutBuffer(buf)
+
+	var total uint64
+	passes := scheme.TotalPasses(opts.Passes)
+	for pass := 0; pass < passes; pass++ {
+		spec := scheme.Pass(pass)
+		filler, err := spec.NewFiller()
+		if err != nil {
+			return total, fmt.Errorf("ошибка генерации паттерна: %w", err)
+		}
+		if _, err := file.Seek(0, 0); err != nil {
+			return total, err
+		}
+
+		for written := uint64(0); written < size; {
+			chunk := buf[:min(uint64(len(buf)), size-written)]
+			if err := filler.FillAt(chunk, written); err != nil {
+				return total, fmt.Errorf("ошибка генерации паттерна: %w", err)
+			}
+			n, err := writer.Write(chunk)
+			written += uint64(n)
+			total += uint64(n)
+			if err != nil {
+				return total, fmt.Errorf("ошибка записи (проход %d): %w", pass+1, err)
+			}
+		}
+
+		if err := writer.Sync(); err != nil {
+			return total, fmt.Errorf("ошибка синхронизации (проход %d): %w", pass+1, err)
+		}
+
+		if pass == passes-1 || spec.Verify {
+			if err := opts.Verifier.VerifyFile(ctx, path, size, filler, 0); err != nil {
+				return total, fmt.Errorf("ошибка проверки чтением: %w", err)
+			}
+		}
+	}
+
+	// Длина файла тоже не должна сохраниться в метаданных
+	if err := file.Truncate(0); err != nil {
+		return total, err
+	}
+	if err := file.Sync(); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// removeRenamed переименовывает путь renames раз в случайные имена той же
+// длины (чтобы в журнале файловой системы не осталось исходного имени) и удаляет его
+func removeRenamed(path string, renames int) error {
+	dir := filepath.Dir(path)
+	current := path
+	for i := 0; i < renames; i++ {
+		name, err := randomName(len(filepath.Base(path)))
+		if err != nil {
+			return err
+		}
+		next := filepath.Join(dir, name)
+		if _, err := os.Lstat(next); err == nil {
+			continue // Имя занято: пропускаем это переименование
+		}
+		if err := os.Rename(current, next); err != nil {
+			return fmt.Errorf("ошибка переименования: %w", err)
+		}
+		current = next
+	}
+	return os.Remove(current)
+}
+
+// randomName возвращает случайное имя длины n
+func randomName(n int) (string, error) {
+	if n < 1 {
+		n = 1
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = shredNameChars[int(b[i])%len(shredNameChars)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,106 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShred(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string // Пути относительно временного каталога
+		opts       ShredOptions
+		wantFiles  int
+		wantPasses int
+		wantBytes  uint64
+		wantErr    string   // Подстрока ошибки в отчете ("" - без ошибок)
+		wantLeft   []string // Пути, которые должны остаться
+	}{
+		{"файл", []string{"a.txt"}, ShredOptions{Method: MethodRandom}, 1, 1, 100,
+			"", []string{"b.bin", "dir/c.txt"}},
+		{"схема целиком", []string{"a.txt"}, ShredOptions{Method: MethodDOD5220, Passes: 1}, 1, 3, 300,
+			"", nil},
+		{"проходы сверх схемы", []string{"a.txt"}, ShredOptions{Method: MethodDOD5220, Passes: 5}, 1, 5, 500,
+			"", nil},
+		{"маска", []string{"*.txt", "a.txt"}, ShredOptions{Method: MethodZero}, 1, 1, 100,
+			"", []string{"b.bin"}},
+		{"каталог без recursive", []string{"dir"}, ShredOptions{Method: MethodZero}, 0, 0, 0,
+			"это каталог", []string{"dir/c.txt"}},
+		{"каталог рекурсивно", []string{"dir"}, ShredOptions{Method: MethodZero, Recursive: true}, 1, 1, 50,
+			"", []string{"a.txt"}},
+		{"тестовый режим", []string{"a.txt", "dir"}, ShredOptions{Method: MethodZero, Recursive: true, DryRun: true}, 2, 1, 0,
+			"", []string{"a.txt", "dir/c.txt"}},
+		{"защищенный путь", []string{"a.txt"}, ShredOptions{Method: MethodZero, Guard: func(string) error {
+			return errors.New("защищенный путь")
+		}}, 0, 0, 0, "защищенный путь", []string{"a.txt"}},
+		{"нет совпадений", []string{"*.log"}, ShredOptions{Method: MethodZero}, 0, 0, 0,
+			"нет совпадений", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			files := map[string]int{"a.txt": 100, "b.bin": 20, "dir/c.txt": 50}
+			for name, size := range files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var patterns []string
+			for _, p := range tt.patterns {
+				patterns = append(patterns, filepath.Join(root, p))
+			}
+			tt.opts.Renames = 2
+			report, err := Shred(context.Background(), patterns, tt.opts, newTestLogger(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(report.Files) != tt.wantFiles || report.BytesOverwritten != tt.wantBytes {
+				t.Errorf("файлов %d, байт %d, want %d и %d", len(report.Files), report.BytesOverwritten, tt.wantFiles, tt.wantBytes)
+			}
+			for _, f := range report.Files {
+				if f.Passes != tt.wantPasses {
+					t.Errorf("%s: проходов %d, want %d", f.Path, f.Passes, tt.wantPasses)
+				}
+				if _, err := os.Lstat(f.Path); tt.opts.DryRun == os.IsNotExist(err) {
+					t.Errorf("%s: наличие после уничтожения не соответствует режиму (dry-run %v)", f.Path, tt.opts.DryRun)
+				}
+			}
+			gotErr := strings.Join(report.Errors, "; ")
+			if (tt.wantErr == "") != (gotErr == "") || !strings.Contains(gotErr, tt.wantErr) {
+				t.Errorf("ошибки %q, want %q", gotErr, tt.wantErr)
+			}
+			for _, name := range tt.wantLeft {
+				if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+					t.Errorf("%s не должен быть удален: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestShredCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Shred(ctx, []string{path}, ShredOptions{Method: MethodZero}, newTestLogger(t)); err == nil {
+		t.Error("ожидалась ошибка отмены")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("файл удален после отмены: %v", err)
+	}
+}
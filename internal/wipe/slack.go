@@ -0,0 +1,240 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// errFileInUse - файл открыт другим процессом
+var errFileInUse = errors.New("файл открыт другим процессом")
+
+// SlackOptions параметры затирания хвостов кластеров
+type SlackOptions struct {
+	DryRun bool
+
+	Guard func(path string) error // Проверка защищенных путей (nil - без проверки)
+}
+
+// SlackFile описывает файл с затертым хвостом последнего кластера
+type SlackFile struct {
+	Path  string
+	Size  int64
+	Slack uint64
+}
+
+// SkippedFile описывает пропущенный файл
+type SkippedFile struct {
+	Path   string
+	Reason string
+}
+
+// SlackReport итоги затирания хвостов кластеров
+type SlackReport struct {
+	Files      []SlackFile
+	SlackBytes uint64
+	Skipped    []SkippedFile
+	Errors     []string
+	DryRun     bool
+}
+
+// WipeSlack затирает неиспользуемый хвост последнего кластера (slack space)
+// у файлов по путям patterns: файл дописывается случайными данными до
+// границы кластера, сбрасывается на диск и обрезается до исходной длины.
+// Каталоги обходятся рекурсивно. Время изменения и доступа восстанавливается,
+// время изменения метаданных (ctime) сохранить нельзя.
+//
+// Открытые другими процессами, защищенные, разреженные и сжатые файлы
+// пропускаются. На файловых системах с копированием при записи хвост
+// записывается в новый блок, и старый кластер не затирается.
+func WipeSlack(ctx context.Context, patterns []string, opts SlackOptions, logger *logging.EnterpriseLogger) (*SlackReport, error) {
+	report := &SlackReport{DryRun: opts.DryRun}
+	targets, errs := expandShredTargets(patterns)
+	report.Errors = append(report.Errors, errs...)
+
+	w := &slackWiper{
+		opts:     opts,
+		report:   report,
+		logger:   logger,
+		clusters: make(map[string]uint64),
+		open:     newOpenFileIndex(),
+	}
+
+	for _, target := range targets {
+		err := filepath.WalkDir(target, func(p string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", p, walkErr))
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if d.IsDir() {
+				if err := guardSlackPath(p, opts); err != nil {
+					report.Skipped = append(report.Skipped, SkippedFile{Path: p, Reason: err.Error()})
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil // Символьные ссылки и специальные файлы не обрабатываются
+			}
+			w.wipeFile(p)
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, fmt.Errorf("операция отменена")
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", target, err))
+		}
+	}
+
+	logger.Log("INFO", "Затирание хвостов кластеров завершено", "files", len(report.Files),
+		"slack_bytes", report.SlackBytes, "skipped", len(report.Skipped), "errors", len(report.Errors), "dry_run", opts.DryRun)
+	return report, nil
+}
+
+func guardSlackPath(path string, opts SlackOptions) error {
+	if opts.Guard == nil {
+		return nil
+	}
+	return opts.Guard(path)
+}
+
+// slackWiper хранит состояние одного запуска WipeSlack
+type slackWiper struct {
+	opts     SlackOptions
+	report   *SlackReport
+	logger   *logging.EnterpriseLogger
+	clusters map[string]uint64 // Размер кластера по каталогам
+	open     openFileIndex
+}
+
+// wipeFile затирает хвост одного файла; результат записывается в отчет
+func (w *slackWiper) wipeFile(path string) {
+	skip := func(reason string) {
+		w.report.Skipped = append(w.report.Skipped, SkippedFile{Path: path, Reason: reason})
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		w.report.Errors = append(w.report.Errors, fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+	if err := guardSlackPath(path, w.opts); err != nil {
+		skip(err.Error())
+		return
+	}
+	if info.Size() == 0 {
+		return
+	}
+	if reason := slackSkipReason(info); reason != "" {
+		skip(reason)
+		return
+	}
+
+	cluster, err := w.clusterSize(filepath.Dir(path))
+	if err != nil {
+		w.report.Errors = append(w.report.Errors, fmt.Sprintf("%s: размер кластера: %v", path, err))
+		return
+	}
+	slack := cluster - uint64(info.Size())%cluster
+	if slack == cluster {
+		return // Файл заканчивается ровно на границе кластера
+	}
+
+	if w.opts.DryRun {
+		w.report.Files = append(w.report.Files, SlackFile{Path: path, Size: info.Size(), Slack: slack})
+		w.report.SlackBytes += slack
+		return
+	}
+
+	if err := overwriteSlack(path, info, slack, w.open); err != nil {
+		if errors.Is(err, errFileInUse) {
+			skip(err.Error())
+			return
+		}
+		w.report.Errors = append(w.report.Errors, fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+
+	w.logger.Log("DEBUG", "Хвост кластера затерт", "path", path, "size", info.Size(), "slack", slack)
+	w.report.Files = append(w.report.Files, SlackFile{Path: path, Size: info.Size(), Slack: slack})
+	w.report.SlackBytes += slack
+}
+
+// clusterSize возвращает размер кластера тома каталога dir с кешированием
+func (w *slackWiper) clusterSize(dir string) (uint64, error) {
+	if size, ok := w.clusters[dir]; ok {
+		return size, nil
+	}
+	size, err := system.ClusterSize(dir)
+	if err != nil {
+		return 0, err
+	}
+	if size == 0 {
+		return 0, fmt.Errorf("файловая система не сообщила размер кластера")
+	}
+	w.clusters[dir] = size
+	return size, nil
+}
+
+// overwriteSlack дописывает slack случайных байт за концом файла, сбрасывает
+// их на диск и обрезает файл до исходной длины, восстанавливая время
+// изменения и доступа
+func overwriteSlack(path string, info os.FileInfo, slack uint64, open openFileIndex) (err error) {
+	size := info.Size()
+	atime := fileAccessTime(info)
+
+	// Файл только для чтения временно делается записываемым
+	perm := info.Mode().Perm()
+	if perm&0200 == 0 {
+		if err := os.Chmod(path, perm|0200); err != nil {
+			return err
+		}
+		defer func() {
+			if chErr := os.Chmod(path, perm); chErr != nil && err == nil {
+				err = chErr
+			}
+		}()
+	}
+
+	file, err := openSlackFile(path, info, open)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Файл не должен остаться удлиненным ни при какой ошибке
+		if truncErr := file.Truncate(size); truncErr != nil && err == nil {
+			err = truncErr
+		}
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chtimes(path, atime, info.ModTime())
+		}
+	}()
+
+	buf := make([]byte, slack)
+	if err := FillRandom(buf); err != nil {
+		return fmt.Errorf("ошибка генерации данных: %w", err)
+	}
+	if _, err := file.WriteAt(buf, size); err != nil {
+		return fmt.Errorf("ошибка записи: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("ошибка синхронизации: %w", err)
+	}
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("ошибка обрезки: %w", err)
+	}
+	return file.Sync()
+}
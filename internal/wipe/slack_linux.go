@@ -0,0 +1,91 @@
+//go:build linux
+
+package wipe
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// fileKey идентифицирует файл по устройству и inode
+type fileKey struct {
+	dev uint64
+	ino uint64
+}
+
+// openFileIndex - снимок файлов, открытых процессами системы, по /proc/*/fd.
+// Без прав root видны только файлы процессов текущего пользователя.
+type openFileIndex map[fileKey]struct{}
+
+// newOpenFileIndex собирает файлы, открытые другими процессами
+func newOpenFileIndex() openFileIndex {
+	index := make(openFileIndex)
+	self := strconv.Itoa(os.Getpid())
+
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		if filepath.Base(filepath.Dir(filepath.Dir(fd))) == self {
+			continue
+		}
+		info, err := os.Stat(fd)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			index[fileKey{dev: uint64(st.Dev), ino: st.Ino}] = struct{}{}
+		}
+	}
+	return index
+}
+
+// openSlackFile открывает файл на запись, если он не открыт другим
+// процессом и не заблокирован через flock
+func openSlackFile(path string, info os.FileInfo, open openFileIndex) (*os.File, error) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		if _, busy := open[fileKey{dev: uint64(st.Dev), ino: st.Ino}]; busy {
+			return nil, errFileInUse
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errFileInUse
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// fileAccessTime возвращает время последнего доступа к файлу
+func fileAccessTime(info os.FileInfo) time.Time {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Atim.Sec, st.Atim.Nsec)
+	}
+	return info.ModTime()
+}
+
+// slackSkipReason возвращает причину, по которой у файла нельзя затереть
+// хвост кластера, или пустую строку
+func slackSkipReason(info os.FileInfo) string {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	allocated := st.Blocks * 512
+	switch {
+	case allocated == 0:
+		return "данные хранятся в inode"
+	case allocated < info.Size():
+		return "разреженный или сжатый файл"
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+//go:build !linux && !windows
+
+package wipe
+
+import (
+	"os"
+	"time"
+)
+
+// openFileIndex - определение занятых файлов поддерживается только в Linux и Windows
+type openFileIndex struct{}
+
+func newOpenFileIndex() openFileIndex {
+	return openFileIndex{}
+}
+
+func openSlackFile(path string, info os.FileInfo, _ openFileIndex) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
+
+func fileAccessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
+
+func slackSkipReason(info os.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,95 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/system"
+)
+
+func TestWipeSlack(t *testing.T) {
+	root := t.TempDir()
+	cluster, err := system.ClusterSize(root)
+	if err != nil || cluster == 0 {
+		t.Skipf("размер кластера не определен: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		size      int64
+		perm      os.FileMode
+		opts      SlackOptions
+		wantSlack uint64 // 0 - файл не попадает в отчет
+		wantSkip  string // Подстрока причины пропуска
+	}{
+		{"хвост кластера", 100, 0644, SlackOptions{}, cluster - 100, ""},
+		{"файл только для чтения", 100, 0444, SlackOptions{}, cluster - 100, ""},
+		{"ровно кластер", int64(cluster), 0644, SlackOptions{}, 0, ""},
+		{"пустой файл", 0, 0644, SlackOptions{}, 0, ""},
+		{"тестовый режим", 100, 0644, SlackOptions{DryRun: true}, cluster - 100, ""},
+		{"защищенный путь", 100, 0644, SlackOptions{Guard: func(string) error {
+			return errors.New("защищенный путь")
+		}}, 0, "защищенный путь"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.bin")
+			data := make([]byte, tt.size)
+			if err := FillRandom(data); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, tt.perm); err != nil {
+				t.Fatal(err)
+			}
+			mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := WipeSlack(context.Background(), []string{filepath.Dir(path)}, tt.opts, newTestLogger(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Errors) > 0 {
+				t.Fatalf("ошибки: %v", report.Errors)
+			}
+
+			var gotSlack uint64
+			if len(report.Files) == 1 {
+				gotSlack = report.Files[0].Slack
+			}
+			if gotSlack != tt.wantSlack || report.SlackBytes != tt.wantSlack {
+				t.Errorf("хвост %d (всего %d), want %d", gotSlack, report.SlackBytes, tt.wantSlack)
+			}
+			gotSkip := ""
+			for _, s := range report.Skipped {
+				gotSkip += s.Reason
+			}
+			if (tt.wantSkip == "") != (gotSkip == "") || !strings.Contains(gotSkip, tt.wantSkip) {
+				t.Errorf("пропуски %q, want %q", gotSkip, tt.wantSkip)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("содержимое файла изменилось: %d байт, want %d", len(got), len(data))
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !info.ModTime().Equal(mtime) || info.Mode().Perm() != tt.perm {
+				t.Errorf("время %v и права %v, want %v и %v", info.ModTime(), info.Mode().Perm(), mtime, tt.perm)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package wipe
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// ntfsResidentLimit - файлы меньше этого размера NTFS обычно хранит прямо
+// в записи MFT, кластеров у них нет
+const ntfsResidentLimit = 1024
+
+// openFileIndex не нужен в Windows: занятость файла определяется
+// открытием без общего доступа
+type openFileIndex struct{}
+
+func newOpenFileIndex() openFileIndex {
+	return openFileIndex{}
+}
+
+// openSlackFile открывает файл монопольно (без общего доступа):
+// если файл открыт другим процессом, возвращается errFileInUse
+func openSlackFile(path string, info os.FileInfo, _ openFileIndex) (*os.File, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := windows.CreateFile(name, windows.GENERIC_WRITE, 0, nil,
+		windows.OPEN_EXISTING, windows.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+			return nil, errFileInUse
+		}
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(handle), path), nil
+}
+
+// fileAccessTime возвращает время последнего доступа к файлу
+func fileAccessTime(info os.FileInfo) time.Time {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.LastAccessTime.Nanoseconds())
+	}
+	return info.ModTime()
+}
+
+// slackSkipReason возвращает причину, по которой у файла нельзя затереть
+// хвост кластера, или пустую строку
+func slackSkipReason(info os.FileInfo) string {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		switch {
+		case data.FileAttributes&windows.FILE_ATTRIBUTE_SPARSE_FILE != 0:
+			return "разреженный файл"
+		case data.FileAttributes&windows.FILE_ATTRIBUTE_COMPRESSED != 0:
+			return "сжатый файл"
+		case data.FileAttributes&windows.FILE_ATTRIBUTE_ENCRYPTED != 0:
+			return "зашифрованный файл (EFS)"
+		}
+	}
+	if info.Size() < ntfsResidentLimit {
+		return "данные хранятся в записи MFT"
+	}
+	return ""
+}
@@ -2,13 +2,11 @@ package wipe
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
-	"os"
 	"time"
 
+	"wipedisk_enterprise/internal/config"
 	"wipedisk_enterprise/internal/logging"
-	"wipedisk_enterprise/internal/system"
 )
 
 // WipeMode определяет режим затирания
@@ -195,211 +193,39 @@ func (p CipherPass) String() string {
 
 // CipherPattern генерирует паттерн для cipher прохода
 func CipherPattern(pass CipherPass, size int) ([]byte, error) {
-	buf := make([]byte, size)
-
-	switch pass {
-	case CipherPassZero:
-	case CipherPassFF:
-		for i := range buf {
-			buf[i] = 0xFF
-		}
-	case CipherPassRandom:
-		if _, err := rand.Read(buf); err != nil {
-			return nil, fmt.Errorf("ошибка генерации случайных данных: %w", err)
-		}
-	default:
+	if pass < CipherPassZero || pass > CipherPassRandom {
 		return nil, fmt.Errorf("неизвестный проход cipher: %d", pass)
 	}
-
-	return buf, nil
+	return FillPattern(MethodCipher, int(pass), size)
 }
 
-// ExecuteWipeWithStrategy выполняет затирание с использованием стратегии
-func ExecuteWipeWithStrategy(ctx context.Context, disk system.DiskInfo, cfg *WipeConfig, logger *logging.EnterpriseLogger, mode WipeMode, profile string) (*WipeOperation, error) {
-	strategy := GetStrategy(mode)
-
-	op := &WipeOperation{
-		ID:        fmt.Sprintf("wipe_%d", time.Now().UnixNano()),
-		Disk:      disk.Letter,
-		Method:    string(mode),
-		Passes:    cfg.Passes,
-		ChunkSize: int64(strategy.GetFileSize(disk.Type, profile)),
-		Status:    "RUNNING",
-		StartTime: time.Now(),
-	}
-
-	logger.Log("INFO", "Запуск затирания", "disk", disk.Letter, "mode", mode, "profile", profile, "strategy", fmt.Sprintf("%T", strategy))
-
-	passes := cfg.Passes
-	if mode == ModeCipher {
-		passes = 3
-	}
-
-	for pass := 0; pass < passes; pass++ {
-		// Проверка контекста
-		select {
-		case <-ctx.Done():
-			if ctx.Err() == context.DeadlineExceeded {
-				op.Status = "PARTIAL"
-				op.Warning = "Операция прервана по таймауту"
-			} else {
-				op.Status = "CANCELLED"
-				op.Warning = "Операция отменена пользователем"
-			}
-			return op, fmt.Errorf("операция прервана")
-		default:
-		}
-
-		var err error
-		if mode == ModeCipher {
-			cipherPass := CipherPass(pass)
-			err = executeCipherPass(ctx, disk, cfg, logger, strategy, cipherPass, profile)
-			logger.Log("INFO", "Cipher проход завершен", "disk", disk.Letter, "pass", cipherPass.String(), "error", err)
-		} else {
-			err = executeStandardPass(ctx, disk, cfg, logger, strategy, profile, pass)
-			logger.Log("INFO", "Проход завершен", "disk", disk.Letter, "pass", pass+1, "total", passes, "error", err)
-		}
-
-		if err != nil {
-			if ctx.Err() != nil {
-				if ctx.Err() == context.DeadlineExceeded {
-					op.Status = "PARTIAL"
-					op.Warning = "Операция прервана по таймауту"
-				} else {
-					op.Status = "CANCELLED"
-					op.Warning = "Операция отменена пользователем"
-				}
-			} else {
-				op.Status = "FAILED"
-				op.Error = err.Error()
-			}
-			return op, err
+// createPatternFile создает большой файл с последовательной записью паттерна прохода
+// и, если включена проверка, перечитывает его.
+// baseOffset - смещение файла в потоке данных прохода.
+func createPatternFile(ctx context.Context, filename string, fileSize uint64, cfg *WipeConfig, syncInterval uint64, chunkSize int, filler PassFiller, baseOffset uint64, logger *logging.EnterpriseLogger) (uint64, error) {
+	file, isDirect, err := openWipeFile(filename, cfg.DirectIO)
+	if err != nil {
+		if isDiskFullError(err) {
+			return 0, ErrDiskFull
 		}
+		return 0, err
 	}
-
-	// Успешное завершение
-	now := time.Now()
-	op.EndTime = &now
-	op.Status = "COMPLETED"
-	op.BytesWiped = disk.FreeSize
-	if op.EndTime.Sub(op.StartTime).Seconds() > 0 {
-		op.SpeedMBps = float64(op.BytesWiped) / (1024 * 1024) / op.EndTime.Sub(op.StartTime).Seconds()
+	defer func() { file.Close() }()
+	if cfg.DirectIO && !isDirect {
+		logger.Log("DEBUG", "Прямой ввод-вывод недоступен, запись через кэш ОС", "file", filename)
 	}
 
-	logger.Log("INFO", "Затирание завершено", "disk", disk.Letter, "bytes", op.BytesWiped, "speed", op.SpeedMBps)
-	return op, nil
-}
-
-// executeStandardPass выполняет стандартный проход затирания
-func executeStandardPass(ctx context.Context, disk system.DiskInfo, cfg *WipeConfig, logger *logging.EnterpriseLogger, strategy WipeStrategy, profile string, passNum int) error {
-	freeSpace := disk.FreeSize
-	minFreeSpace := strategy.GetMinFreeSpace()
-	maxFiles := strategy.GetMaxFiles()
-	fileSize := strategy.GetFileSize(disk.Type, profile)
-	syncInterval := strategy.GetSyncInterval()
-
-	fileIndex := 0
-
-	for freeSpace > minFreeSpace && fileIndex < maxFiles {
-		// Проверка контекста
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("операция отменена")
-		default:
-		}
-
-		// Определяем размер файла
-		currentFileSize := fileSize
-		if freeSpace < currentFileSize {
-			currentFileSize = freeSpace - minFreeSpace
-			if currentFileSize < 64*1024*1024 { // Минимальный размер файла 64MB
-				break
-			}
-		}
-
-		// Создаем и заполняем файл
-		filename := fmt.Sprintf("%swipe_%03d.tmp", disk.Letter, fileIndex)
-		err := createLargeFile(ctx, filename, currentFileSize, cfg.MaxSpeedMBps, syncInterval, logger)
-		if err != nil {
-			return fmt.Errorf("ошибка создания файла %s: %w", filename, err)
-		}
-
-		// Удаляем файл
-		if err := os.Remove(filename); err != nil {
-			logger.Log("WARN", "Ошибка удаления файла", "file", filename, "error", err.Error())
-		}
-
-		freeSpace -= currentFileSize
-		fileIndex++
+	throttledWriter := NewLimitedWriter(ctx, file, cfg.Limiter)
+	throttledWriter.SetDirectIO(isDirect)
+	if isDirect {
+		// Невыровненный блок допустим только последним: иначе вторая запись
+		// упрется в обрезанный хвост файла
+		chunkSize = alignUp(chunkSize)
 	}
 
-	return nil
-}
-
-// executeCipherPass выполняет проход cipher с указанным паттерном
-func executeCipherPass(ctx context.Context, disk system.DiskInfo, cfg *WipeConfig, logger *logging.EnterpriseLogger, strategy WipeStrategy, pass CipherPass, profile string) error {
-	freeSpace := disk.FreeSize
-	minFreeSpace := strategy.GetMinFreeSpace()
-	maxFiles := strategy.GetMaxFiles()
-	fileSize := strategy.GetFileSize(disk.Type, profile)
-	syncInterval := strategy.GetSyncInterval()
-
-	fileIndex := 0
-
-	for freeSpace > minFreeSpace && fileIndex < maxFiles {
-		// Проверка контекста
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("операция отменена")
-		default:
-		}
-
-		// Определяем размер файла
-		currentFileSize := fileSize
-		if freeSpace < currentFileSize {
-			currentFileSize = freeSpace - minFreeSpace
-			if currentFileSize < 64*1024*1024 { // Минимальный размер файла 64MB
-				break
-			}
-		}
-
-		// Создаем и заполняем файл с cipher паттерном
-		filename := fmt.Sprintf("%scipher_%03d_%s.tmp", disk.Letter, fileIndex, pass.String())
-		err := createCipherFile(ctx, filename, currentFileSize, cfg.MaxSpeedMBps, syncInterval, pass, logger)
-		if err != nil {
-			return fmt.Errorf("ошибка создания cipher файла %s: %w", filename, err)
-		}
-
-		// Удаляем файл
-		if err := os.Remove(filename); err != nil {
-			logger.Log("WARN", "Ошибка удаления cipher файла", "file", filename, "error", err.Error())
-		}
-
-		freeSpace -= currentFileSize
-		fileIndex++
-	}
-
-	return nil
-}
-
-// createLargeFile создает большой файл с последовательной записью
-func createLargeFile(ctx context.Context, filename string, fileSize uint64, maxSpeedMBps float64, syncInterval uint64, logger *logging.EnterpriseLogger) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	throttledWriter := NewThrottledWriter(file, maxSpeedMBps)
-
 	// Используем большой буфер для последовательной записи
-	chunkSize := 16 * 1024 * 1024 // 16MB chunks
-	buf := make([]byte, chunkSize)
-
-	// Заполняем буфер случайными данными один раз
-	if _, err := rand.Read(buf); err != nil {
-		return fmt.Errorf("ошибка генерации данных: %w", err)
-	}
+	buf := GetBuffer(chunkSize)
+	defer PutBuffer(buf)
 
 	var written uint64
 	lastSync := uint64(0)
@@ -408,71 +234,26 @@ func createLargeFile(ctx context.Context, filename string, fileSize uint64, maxS
 		// Проверка контекста
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("операция отменена")
+			return written, fmt.Errorf("операция отменена: %w", ctx.Err())
 		default:
 		}
 
-		remaining := fileSize - written
-		toWrite := uint64(chunkSize)
-		if remaining < toWrite {
-			toWrite = remaining
-		}
-
-		// Записываем данные
-		off := 0
-		for off < int(toWrite) {
-			n, err := throttledWriter.Write(buf[off:int(toWrite)])
-			if n > 0 {
-				off += n
-				written += uint64(n)
+		// Пауза: файл сбрасывается и закрывается, буфер остается за писателем
+		if cfg.Pause.Paused() {
+			if err := throttledWriter.Sync(); err != nil {
+				return written, fmt.Errorf("ошибка синхронизации: %w", err)
 			}
-			if err != nil {
-				return fmt.Errorf("ошибка записи: %w", err)
+			file.Close()
+			logger.Log("INFO", "Запись приостановлена", "file", filename, "bytes", written)
+			if err := holdPause(ctx, cfg.Pause, cfg.progress); err != nil {
+				return written, fmt.Errorf("операция отменена: %w", err)
 			}
-			if n == 0 {
-				return fmt.Errorf("запись вернула 0 байт")
-			}
-		}
-
-		// Периодический sync
-		if syncInterval > 0 && written-lastSync >= syncInterval {
-			if err := file.Sync(); err != nil {
-				return fmt.Errorf("ошибка синхронизации: %w", err)
+			file, isDirect, err = reopenWipeFile(filename, isDirect, int64(written))
+			if err != nil {
+				return written, fmt.Errorf("ошибка открытия файла после паузы: %w", err)
 			}
-			lastSync = written
-		}
-	}
-
-	// Финальный sync
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("ошибка финальной синхронизации: %w", err)
-	}
-
-	return nil
-}
-
-// createCipherFile создает файл с cipher паттерном
-func createCipherFile(ctx context.Context, filename string, fileSize uint64, maxSpeedMBps float64, syncInterval uint64, pass CipherPass, logger *logging.EnterpriseLogger) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	throttledWriter := NewThrottledWriter(file, maxSpeedMBps)
-
-	// Используем большой буфер для последовательной записи
-	chunkSize := 16 * 1024 * 1024 // 16MB chunks
-
-	var written uint64
-	lastSync := uint64(0)
-
-	for written < fileSize {
-		// Проверка контекста
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("операция отменена")
-		default:
+			throttledWriter.Reopen(file, isDirect)
+			logger.Log("INFO", "Запись возобновлена", "file", filename, "bytes", written)
 		}
 
 		remaining := fileSize - written
@@ -482,47 +263,94 @@ func createCipherFile(ctx context.Context, filename string, fileSize uint64, max
 		}
 
 		// Генерируем паттерн для этого чанка
-		pattern, err := CipherPattern(pass, int(toWrite))
-		if err != nil {
-			return fmt.Errorf("ошибка генерации паттерна: %w", err)
+		chunk := buf[:toWrite]
+		if err := filler.FillAt(chunk, baseOffset+written); err != nil {
+			return written, fmt.Errorf("ошибка генерации паттерна: %w", err)
+		}
+		if written == 0 {
+			cfg.Marker.mark(chunk, filename)
 		}
 
 		// Записываем данные
 		off := 0
 		for off < int(toWrite) {
-			n, err := throttledWriter.Write(pattern[off:int(toWrite)])
+			n, err := throttledWriter.Write(chunk[off:])
 			if n > 0 {
 				off += n
 				written += uint64(n)
+				cfg.progress.add(uint64(n))
 			}
 			if err != nil {
-				return fmt.Errorf("ошибка записи: %w", err)
+				if isDiskFullError(err) {
+					return finishPartialFile(ctx, throttledWriter, filename, written, cfg, filler, baseOffset, logger)
+				}
+				return written, fmt.Errorf("ошибка записи: %w", err)
 			}
 			if n == 0 {
-				return fmt.Errorf("запись вернула 0 байт")
+				return written, fmt.Errorf("запись вернула 0 байт")
 			}
 		}
 
 		// Периодический sync
 		if syncInterval > 0 && written-lastSync >= syncInterval {
-			if err := file.Sync(); err != nil {
-				return fmt.Errorf("ошибка синхронизации: %w", err)
+			if err := throttledWriter.Sync(); err != nil {
+				return written, fmt.Errorf("ошибка синхронизации: %w", err)
 			}
 			lastSync = written
 		}
 	}
 
 	// Финальный sync
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("ошибка финальной синхронизации: %w", err)
+	if err := throttledWriter.Sync(); err != nil {
+		return written, fmt.Errorf("ошибка финальной синхронизации: %w", err)
+	}
+
+	if err := cfg.Verifier.VerifyFile(ctx, filename, written, filler, baseOffset); err != nil {
+		return written, fmt.Errorf("ошибка проверки чтением: %w", err)
 	}
 
-	return nil
+	return written, nil
+}
+
+// finishPartialFile завершает файл, запись которого остановило заполнение
+// тома: записанная часть сбрасывается на диск и проверяется чтением
+func finishPartialFile(ctx context.Context, w *ThrottledWriter, filename string, written uint64, cfg *WipeConfig, filler PassFiller, baseOffset uint64, logger *logging.EnterpriseLogger) (uint64, error) {
+	if err := w.Sync(); err != nil {
+		logger.Log("DEBUG", "Синхронизация заполнившего том файла не выполнена", "file", filename, "error", err.Error())
+		return written, ErrDiskFull
+	}
+	if err := cfg.Verifier.VerifyFile(ctx, filename, written, filler, baseOffset); err != nil {
+		return written, fmt.Errorf("ошибка проверки чтением: %w", err)
+	}
+	return written, ErrDiskFull
 }
 
 // WipeConfig конфигурация для затирания
 type WipeConfig struct {
-	Passes       int
+	Method       WipeMethod
+	Passes       int // Число проходов из ssd_passes/hdd_passes (см. PatternScheme.TotalPasses)
 	MaxSpeedMBps float64
 	MaxDuration  time.Duration
+	Checkpoint   *VolumeCheckpoint // Контрольная точка в журнале запуска (nil - без журнала)
+	Limiter      *RateLimiter      // Общий лимитер диска (nil - собственный на MaxSpeedMBps)
+	Adaptive     config.AdaptiveThrottleConfig
+	DirectIO     bool            // Запись в обход кэша ОС
+	Verifier     *Verifier       // Проверка файлов чтением перед удалением (nil - только проходы с Verify)
+	Marker       *ArtifactMarker // Подпись файлов затирания для recover (nil - без маркера)
+	ChunkSize    int             // Размер блока записи, 0 - из схемы метода
+	SyncInterval uint64          // Период fsync при записи файла, 0 - из стратегии
+
+	MetadataScrub config.MetadataScrubConfig // Очистка метаданных после проходов
+	EnableTrim    bool                       // TRIM файлов и тома после затирания (только SSD)
+
+	Profile   string              // Профиль для размера файлов стратегии
+	FileDelay time.Duration       // Пауза между файлами
+	DryRun    bool                // Только расчет, без записи
+	Writer    FileWriter          // Запись файлов (nil - последовательная запись паттерна)
+	Progress  chan<- ProgressInfo // Прогресс затирания (nil - без прогресса)
+	Pause     *PauseController    // Пауза и возобновление (nil - без паузы)
+
+	scheme   *PatternScheme // Собственная схема вместо схемы метода
+	discard  *discarder
+	progress *progressTracker
 }
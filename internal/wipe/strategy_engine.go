@@ -10,67 +10,79 @@ import (
 	"wipedisk_enterprise/internal/system"
 )
 
-// WipeWithStrategy выполняет затирание с использованием стратегии
-func WipeWithStrategy(ctx context.Context, disk system.DiskInfo, cfg *config.Config, logger *logging.EnterpriseLogger, dryRun bool, maxDuration time.Duration, mode WipeMode, profile string) *WipeOperation {
-	// Создаем конфигурацию для затирания
+// WipeWithStrategy выполняет затирание с использованием стратегии.
+// checkpoint - контрольная точка тома в журнале запуска; nil отключает журнал.
+// progress получает прогресс затирания (nil - без прогресса), pause
+// приостанавливает запись (nil - без паузы).
+func WipeWithStrategy(ctx context.Context, disk system.DiskInfo, cfg *config.Config, logger *logging.EnterpriseLogger, dryRun bool, maxDuration time.Duration, mode WipeMode, profile string, checkpoint *VolumeCheckpoint, progress chan<- ProgressInfo, pause *PauseController) *WipeOperation {
+	// Лимит max_speed_mbps общий для всех параллельно затираемых томов
+	Limiters().Configure(cfg.Wipe.MaxSpeedMBps, cfg.Wipe.DiskSpeedMBps)
+
+	wipeConfig := NewWipeConfig(cfg, disk, mode, logger)
+	wipeConfig.MaxDuration = maxDuration
+	wipeConfig.Checkpoint = checkpoint
+	wipeConfig.Profile = config.BaseProfile(cfg, profile) // Пользовательский профиль - по встроенному в основе
+	wipeConfig.DryRun = dryRun
+	wipeConfig.Progress = progress
+	wipeConfig.Pause = pause
+	if checkpoint != nil && checkpoint.Method != "" {
+		wipeConfig.Method = WipeMethod(checkpoint.Method) // Продолжение тем же методом
+	}
+	if wipeConfig.Profile == config.TunedProfile {
+		// Размер блока и период fsync, подобранные wipedisk benchmark
+		wipeConfig.ChunkSize = cfg.Wipe.Tuned.ChunkSize
+		wipeConfig.SyncInterval = uint64(cfg.Wipe.Tuned.SyncIntervalMB) * 1024 * 1024
+	}
+
+	logger.Log("INFO", "Запуск затирания со стратегией", "disk", disk.Letter, "mode", mode, "method", wipeConfig.Method, "profile", profile, "passes", wipeConfig.Passes)
+
+	return RunFreeSpaceWipe(ctx, disk, GetStrategy(mode), wipeConfig, logger)
+}
+
+// NewWipeConfig собирает настройки конвейера из конфигурации для тома disk
+// и режима mode. Ограничение скорости берется из общего лимитера диска,
+// ключ маркеров - из каталога состояния wipe.state_dir.
+func NewWipeConfig(cfg *config.Config, disk system.DiskInfo, mode WipeMode, logger *logging.EnterpriseLogger) *WipeConfig {
+	marker := loadMarker(cfg.Wipe.StateDir, logger)
 	wipeConfig := &WipeConfig{
-		Passes:       getPassesForMode(cfg, mode),
+		Method:       MethodForDisk(cfg.Wipe.SSDMethod, cfg.Wipe.HDDMethod, disk.Type),
+		Passes:       getPassesForMode(cfg, mode, disk.Type),
 		MaxSpeedMBps: cfg.Wipe.MaxSpeedMBps,
-		MaxDuration:  maxDuration,
-	}
+		Limiter:      Limiters().ForDisk(disk.Letter),
+		Adaptive:     cfg.Wipe.Adaptive,
+		DirectIO:     cfg.Wipe.DirectIO,
+		Verifier:     NewVerifier(cfg.Wipe.Verify, marker, logger),
+		Marker:       marker,
 
-	logger.Log("INFO", "Запуск затирания со стратегией", "disk", disk.Letter, "mode", mode, "profile", profile, "passes", wipeConfig.Passes)
+		MetadataScrub: cfg.Wipe.MetadataScrub,
+		EnableTrim:    cfg.Wipe.EnableTrim && disk.Type == "SSD",
 
-	if dryRun {
-		op := &WipeOperation{
-			ID:        fmt.Sprintf("strategy_%d", time.Now().UnixNano()),
-			Disk:      disk.Letter,
-			Method:    string(mode),
-			Passes:    wipeConfig.Passes,
-			ChunkSize: int64(GetStrategy(mode).GetFileSize(disk.Type, profile)),
-			Status:    "COMPLETED",
-			StartTime: time.Now(),
-		}
-		now := time.Now()
-		op.EndTime = &now
-		op.BytesWiped = disk.FreeSize
-		op.SpeedMBps = 25.0 // Примерная скорость
-		logger.Log("INFO", "DRY RUN: затирание со стратегией завершено", "disk", disk.Letter, "bytes", op.BytesWiped)
-		return op
+		FileDelay: time.Duration(cfg.Wipe.FileDelayMs) * time.Millisecond,
 	}
 
-	// Выполняем затирание с стратегией
-	op, err := ExecuteWipeWithStrategy(ctx, disk, wipeConfig, logger, mode, profile)
-	if err != nil {
-		if op == nil {
-			op = &WipeOperation{
-				ID:        fmt.Sprintf("strategy_%d", time.Now().UnixNano()),
-				Disk:      disk.Letter,
-				Method:    string(mode),
-				Status:    "FAILED",
-				StartTime: time.Now(),
-				Error:     err.Error(),
-			}
-		}
-		now := time.Now()
-		op.EndTime = &now
+	if mode == ModeCipher {
+		wipeConfig.Method = MethodCipher
 	}
-
-	return op
+	if m, err := ValidateMethod(string(wipeConfig.Method)); err == nil {
+		wipeConfig.Method = m // Каноническое имя вместо псевдонима
+	}
+	return wipeConfig
 }
 
-// getPassesForMode возвращает количество проходов для режима
-func getPassesForMode(cfg *config.Config, mode WipeMode) int {
+// getPassesForMode возвращает число проходов для режима
+func getPassesForMode(cfg *config.Config, mode WipeMode, diskType string) int {
 	switch mode {
 	case ModeCipher:
-		return 3 // Всегда 3 прохода для cipher
+		return 1 // Схема cipher уже содержит 3 прохода
 	case ModeSDelete:
 		return 1 // SDelete использует 1 проход
 	case ModeStandard:
 		fallthrough
 	default:
-		// Use SSD passes as base value for standard mode
-		return cfg.Wipe.SSDPasses
+		if diskType == "SSD" {
+			return cfg.Wipe.SSDPasses
+		}
+		return cfg.Wipe.HDDPasses
 	}
 }
 
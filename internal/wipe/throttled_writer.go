@@ -1,6 +1,7 @@
 package wipe
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
@@ -9,23 +10,54 @@ import (
 
 // ThrottledWriter ограничивает скорость записи (thread-safe)
 type ThrottledWriter struct {
-	file         *os.File
-	maxSpeedMBps float64
-	lastWrite    time.Time
-	mu           sync.RWMutex
-	closed       bool
+	file    *os.File
+	limiter *RateLimiter
+	ctx     context.Context
+	mu      sync.RWMutex
+	closed  bool
+	pending uint64 // Байт с последнего Sync
+
+	// Прямой ввод-вывод: логическая длина файла и признак записанного хвоста
+	direct bool
+	offset int64
+	tail   bool
 }
 
-// NewThrottledWriter создает новый throttled writer
+// NewThrottledWriter создает writer с собственным лимитом скорости
 func NewThrottledWriter(file *os.File, maxSpeedMBps float64) *ThrottledWriter {
+	return NewLimitedWriter(context.Background(), file, NewRateLimiter(maxSpeedMBps, nil))
+}
+
+// NewLimitedWriter создает writer с общим лимитером (диска или процесса).
+// Отмена ctx прерывает ожидание лимита.
+func NewLimitedWriter(ctx context.Context, file *os.File, limiter *RateLimiter) *ThrottledWriter {
 	return &ThrottledWriter{
-		file:         file,
-		maxSpeedMBps: maxSpeedMBps,
-		lastWrite:    time.Now(),
-		closed:       false,
+		file:    file,
+		limiter: limiter,
+		ctx:     ctx,
+		closed:  false,
 	}
 }
 
+// SetDirectIO включает запись по правилам прямого ввода-вывода (файл открыт
+// через openWipeFile с direct): невыровненный последний блок дописывается с
+// дополнением до сектора и обрезкой файла
+func (tw *ThrottledWriter) SetDirectIO(direct bool) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.direct = direct
+}
+
+// Reopen продолжает запись в снова открытый после паузы файл; логическая
+// длина файла сохраняется
+func (tw *ThrottledWriter) Reopen(file *os.File, direct bool) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.file = file
+	tw.direct = direct
+	tw.closed = false
+}
+
 // Write записывает данные с ограничением скорости (thread-safe)
 func (tw *ThrottledWriter) Write(data []byte) (int, error) {
 	if tw.closed {
@@ -39,20 +71,24 @@ func (tw *ThrottledWriter) Write(data []byte) (int, error) {
 	tw.mu.Lock()
 	defer tw.mu.Unlock()
 
-	now := time.Now()
-	if tw.maxSpeedMBps > 0 {
-		bytesPerSec := tw.maxSpeedMBps * 1024 * 1024
-		if bytesPerSec > 0 {
-			expected := time.Duration(float64(len(data)) / bytesPerSec * float64(time.Second))
-			actual := now.Sub(tw.lastWrite)
-			if actual < expected {
-				time.Sleep(expected - actual)
-			}
-		}
+	if err := tw.limiter.WaitN(tw.ctx, len(data)); err != nil {
+		return 0, err
 	}
 
-	n, err := tw.file.Write(data)
-	tw.lastWrite = time.Now()
+	start := time.Now()
+	var n int
+	var err error
+	if tw.direct {
+		if tw.tail {
+			return 0, errDirectIOTail
+		}
+		n, tw.tail, err = writeDirect(tw.file, data, tw.offset)
+	} else {
+		n, err = tw.file.Write(data)
+	}
+	tw.limiter.ObserveWrite(n, time.Since(start))
+	tw.pending += uint64(n)
+	tw.offset += int64(n)
 	return n, err
 }
 
@@ -65,7 +101,11 @@ func (tw *ThrottledWriter) Sync() error {
 		return io.ErrClosedPipe
 	}
 
-	return tw.file.Sync()
+	start := time.Now()
+	err := tw.file.Sync()
+	tw.limiter.ObserveSync(tw.pending, time.Since(start))
+	tw.pending = 0
+	return err
 }
 
 // Close закрывает файл
@@ -0,0 +1,133 @@
+package wipe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"wipedisk_enterprise/internal/logging"
+	"wipedisk_enterprise/internal/system"
+)
+
+// errDiscardUnsupported - файловая система не умеет освобождать диапазоны файла
+var errDiscardUnsupported = errors.New("освобождение блоков файла не поддерживается")
+
+// TrimStats итоги TRIM после затирания SSD
+type TrimStats struct {
+	FilesDiscarded     int    `json:"files_discarded"`               // Файлы затирания, блоки которых освобождены перед удалением
+	BytesDiscarded     uint64 `json:"bytes_discarded"`               // Их объем
+	DiscardUnsupported bool   `json:"discard_unsupported,omitempty"` // Файловая система не освобождает диапазоны файла
+	VolumeTrimmed      bool   `json:"volume_trimmed"`                // TRIM свободного места тома выполнен
+	BytesTrimmed       uint64 `json:"bytes_trimmed,omitempty"`       // Объем, о котором сообщила файловая система
+	Unsupported        bool   `json:"unsupported,omitempty"`         // Том или устройство не поддерживает TRIM
+	Error              string `json:"error,omitempty"`
+}
+
+// discarder освобождает блоки файлов затирания перед удалением (punch hole),
+// чтобы файловая система передала их устройству как discard. Методы
+// безопасны для nil: без discarder файлы просто удаляются.
+type discarder struct {
+	logger *logging.EnterpriseLogger
+
+	mu    sync.Mutex
+	stats TrimStats
+}
+
+// newDiscarder создает discarder; nil, если TRIM выключен
+func newDiscarder(enabled bool, logger *logging.EnterpriseLogger) *discarder {
+	if !enabled {
+		return nil
+	}
+	return &discarder{logger: logger}
+}
+
+// remove освобождает блоки файла и удаляет его
+func (d *discarder) remove(path string) error {
+	if d != nil {
+		d.discard(path)
+	}
+	return os.Remove(path)
+}
+
+// discard освобождает все блоки файла, сохраняя его длину
+func (d *discarder) discard(path string) {
+	d.mu.Lock()
+	unsupported := d.stats.DiscardUnsupported
+	d.mu.Unlock()
+	if unsupported {
+		return
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return // Ошибку сообщит удаление
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err == nil {
+		err = punchHole(file, info.Size())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case err == nil:
+		d.stats.FilesDiscarded++
+		d.stats.BytesDiscarded += uint64(info.Size())
+	case errors.Is(err, errDiscardUnsupported):
+		d.stats.DiscardUnsupported = true
+		d.logger.Log("INFO", "Файловая система не освобождает блоки файлов, останется только TRIM тома", "file", path)
+	default:
+		d.logger.Log("WARN", "Ошибка освобождения блоков файла", "file", path, "error", err.Error())
+	}
+}
+
+// snapshot возвращает копию накопленных итогов
+func (d *discarder) snapshot() TrimStats {
+	if d == nil {
+		return TrimStats{}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// discardDir освобождает блоки всех файлов каталога затирания
+func (d *discarder) discardDir(dir string) {
+	if d == nil {
+		return
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			d.discard(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// performTrim выполняет TRIM свободного места тома и возвращает итоги вместе
+// с итогами освобождения файлов. Отсутствие поддержки TRIM не считается
+// ошибкой затирания.
+func performTrim(ctx context.Context, drive string, d *discarder, logger *logging.EnterpriseLogger) *TrimStats {
+	stats := d.snapshot()
+	logger.Log("INFO", "Выполнение TRIM", "drive", drive, "files_discarded", stats.FilesDiscarded, "bytes_discarded", stats.BytesDiscarded)
+
+	result, err := system.TrimVolume(ctx, drive)
+	switch {
+	case err == nil:
+		stats.VolumeTrimmed = true
+		stats.BytesTrimmed = result.BytesTrimmed
+		logger.Log("INFO", "TRIM выполнен", "drive", drive, "bytes_trimmed", result.BytesTrimmed, "duration", result.Duration.String())
+	case errors.Is(err, system.ErrTrimUnsupported):
+		stats.Unsupported = true
+		stats.Error = err.Error()
+		logger.Log("WARN", "TRIM не поддерживается", "drive", drive, "error", err.Error())
+	default:
+		stats.Error = err.Error()
+		logger.Log("WARN", "Ошибка TRIM", "drive", drive, "error", err.Error())
+	}
+	return &stats
+}
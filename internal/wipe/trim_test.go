@@ -0,0 +1,66 @@
+package wipe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscarder(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		sizes     []int // Размеры файлов каталога затирания
+		wantFiles int
+	}{
+		{"TRIM выключен", false, []int{4096, 8192}, 0},
+		{"файлы", true, []int{4096, 8192}, 2},
+		{"пустой файл", true, []int{0}, 1},
+		{"пустой каталог", true, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var wantBytes uint64
+			for i, size := range tt.sizes {
+				path := filepath.Join(dir, "wipe_"+string(rune('a'+i))+".tmp")
+				if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+					t.Fatal(err)
+				}
+				wantBytes += uint64(size)
+			}
+
+			d := newDiscarder(tt.enabled, newTestLogger(t))
+			if (d != nil) != tt.enabled {
+				t.Fatalf("discarder %v при enabled=%v", d, tt.enabled)
+			}
+			d.discardDir(dir)
+			stats := d.snapshot()
+			if stats.DiscardUnsupported {
+				t.Skip("файловая система не освобождает блоки файлов")
+			}
+			if !tt.enabled {
+				wantBytes = 0
+			}
+			if stats.FilesDiscarded != tt.wantFiles || stats.BytesDiscarded != wantBytes {
+				t.Errorf("освобождено %d файлов, %d байт; want %d и %d", stats.FilesDiscarded, stats.BytesDiscarded, tt.wantFiles, wantBytes)
+			}
+
+			// Длина файла сохраняется, пока его не удалит remove
+			entries, _ := os.ReadDir(dir)
+			for i, e := range entries {
+				path := filepath.Join(dir, e.Name())
+				if info, err := os.Stat(path); err != nil || info.Size() != int64(tt.sizes[i]) {
+					t.Errorf("%s: размер изменился (%v)", e.Name(), err)
+				}
+				if err := d.remove(path); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("%s не удален", e.Name())
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 type WipeOperation struct {
 	ID         string
 	Disk       string
+	Path       string // Каталог затирания внутри тома Disk (пусто - том целиком)
 	Method     string
 	Passes     int
 	ChunkSize  int64
@@ -17,6 +18,65 @@ type WipeOperation struct {
 	SpeedMBps  float64
 	Error      string
 	Warning    string
+
+	PassRecords []PassRecord // Журнал выполненных проходов
+
+	RunID    string       // Идентификатор запуска в журнале контрольных точек
+	Segments []RunSegment // Запуски, за которые выполнена операция (больше одного после --resume)
+
+	ActiveIntervals []ActiveInterval // Интервалы работы за вычетом пауз и времени вне окон обслуживания
+
+	Verification *VerifyStats // Итоги проверки чтением (nil - проверка выключена)
+	Trim         *TrimStats   // Итоги TRIM после затирания SSD (nil - TRIM не выполнялся)
+}
+
+// Фазы проходов в PassRecord
+const (
+	PhaseOverwrite     = "overwrite"
+	PhaseMetadataScrub = "metadata_scrub"
+)
+
+// PassRecord описывает один выполненный проход операции затирания
+type PassRecord struct {
+	Number       int       `json:"number"`  // Порядковый номер прохода, начиная с 1
+	Phase        string    `json:"phase"`   // overwrite, metadata_scrub
+	Pattern      string    `json:"pattern"` // Описание паттерна (0x00, random, 0x92 0x49 0x24)
+	BytesWritten uint64    `json:"bytes_written"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Status       string    `json:"status"` // COMPLETED, PARTIAL, CANCELLED, FAILED
+	Error        string    `json:"error,omitempty"`
+	Files        int       `json:"files,omitempty"` // Число созданных файлов
+}
+
+// Target возвращает цель операции для вывода: каталог или том
+func (op *WipeOperation) Target() string {
+	if op.Path != "" {
+		return op.Path
+	}
+	return op.Disk
+}
+
+// beginPass добавляет запись о начале прохода и возвращает указатель на нее
+func (op *WipeOperation) beginPass(phase, pattern string) *PassRecord {
+	op.PassRecords = append(op.PassRecords, PassRecord{
+		Number:  len(op.PassRecords) + 1,
+		Phase:   phase,
+		Pattern: pattern,
+		Start:   time.Now(),
+		Status:  "RUNNING",
+	})
+	return &op.PassRecords[len(op.PassRecords)-1]
+}
+
+// finish завершает запись прохода
+func (r *PassRecord) finish(written uint64, status string, err error) {
+	r.BytesWritten = written
+	r.End = time.Now()
+	r.Status = status
+	if err != nil {
+		r.Error = err.Error()
+	}
 }
 
 // SystemDiskPolicy определяет политику безопасности для системного диска
@@ -39,6 +99,14 @@ type ProgressInfo struct {
 	Done          bool
 	StartTime     time.Time
 	EstimatedTime time.Duration
+
+	Disk       string
+	Pass       int    // Номер текущего прохода, с 1
+	Passes     int    // Число проходов операции
+	PassBytes  uint64 // Записано в текущем проходе
+	PassTarget uint64 // Ожидаемый объем прохода
+	TotalBytes uint64 // Ожидаемый объем всей операции
+	Paused     bool   // Затирание приостановлено
 }
 
 // WipeResult результат операции затирания
@@ -50,4 +118,6 @@ type WipeResult struct {
 	FilesCreated int
 	Error        error
 	Cancelled    bool
+	Verification *VerifyStats // Итоги проверки чтением (nil - проверка выключена)
+	Trim         *TrimStats   // Итоги TRIM (nil - TRIM не выполнялся)
 }
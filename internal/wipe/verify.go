@@ -0,0 +1,261 @@
+package wipe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+// verifyBlockSize - размер блока проверки чтением
+const verifyBlockSize = 1024 * 1024
+
+// maxVerifyDetails - сколько несовпадений описывается в отчете поименно
+const maxVerifyDetails = 20
+
+// VerifyStats итоги проверки записанных данных чтением
+type VerifyStats struct {
+	SampleRate     float64  `json:"sample_rate"`
+	Files          int      `json:"files"`
+	BlocksChecked  int      `json:"blocks_checked"`
+	BytesChecked   uint64   `json:"bytes_checked"`
+	MismatchBlocks int      `json:"mismatch_blocks"`
+	MismatchBytes  uint64   `json:"mismatch_bytes"`
+	ReadErrors     int      `json:"read_errors"`
+	Details        []string `json:"details,omitempty"`
+}
+
+// Verifier перечитывает файлы затирания (целиком или выборку блоков) и
+// сравнивает их с ожидаемыми данными прохода. Случайные данные берутся из
+// того же потока AES-CTR по смещению, паттерны генерируются заново.
+// Чтение идет в обход кэша ОС, если это возможно, иначе проверялся бы кэш.
+type Verifier struct {
+	sampleRate float64
+	markedOnly bool            // Проверяются только проходы с Verify (wipe.verify выключен)
+	marker     *ArtifactMarker // Ключ для проверки заголовков-маркеров (nil - без ключа)
+	logger     *logging.EnterpriseLogger
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stats VerifyStats
+}
+
+// NewVerifier создает проверку по настройкам; nil, если проверка выключена.
+// marker проверяет заголовки файлов затирания (nil - файлов без маркера).
+func NewVerifier(settings config.VerifyConfig, marker *ArtifactMarker, logger *logging.EnterpriseLogger) *Verifier {
+	if !settings.Enabled {
+		return nil
+	}
+
+	rate := settings.SampleRate
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	return &Verifier{
+		sampleRate: rate,
+		marker:     marker,
+		logger:     logger,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:      VerifyStats{SampleRate: rate},
+	}
+}
+
+// passVerifier возвращает v или, если проверка выключена, а в схеме есть
+// проходы с Verify, проверку только этих проходов
+func passVerifier(v *Verifier, scheme *PatternScheme, marker *ArtifactMarker, logger *logging.EnterpriseLogger) *Verifier {
+	if v != nil || !scheme.hasVerifyPass() {
+		return v
+	}
+	v = NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 1}, marker, logger)
+	v.markedOnly = true
+	return v
+}
+
+// fullVerifyFiller - источник данных прохода с Verify: такой проход
+// перечитывается целиком, а не по выборке
+type fullVerifyFiller struct {
+	PassFiller
+}
+
+// Stats возвращает копию накопленных итогов (nil без проверки)
+func (v *Verifier) Stats() *VerifyStats {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	stats := v.stats
+	stats.Details = append([]string(nil), v.stats.Details...)
+	return &stats
+}
+
+// VerifyFile проверяет первые size байт файла. Ожидаемые данные смещения o
+// файла - filler.FillAt(..., baseOffset+o). Если файл начинается с
+// заголовка-маркера, заголовок проверяется по подписи. Несовпадения только
+// учитываются; ошибка возвращается лишь при отмене.
+func (v *Verifier) VerifyFile(ctx context.Context, filename string, size uint64, filler PassFiller, baseOffset uint64) error {
+	return v.verifyRange(ctx, filename, 0, size, filler, baseOffset)
+}
+
+// VerifyRange проверяет size байт устройства или образа начиная со смещения
+// start. Ожидаемые данные смещения start+o - filler.FillAt(..., o).
+func (v *Verifier) VerifyRange(ctx context.Context, filename string, start, size uint64, filler PassFiller) error {
+	return v.verifyRange(ctx, filename, start, size, filler, 0)
+}
+
+func (v *Verifier) verifyRange(ctx context.Context, filename string, start, size uint64, filler PassFiller, baseOffset uint64) error {
+	if v == nil || size == 0 {
+		return nil
+	}
+	rate := v.sampleRate
+	if _, full := filler.(fullVerifyFiller); full {
+		rate = 1
+	} else if v.markedOnly {
+		return nil
+	}
+
+	var file *os.File
+	var err error
+	if start%DirectIOAlignment == 0 {
+		file, err = openVerifyFile(filename)
+	} else {
+		file, err = os.Open(filename) // Прямое чтение требует выровненных смещений
+	}
+	if err != nil {
+		v.fail(filename, 0, 0, true, fmt.Sprintf("ошибка открытия: %v", err))
+		return nil
+	}
+	defer file.Close()
+
+	readBuf := GetBuffer(alignUp(verifyBlockSize))
+	defer PutBuffer(readBuf)
+	expected := GetBuffer(verifyBlockSize)
+	defer PutBuffer(expected)
+
+	blocks := (size + verifyBlockSize - 1) / verifyBlockSize
+	var checked int
+	var checkedBytes, mismatchBytes uint64
+	var mismatches int
+	for block := uint64(0); block < blocks; block++ {
+		// Первый и последний блоки проверяются всегда, остальные - по выборке
+		if block != 0 && block != blocks-1 && !v.sample(rate) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		offset := block * verifyBlockSize
+		length := int(min(uint64(verifyBlockSize), size-offset))
+
+		n, err := file.ReadAt(readBuf[:alignUp(length)], int64(start+offset))
+		if n < length {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			v.fail(filename, start+offset, uint64(length), true, fmt.Sprintf("ошибка чтения: %v", err))
+			continue
+		}
+
+		want := expected[:length]
+		if err := filler.FillAt(want, baseOffset+offset); err != nil {
+			return fmt.Errorf("ошибка генерации ожидаемых данных: %w", err)
+		}
+
+		got := readBuf[:length]
+		from := 0
+		if start+offset == 0 && bytes.HasPrefix(got, []byte(markerMagic)) {
+			// Заголовок-маркер не совпадает с паттерном: сверяем его подпись
+			from = min(MarkerSize, length)
+			if v.marker == nil {
+				v.fail(filename, 0, uint64(from), false, "заголовок-маркер: нет ключа подписи")
+			} else if _, err := v.marker.Verify(filename); err != nil {
+				v.fail(filename, 0, uint64(from), false, fmt.Sprintf("заголовок-маркер: %v", err))
+			}
+		}
+		checked++
+		checkedBytes += uint64(length)
+		if !bytes.Equal(got[from:], want[from:]) {
+			diff := countDiff(got[from:], want[from:])
+			mismatches++
+			mismatchBytes += diff
+			v.fail(filename, start+offset, diff, false, fmt.Sprintf("%d байт не совпадает", diff))
+		}
+	}
+
+	v.mu.Lock()
+	v.stats.Files++
+	v.stats.BlocksChecked += checked
+	v.stats.BytesChecked += checkedBytes
+	v.mu.Unlock()
+
+	level := "DEBUG"
+	if mismatches > 0 {
+		level = "WARN"
+	}
+	v.logger.Log(level, "Проверка файла чтением", "file", filename, "blocks", checked, "of", blocks,
+		"bytes_checked", checkedBytes, "mismatch_blocks", mismatches, "mismatch_bytes", mismatchBytes)
+	return nil
+}
+
+// sample решает, проверять ли очередной блок при доле выборки rate
+func (v *Verifier) sample(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.rng.Float64() < rate
+}
+
+// fail учитывает несовпадение или ошибку чтения блока
+func (v *Verifier) fail(filename string, offset, n uint64, readErr bool, reason string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if readErr {
+		v.stats.ReadErrors++
+	} else {
+		v.stats.MismatchBlocks++
+		v.stats.MismatchBytes += n
+	}
+	if len(v.stats.Details) < maxVerifyDetails {
+		v.stats.Details = append(v.stats.Details, fmt.Sprintf("%s@%d: %s", filename, offset, reason))
+	}
+}
+
+// countDiff считает различающиеся байты
+func countDiff(a, b []byte) uint64 {
+	var n uint64
+	for i := range a {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// recordVerification переносит итоги проверки чтением в операцию;
+// несовпадения отмечаются предупреждением
+func (op *WipeOperation) recordVerification(v *Verifier) {
+	stats := v.Stats()
+	if stats == nil {
+		return
+	}
+	op.Verification = stats
+	if (stats.MismatchBlocks > 0 || stats.ReadErrors > 0) && op.Warning == "" {
+		op.Warning = fmt.Sprintf("Проверка чтением: %d блоков не совпало, %d ошибок чтения",
+			stats.MismatchBlocks, stats.ReadErrors)
+	}
+}
@@ -0,0 +1,154 @@
+package wipe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"wipedisk_enterprise/internal/config"
+)
+
+func TestVerifierVerifyFile(t *testing.T) {
+	m, err := LoadArtifactMarker(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := LoadArtifactMarker(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := newTestLogger(t)
+
+	const size = 3*verifyBlockSize + 100
+	tests := []struct {
+		name         string
+		header       *ArtifactMarker // Ключ заголовка файла (nil - без маркера)
+		key          *ArtifactMarker // Ключ проверки
+		corrupt      int64           // Смещение испорченного байта (-1 - нет)
+		truncate     int64           // Длина файла на диске (0 - без обрезки)
+		wantMismatch int
+		wantReadErr  int
+	}{
+		{"без изменений", m, m, -1, 0, 0, 0},
+		{"без маркера", nil, m, -1, 0, 0, 0},
+		{"испорченный байт", m, m, verifyBlockSize + 5, 0, 1, 0},
+		{"испорченный хвост", m, m, size - 1, 0, 1, 0},
+		{"чужая подпись", other, m, -1, 0, 1, 0},
+		{"маркер без ключа", m, nil, -1, 0, 1, 0},
+		{"файл короче записанного", m, m, -1, 2 * verifyBlockSize, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filler, err := RepeatPass(1, 2, 3).NewFiller()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := make([]byte, size)
+			if err := filler.FillAt(data, 0); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "wipe_001.tmp")
+			tt.header.mark(data, path)
+			if tt.corrupt >= 0 {
+				data[tt.corrupt] ^= 0xFF
+			}
+			if tt.truncate > 0 {
+				data = data[:tt.truncate]
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			v := NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 1}, tt.key, logger)
+			if err := v.VerifyFile(context.Background(), path, size, filler, 0); err != nil {
+				t.Fatal(err)
+			}
+			stats := v.Stats()
+			if stats.MismatchBlocks != tt.wantMismatch || stats.ReadErrors != tt.wantReadErr {
+				t.Errorf("несовпадений %d, ошибок чтения %d, want %d и %d: %v",
+					stats.MismatchBlocks, stats.ReadErrors, tt.wantMismatch, tt.wantReadErr, stats.Details)
+			}
+		})
+	}
+}
+
+func TestNewVerifierDisabled(t *testing.T) {
+	v := NewVerifier(config.VerifyConfig{Enabled: false, SampleRate: 1}, nil, newTestLogger(t))
+	if v != nil {
+		t.Fatal("выключенная проверка должна быть nil")
+	}
+	if err := v.VerifyFile(context.Background(), "missing", 1, nil, 0); err != nil || v.Stats() != nil {
+		t.Errorf("nil-проверка: ошибка %v, итоги %v", err, v.Stats())
+	}
+}
+
+func TestPassVerifier(t *testing.T) {
+	const blocks = 8
+	logger := newTestLogger(t)
+	plain := &PatternScheme{Passes: []PassSpec{FixedPass(0)}}
+	marked := &PatternScheme{Passes: []PassSpec{FixedPass(0), {Kind: PassRandom, Verify: true}}}
+	sampled := func() *Verifier {
+		return NewVerifier(config.VerifyConfig{Enabled: true, SampleRate: 0.000001}, nil, logger)
+	}
+
+	tests := []struct {
+		name       string
+		verifier   *Verifier
+		scheme     *PatternScheme
+		wantNil    bool
+		wantPlain  int // Проверено блоков обычного прохода
+		wantVerify int // Проверено блоков прохода с Verify
+	}{
+		{"проверка выключена, без Verify", nil, plain, true, 0, 0},
+		{"проверка выключена, проход с Verify", nil, marked, false, 0, blocks},
+		{"выборка, проход с Verify", sampled(), marked, false, 2, blocks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := passVerifier(tt.verifier, tt.scheme, nil, logger)
+			if (v == nil) != tt.wantNil {
+				t.Fatalf("проверка %v, want nil: %v", v, tt.wantNil)
+			}
+			if v == nil {
+				return
+			}
+			if tt.verifier != nil && v != tt.verifier {
+				t.Error("включенная проверка заменена")
+			}
+
+			for _, spec := range []PassSpec{FixedPass(0), {Kind: PassRandom, Verify: true}} {
+				filler, err := spec.NewFiller()
+				if err != nil {
+					t.Fatal(err)
+				}
+				data := make([]byte, blocks*verifyBlockSize)
+				if err := filler.FillAt(data, 0); err != nil {
+					t.Fatal(err)
+				}
+				path := filepath.Join(t.TempDir(), "wipe_001.tmp")
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				before := v.Stats().BlocksChecked
+				if err := v.VerifyFile(context.Background(), path, uint64(len(data)), filler, 0); err != nil {
+					t.Fatal(err)
+				}
+				checked := v.Stats().BlocksChecked - before
+				want := tt.wantPlain
+				if spec.Verify {
+					want = tt.wantVerify
+				}
+				if checked != want {
+					t.Errorf("проход %s: проверено блоков %d, want %d", spec, checked, want)
+				}
+			}
+			if stats := v.Stats(); stats.MismatchBlocks != 0 || stats.ReadErrors != 0 {
+				t.Errorf("итоги проверки: %+v", stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package wipe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+	"wipedisk_enterprise/internal/logging"
+)
+
+// scheduleRecheck - наибольший интервал между проверками расписания: часы
+// могут быть переведены, а система - уйти в сон
+const scheduleRecheck = time.Minute
+
+// WipeSchedule окна обслуживания, в которые разрешено затирание
+type WipeSchedule struct {
+	windows []scheduleWindow
+}
+
+// scheduleWindow разобранное окно обслуживания
+type scheduleWindow struct {
+	days     [7]bool       // Дни начала окна
+	from, to time.Duration // Смещение от полуночи
+}
+
+// scheduleSpan одно вхождение окна во времени
+type scheduleSpan struct {
+	start, end time.Time
+}
+
+// NewWipeSchedule разбирает окна обслуживания; nil - расписание выключено
+func NewWipeSchedule(cfg config.ScheduleConfig) (*WipeSchedule, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if len(cfg.Windows) == 0 {
+		return nil, fmt.Errorf("расписание включено, но окна не заданы")
+	}
+
+	s := &WipeSchedule{}
+	for i, w := range cfg.Windows {
+		days, err := w.Weekdays()
+		if err != nil {
+			return nil, fmt.Errorf("окно %d: %w", i+1, err)
+		}
+		from, to, err := w.Bounds()
+		if err != nil {
+			return nil, fmt.Errorf("окно %d: %w", i+1, err)
+		}
+
+		window := scheduleWindow{from: from, to: to}
+		for _, d := range days {
+			window.days[d] = true
+		}
+		if len(days) == 0 {
+			window.days = [7]bool{true, true, true, true, true, true, true}
+		}
+		s.windows = append(s.windows, window)
+	}
+	return s, nil
+}
+
+// State сообщает, открыто ли окно в момент t, и когда это изменится:
+// конец текущего окна или начало следующего. Смежные и перекрывающиеся
+// окна считаются одним.
+func (s *WipeSchedule) State(t time.Time) (open bool, change time.Time) {
+	spans := s.spans(t)
+	for _, span := range spans {
+		if t.Before(span.start) {
+			return false, span.start
+		}
+		if t.Before(span.end) {
+			return true, span.end
+		}
+	}
+	// Окна заданы, но ни один день недели не выбран - недостижимо после валидации
+	return false, t.Add(scheduleRecheck)
+}
+
+// spans возвращает слитые вхождения окон, которые начинаются от суток до t
+// и до недели после t, в порядке времени
+func (s *WipeSchedule) spans(t time.Time) []scheduleSpan {
+	var spans []scheduleSpan
+	y, m, d := t.Date()
+	for offset := -1; offset <= 7; offset++ {
+		midnight := time.Date(y, m, d+offset, 0, 0, 0, 0, t.Location())
+		for _, w := range s.windows {
+			if !w.days[midnight.Weekday()] {
+				continue
+			}
+			start := midnight.Add(w.from)
+			end := midnight.Add(w.to)
+			if w.to <= w.from {
+				end = time.Date(y, m, d+offset+1, 0, 0, 0, 0, t.Location()).Add(w.to)
+			}
+			spans = insertSpan(spans, scheduleSpan{start: start, end: end})
+		}
+	}
+	return spans
+}
+
+// insertSpan добавляет вхождение в упорядоченный список, сливая его с
+// перекрывающимися и смежными
+func insertSpan(spans []scheduleSpan, span scheduleSpan) []scheduleSpan {
+	merged := make([]scheduleSpan, 0, len(spans)+1)
+	i := 0
+	for ; i < len(spans) && spans[i].end.Before(span.start); i++ {
+		merged = append(merged, spans[i])
+	}
+	for ; i < len(spans) && !spans[i].start.After(span.end); i++ {
+		if spans[i].start.Before(span.start) {
+			span.start = spans[i].start
+		}
+		if spans[i].end.After(span.end) {
+			span.end = spans[i].end
+		}
+	}
+	merged = append(merged, span)
+	return append(merged, spans[i:]...)
+}
+
+// Start приостанавливает затирание через pause вне окон обслуживания, пока
+// не отменен ctx. Состояние на текущий момент применяется до возврата, так
+// что запись не начнется вне окна; при отмене ctx пауза по расписанию
+// снимается. У nil-расписания ничего не делает.
+func (s *WipeSchedule) Start(ctx context.Context, pause *PauseController, logger *logging.EnterpriseLogger) {
+	if s == nil || pause == nil {
+		return
+	}
+
+	apply := func() time.Time {
+		now := time.Now()
+		open, change := s.State(now)
+		if open {
+			if pause.release(pauseSchedule) {
+				logger.Log("INFO", "Окно обслуживания открыто, затирание продолжается", "until", change.Format(time.RFC3339))
+			}
+		} else if pause.hold(pauseSchedule) {
+			logger.Log("INFO", "Вне окна обслуживания, затирание приостановлено", "resume_at", change.Format(time.RFC3339))
+		}
+		return change
+	}
+
+	change := apply()
+	go func() {
+		defer pause.release(pauseSchedule)
+		for {
+			timer := time.NewTimer(min(time.Until(change), scheduleRecheck))
+			select {
+			case <-timer.C:
+				change = apply()
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,79 @@
+package wipe
+
+import (
+	"testing"
+	"time"
+
+	"wipedisk_enterprise/internal/config"
+)
+
+// at - момент недели 12-18 октября 2026 (пн-вс) в UTC; 16.10 - пятница
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestWipeScheduleState(t *testing.T) {
+	overnight := []config.ScheduleWindow{{From: "20:00", To: "07:00"}}
+	fridayNight := []config.ScheduleWindow{{Days: []string{"fri"}, From: "22:00", To: "06:00"}}
+	adjacent := []config.ScheduleWindow{{From: "20:00", To: "24:00"}, {From: "00:00", To: "07:00"}}
+	weekend := []config.ScheduleWindow{{Days: []string{"Sat"}, From: "08:00", To: "24:00"}, {Days: []string{"sun"}, From: "00:00", To: "06:00"}}
+
+	tests := []struct {
+		name       string
+		windows    []config.ScheduleWindow
+		now        time.Time
+		wantOpen   bool
+		wantChange time.Time
+	}{
+		{"днем до ночного окна", overnight, at(16, 12, 0), false, at(16, 20, 0)},
+		{"ночное окно до полуночи", overnight, at(16, 21, 0), true, at(17, 7, 0)},
+		{"ночное окно после полуночи", overnight, at(17, 6, 59), true, at(17, 7, 0)},
+		{"на границе закрытия", overnight, at(17, 7, 0), false, at(17, 20, 0)},
+		{"на границе открытия", overnight, at(16, 20, 0), true, at(17, 7, 0)},
+		{"окно пятницы в субботу утром", fridayNight, at(17, 5, 0), true, at(17, 6, 0)},
+		{"окно пятницы закрыто в субботу", fridayNight, at(17, 23, 0), false, at(23, 22, 0)},
+		{"окно пятницы еще не открыто", fridayNight, at(15, 23, 0), false, at(16, 22, 0)},
+		{"смежные окна сливаются", adjacent, at(16, 23, 0), true, at(17, 7, 0)},
+		{"24:00 смыкается со следующим днем", weekend, at(17, 23, 0), true, at(18, 6, 0)},
+		{"после окон выходных", weekend, at(18, 7, 0), false, at(24, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewWipeSchedule(config.ScheduleConfig{Enabled: true, Windows: tt.windows})
+			if err != nil {
+				t.Fatal(err)
+			}
+			open, change := s.State(tt.now)
+			if open != tt.wantOpen || !change.Equal(tt.wantChange) {
+				t.Errorf("State(%s) = %v, %s; want %v, %s", tt.now.Format("Mon 15:04"), open, change.Format("Mon 02 15:04"),
+					tt.wantOpen, tt.wantChange.Format("Mon 02 15:04"))
+			}
+		})
+	}
+}
+
+func TestNewWipeScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ScheduleConfig
+		wantNil bool
+		wantErr bool
+	}{
+		{"выключено", config.ScheduleConfig{Windows: []config.ScheduleWindow{{From: "bad"}}}, true, false},
+		{"без окон", config.ScheduleConfig{Enabled: true}, true, true},
+		{"неизвестный день", config.ScheduleConfig{Enabled: true, Windows: []config.ScheduleWindow{{Days: []string{"fry"}, From: "20:00", To: "07:00"}}}, true, true},
+		{"неверное время", config.ScheduleConfig{Enabled: true, Windows: []config.ScheduleWindow{{From: "8:00", To: "07:00"}}}, true, true},
+		{"начало в 24:00", config.ScheduleConfig{Enabled: true, Windows: []config.ScheduleWindow{{From: "24:00", To: "07:00"}}}, true, true},
+		{"корректное окно", config.ScheduleConfig{Enabled: true, Windows: []config.ScheduleWindow{{From: "20:00", To: "24:00"}}}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewWipeSchedule(tt.cfg)
+			if (err != nil) != tt.wantErr || (s == nil) != tt.wantNil {
+				t.Errorf("NewWipeSchedule: расписание %v, ошибка %v", s, err)
+			}
+		})
+	}
+}